@@ -0,0 +1,86 @@
+package flac
+
+import (
+	"iter"
+)
+
+// Blocks returns an iterator over all metadata blocks in file order, starting with the stream info block.
+func (flac *FLAC) Blocks() iter.Seq[IFLACMetadataBlock] {
+	return func(yield func(IFLACMetadataBlock) bool) {
+		if flac.StreamInfo != nil && !yield(flac.StreamInfo) {
+			return
+		}
+
+		for _, block := range flac.MetadataBlocks {
+			if !yield(block) {
+				return
+			}
+		}
+	}
+}
+
+// BlocksOfType returns an iterator over the metadata blocks of the given type in file order.
+func (flac *FLAC) BlocksOfType(blockType BlockType) iter.Seq[IFLACMetadataBlock] {
+	return func(yield func(IFLACMetadataBlock) bool) {
+		for block := range flac.Blocks() {
+			if block.header().Type == blockType && !yield(block) {
+				return
+			}
+		}
+	}
+}
+
+// VorbisComment returns the vorbis comment block, or nil if there is none.
+func (flac *FLAC) VorbisComment() *FLACMetadataBlockVorbisComment {
+	for _, iBlock := range flac.MetadataBlocks {
+		if block, ok := iBlock.(*FLACMetadataBlockVorbisComment); ok {
+			return block
+		}
+	}
+
+	return nil
+}
+
+// SeekTable returns the seek table block, or nil if there is none.
+func (flac *FLAC) SeekTable() *FLACMetadataBlockSeekTable {
+	for _, iBlock := range flac.MetadataBlocks {
+		if block, ok := iBlock.(*FLACMetadataBlockSeekTable); ok {
+			return block
+		}
+	}
+
+	return nil
+}
+
+// CueSheet returns the first cue sheet block, or nil if there is none.
+func (flac *FLAC) CueSheet() *FLACMetadataBlockCueSheet {
+	for _, iBlock := range flac.MetadataBlocks {
+		if block, ok := iBlock.(*FLACMetadataBlockCueSheet); ok {
+			return block
+		}
+	}
+
+	return nil
+}
+
+// Pictures returns all picture blocks in file order.
+func (flac *FLAC) Pictures() (blocks []*FLACMetadataBlockPicture) {
+	for _, iBlock := range flac.MetadataBlocks {
+		if block, ok := iBlock.(*FLACMetadataBlockPicture); ok {
+			blocks = append(blocks, block)
+		}
+	}
+
+	return
+}
+
+// Applications returns all application blocks in file order.
+func (flac *FLAC) Applications() (blocks []*FLACMetadataBlockApplication) {
+	for _, iBlock := range flac.MetadataBlocks {
+		if block, ok := iBlock.(*FLACMetadataBlockApplication); ok {
+			blocks = append(blocks, block)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,49 @@
+package flac
+
+func (suite *FLACTestSuite) TestAccessors() {
+	suite.assert.Equal("reference libFLAC 1.1.4 20070213", suite.flac.VorbisComment().VendorString)
+	suite.assert.Equal(1, len(suite.flac.SeekTable().SeekPoints))
+	suite.assert.Equal(4, len(suite.flac.CueSheet().CueSheetTracks))
+	suite.assert.Equal(1, len(suite.flac.Pictures()))
+	suite.assert.Equal(suite.flac.FrontCover(), suite.flac.Pictures()[0])
+	suite.assert.Equal(1, len(suite.flac.Applications()))
+	suite.assert.Equal("ATCH", suite.flac.Applications()[0].AppID)
+
+	empty := &FLAC{}
+
+	suite.assert.Nil(empty.VorbisComment())
+	suite.assert.Nil(empty.SeekTable())
+	suite.assert.Nil(empty.CueSheet())
+	suite.assert.Equal(0, len(empty.Pictures()))
+	suite.assert.Equal(0, len(empty.Applications()))
+}
+
+func (suite *FLACTestSuite) TestBlocks() {
+	var types []BlockType
+
+	for block := range suite.flac.Blocks() {
+		types = append(types, block.header().Type)
+	}
+
+	suite.assert.Equal([]BlockType{StreamInfo, SeekTable, Application, VorbisComment, Picture, CueSheet, Padding}, types)
+
+	count := 0
+
+	for block := range suite.flac.BlocksOfType(Picture) {
+		suite.assert.Equal(suite.flac.FrontCover(), block)
+
+		count++
+	}
+
+	suite.assert.Equal(1, count)
+
+	count = 0
+
+	for range suite.flac.Blocks() {
+		count++
+
+		break
+	}
+
+	suite.assert.Equal(1, count)
+}
@@ -0,0 +1,200 @@
+package flac
+
+import (
+	"io"
+	"fmt"
+	"bytes"
+	"errors"
+	"unicode/utf8"
+	"unicode/utf16"
+	"encoding/binary"
+)
+
+// Text encodings of ID3v2 frames.
+const (
+	id3Latin1 = 0
+	id3UTF16 = 1
+	id3UTF16BE = 2
+	id3UTF8 = 3
+)
+
+// EncodeAPIC encodes the picture as the body of an ID3v2 APIC frame for target, TargetID3v23 or TargetID3v24.
+// The picture type carries over unchanged, as ID3v2 defines the same types. The description is written in
+// ISO-8859-1 where it fits, and otherwise in UTF-8 for ID3v2.4 or UTF-16 for ID3v2.3. Pictures that link to
+// a URL, with the MIME type "-->", are written with the URL as their data, as APIC frames do.
+func (block *FLACMetadataBlockPicture) EncodeAPIC(target TagTarget) (data []byte, err error) {
+	if target != TargetID3v23 && target != TargetID3v24 {
+		err = errors.New("APIC frames are only defined for ID3v2.3 and ID3v2.4")
+
+		return
+	}
+
+	for index := 0; index < len(block.MIMEType); index++ {
+		if block.MIMEType[index] == 0 || block.MIMEType[index] > 0x7e {
+			err = fmt.Errorf("MIME type %q cannot be written in an APIC frame", block.MIMEType)
+
+			return
+		}
+	}
+
+	var picture bytes.Buffer
+
+	_, err = block.Copy(&picture)
+
+	if err != nil {
+		return
+	}
+
+	encoding, description := encodeID3Text(block.Description, target)
+	data = append([]byte{encoding}, block.MIMEType...)
+	data = append(data, 0, byte(block.Type))
+	data = append(data, description...)
+
+	return append(data, picture.Bytes()...), nil
+}
+
+// encodeID3Text encodes text with its terminator in the narrowest encoding the target allows.
+func encodeID3Text(text string, target TagTarget) (encoding byte, data []byte) {
+	latin1 := true
+
+	for _, c := range text {
+		latin1 = latin1 && c < 0x100
+		data = append(data, byte(c))
+	}
+
+	switch {
+		case latin1:
+			return id3Latin1, append(data, 0)
+
+		case target == TargetID3v24:
+			return id3UTF8, append([]byte(text), 0)
+	}
+
+	data = []byte{0xff, 0xfe}
+
+	for _, unit := range utf16.Encode([]rune(text)) {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+
+	return id3UTF16, append(data, 0, 0)
+}
+
+// DecodeAPIC creates a picture block from the body of an ID3v2.3 or ID3v2.4 APIC frame. A missing MIME type is
+// sniffed from the data, and the dimensions and colour depth are read from the image where it can be decoded.
+// APIC picture types beyond those FLAC defines become Other.
+func DecodeAPIC(data []byte) (block *FLACMetadataBlockPicture, err error) {
+	if len(data) < 1 {
+		err = io.ErrUnexpectedEOF
+
+		return
+	}
+
+	encoding := data[0]
+	mimeEnd := bytes.IndexByte(data[1:], 0)
+
+	if encoding > id3UTF8 {
+		err = fmt.Errorf("unknown ID3v2 text encoding %d", encoding)
+
+		return
+	}
+
+	if mimeEnd < 0 || 1 + mimeEnd + 2 > len(data) {
+		err = io.ErrUnexpectedEOF
+
+		return
+	}
+
+	mimeType := string(data[1:1 + mimeEnd])
+	pictureType := PictureType(data[1 + mimeEnd + 1])
+
+	if pictureType > PublisherLogo {
+		pictureType = Other
+	}
+
+	description, picture, err := decodeID3Text(data[1 + mimeEnd + 2:], encoding)
+
+	if err != nil {
+		return
+	}
+
+	if mimeType == "" {
+		mimeType = sniffMIMEType(picture)
+	}
+
+	block = newPicture(pictureType, mimeType, description, picture)
+
+	if !block.IsURL() {
+		block.FixDimensions()
+	}
+
+	return
+}
+
+// decodeID3Text decodes terminated text in encoding from the start of data and returns it with the data after it.
+func decodeID3Text(data []byte, encoding byte) (text string, rest []byte, err error) {
+	if encoding == id3Latin1 || encoding == id3UTF8 {
+		end := bytes.IndexByte(data, 0)
+
+		if end < 0 {
+			err = io.ErrUnexpectedEOF
+
+			return
+		}
+
+		if encoding == id3UTF8 {
+			text = string(data[:end])
+
+			if !utf8.ValidString(text) {
+				err = errors.New("APIC description is not valid UTF-8")
+			}
+		} else {
+			runes := make([]rune, end)
+
+			for index, c := range data[:end] {
+				runes[index] = rune(c)
+			}
+
+			text = string(runes)
+		}
+
+		return text, data[end + 1:], err
+	}
+
+	end := -1
+
+	for index := 0; index + 1 < len(data); index += 2 {
+		if data[index] == 0 && data[index + 1] == 0 {
+			end = index
+
+			break
+		}
+	}
+
+	if end < 0 {
+		err = io.ErrUnexpectedEOF
+
+		return
+	}
+
+	var order binary.ByteOrder = binary.BigEndian
+	units := data[:end]
+
+	if encoding == id3UTF16 && len(units) >= 2 {
+		switch {
+			case units[0] == 0xff && units[1] == 0xfe:
+				order = binary.LittleEndian
+				units = units[2:]
+
+			case units[0] == 0xfe && units[1] == 0xff:
+				units = units[2:]
+		}
+	}
+
+	decoded := make([]uint16, len(units) / 2)
+
+	for index := range decoded {
+		decoded[index] = order.Uint16(units[index * 2:])
+	}
+
+	return string(utf16.Decode(decoded)), data[end + 2:], nil
+}
@@ -0,0 +1,62 @@
+package flac
+
+func (suite *FLACTestSuite) TestAPIC() {
+	cover := suite.flac.FrontCover()
+	cover.Description = "Front"
+
+	data, err := cover.EncodeAPIC(TargetID3v23)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("\x00image/jpeg\x00\x03Front\x00", string(data[:19]))
+	suite.assert.Equal(cover.Picture, data[19:])
+
+	decoded, err := DecodeAPIC(data)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(FrontCover, decoded.Type)
+	suite.assert.Equal("image/jpeg", decoded.MIMEType)
+	suite.assert.Equal("Front", decoded.Description)
+	suite.assert.Equal(2448, decoded.Width)
+	suite.assert.Equal(cover.PictureMD5, decoded.PictureMD5)
+
+	link := newPicture(Artist, "-->", "Ä ☃", []byte("http://example.com/a.jpg"))
+
+	for _, target := range []TagTarget{TargetID3v23, TargetID3v24} {
+		data, err = link.EncodeAPIC(target)
+
+		suite.assert.NoError(err)
+
+		decoded, err = DecodeAPIC(data)
+
+		suite.assert.NoError(err)
+		suite.assert.Equal(Artist, decoded.Type)
+		suite.assert.Equal("-->", decoded.MIMEType)
+		suite.assert.Equal("Ä ☃", decoded.Description)
+		suite.assert.Equal("http://example.com/a.jpg", string(decoded.Picture))
+	}
+
+	suite.assert.Equal(byte(id3UTF8), data[0])
+
+	latin1, err := DecodeAPIC([]byte("\x00\x00\x30caf\xe9\x00data"))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(Other, latin1.Type)
+	suite.assert.Equal("café", latin1.Description)
+	suite.assert.Equal("text/plain; charset=utf-8", latin1.MIMEType)
+
+	bigEndian, err := DecodeAPIC([]byte("\x02-->\x00\x08\x00h\x00i\x00\x00url"))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("hi", bigEndian.Description)
+	suite.assert.Equal(Artist, bigEndian.Type)
+
+	for _, invalid := range []string{"", "\x04image/png\x00\x03\x00", "\x00image/png", "\x01image/png\x00\x03\x00", "\x03-->\x00\x03\xff\x00"} {
+		_, err = DecodeAPIC([]byte(invalid))
+
+		suite.assert.Error(err, invalid)
+	}
+
+	_, err = cover.EncodeAPIC(TargetMP4)
+
+	suite.assert.Error(err)
+}
@@ -0,0 +1,105 @@
+package flac
+
+import (
+	"fmt"
+	"sync"
+	"errors"
+)
+
+// ApplicationCodec converts the payload of APPLICATION blocks with a particular ID to and from a decoded value.
+type ApplicationCodec interface {
+	Decode(data []byte) (interface{}, error)
+	Encode(value interface{}) ([]byte, error)
+}
+
+var (
+	applicationCodecsMutex sync.RWMutex
+	applicationCodecs = make(map[string]ApplicationCodec)
+)
+
+// RegisterApplicationCodec makes a codec available for APPLICATION blocks with the given 4 byte ID.
+// It panics if the ID is not 4 bytes long or a codec is already registered for it.
+func RegisterApplicationCodec(appID string, codec ApplicationCodec) {
+	if len(appID) != 4 {
+		panic("flac: application ID must be 4 bytes: " + appID)
+	}
+
+	if codec == nil {
+		panic("flac: RegisterApplicationCodec codec is nil")
+	}
+
+	applicationCodecsMutex.Lock()
+	defer applicationCodecsMutex.Unlock()
+
+	if _, exists := applicationCodecs[appID]; exists {
+		panic("flac: RegisterApplicationCodec called twice for " + appID)
+	}
+
+	applicationCodecs[appID] = codec
+}
+
+func applicationCodec(appID string) ApplicationCodec {
+	applicationCodecsMutex.RLock()
+	defer applicationCodecsMutex.RUnlock()
+
+	return applicationCodecs[appID]
+}
+
+// decode fills in Value using the codec registered for the block's AppID. A payload the codec rejects is
+// recorded as a warning and left in AppData, so opaque application data never makes the metadata unreadable.
+func (block *FLACMetadataBlockApplication) decode() {
+	codec := applicationCodec(block.AppID)
+
+	if codec == nil {
+		return
+	}
+
+	value, err := codec.Decode(block.AppData)
+
+	if err != nil {
+		if block.FLAC != nil {
+			block.FLAC.Warnings = append(block.FLAC.Warnings, Warning{
+				Type: Application,
+				Offset: block.HeaderOffset,
+				Message: fmt.Sprintf("application %q data could not be decoded: %v", block.AppID, err),
+			})
+		}
+
+		return
+	}
+
+	block.Value = value
+}
+
+// appData returns the payload to write, encoding Value with the registered codec when there is one.
+func (block *FLACMetadataBlockApplication) appData() (data []byte, err error) {
+	codec := applicationCodec(block.AppID)
+
+	if block.Value == nil || codec == nil {
+		return block.AppData, nil
+	}
+
+	return codec.Encode(block.Value)
+}
+
+// Encode serializes Value into AppData using the codec registered for the block's AppID.
+func (block *FLACMetadataBlockApplication) Encode() (err error) {
+	codec := applicationCodec(block.AppID)
+
+	if codec == nil {
+		err = errors.New("no codec registered for application ID " + block.AppID)
+
+		return
+	}
+
+	data, err := codec.Encode(block.Value)
+
+	if err != nil {
+		return
+	}
+
+	block.AppData = data
+	block.FLACMetadataBlock.DataLength = uint32(len(data) + 4)
+
+	return
+}
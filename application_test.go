@@ -0,0 +1,87 @@
+package flac
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+type upperCaseCodec struct{}
+
+func (upperCaseCodec) Decode(data []byte) (interface{}, error) {
+	return strings.ToLower(string(data)), nil
+}
+
+func (upperCaseCodec) Encode(value interface{}) ([]byte, error) {
+	return []byte(strings.ToUpper(value.(string))), nil
+}
+
+type rejectingCodec struct{}
+
+func (rejectingCodec) Decode(data []byte) (interface{}, error) {
+	return nil, errors.New("unsupported version")
+}
+
+func (rejectingCodec) Encode(value interface{}) ([]byte, error) {
+	return nil, errors.New("unsupported version")
+}
+
+func (suite *FLACTestSuite) TestApplicationCodec() {
+	RegisterApplicationCodec("ATCH", upperCaseCodec{})
+
+	defer func() {
+		applicationCodecsMutex.Lock()
+		delete(applicationCodecs, "ATCH")
+		applicationCodecsMutex.Unlock()
+	}()
+
+	suite.assert.Panics(func() {
+		RegisterApplicationCodec("ATCH", upperCaseCodec{})
+	})
+
+	flac, err := Parse("sample.flac")
+
+	suite.assert.NoError(err)
+
+	for _, iBlock := range flac.MetadataBlocks {
+		block, ok := iBlock.(*FLACMetadataBlockApplication)
+
+		if !ok {
+			continue
+		}
+
+		suite.assert.Equal("c@k3", block.Value)
+
+		block.Value = "ok!!"
+
+		suite.assert.NoError(block.Encode())
+		suite.assert.Equal("OK!!", string(block.AppData))
+
+		block.Value = "new!"
+		data, err := block.marshal()
+
+		suite.assert.NoError(err)
+		suite.assert.Equal("ATCHNEW!", string(data))
+	}
+
+	applicationCodecsMutex.Lock()
+	applicationCodecs["ATCH"] = rejectingCodec{}
+	applicationCodecsMutex.Unlock()
+
+	flac, err = Parse("sample.flac")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(1, len(flac.Warnings))
+	suite.assert.Equal(Application, flac.Warnings[0].Type)
+
+	block := flac.Applications()[0]
+
+	suite.assert.Nil(block.Value)
+	suite.assert.Equal("C@K3", string(block.AppData))
+
+	var buffer bytes.Buffer
+
+	_, err = flac.WriteMetadata(&buffer)
+
+	suite.assert.NoError(err)
+}
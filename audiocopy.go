@@ -0,0 +1,44 @@
+package flac
+
+import (
+	"io"
+	"hash"
+)
+
+// audioChunkSize is the size of the buffer audio frames are copied through.
+const audioChunkSize = 64 * 1024
+
+// copyAudio copies src from offset to its end into dst through a single audioChunkSize buffer, so the amount of
+// memory used does not depend on the size of the audio. Each chunk is also fed to hash when it is not nil.
+func copyAudio(dst io.Writer, src io.ReaderAt, offset int64, hash hash.Hash) (n int64, err error) {
+	buffer := make([]byte, audioChunkSize)
+
+	for {
+		read, readErr := src.ReadAt(buffer, offset + n)
+
+		if read > 0 {
+			if hash != nil {
+				hash.Write(buffer[:read])
+			}
+
+			written, writeErr := dst.Write(buffer[:read])
+			n += int64(written)
+
+			if writeErr != nil {
+				err = writeErr
+
+				return
+			}
+		}
+
+		if readErr == io.EOF {
+			return
+		}
+
+		if readErr != nil {
+			err = readErr
+
+			return
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package flac
+
+import (
+	"bytes"
+	"crypto/md5"
+)
+
+func (suite *FLACTestSuite) TestCopyAudio() {
+	data := bytes.Repeat([]byte("frame"), audioChunkSize / 2)
+	hash := md5.New()
+
+	var buffer bytes.Buffer
+
+	n, err := copyAudio(&buffer, bytes.NewReader(data), 3, hash)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(len(data) - 3, n)
+	suite.assert.True(bytes.Equal(data[3:], buffer.Bytes()))
+
+	expected := md5.Sum(data[3:])
+
+	suite.assert.Equal(expected[:], hash.Sum(nil))
+
+	n, err = copyAudio(&buffer, bytes.NewReader(data), int64(len(data)), nil)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(0, n)
+}
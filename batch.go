@@ -0,0 +1,212 @@
+package flac
+
+import (
+	"fmt"
+	"sync"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// FileError associates an error with the file a batch operation failed on.
+type FileError struct {
+	Path string
+	Err error
+}
+
+func (err *FileError) Error() string {
+	return err.Path + ": " + err.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (err *FileError) Unwrap() error {
+	return err.Err
+}
+
+// BatchError aggregates the failures of a batch operation.
+type BatchError struct {
+	Errors []error
+}
+
+func (err *BatchError) Error() string {
+	messages := make([]string, len(err.Errors))
+
+	for index, fileErr := range err.Errors {
+		messages[index] = fileErr.Error()
+	}
+
+	return fmt.Sprintf("%d files failed: %s", len(err.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the individual errors so they can be inspected with errors.Is and errors.As.
+func (err *BatchError) Unwrap() []error {
+	return err.Errors
+}
+
+// FileResult is the outcome of a batch operation on a single file. Err is nil on success. Report describes the
+// save when EditFiles is given WithDryRun and the file was edited.
+type FileResult struct {
+	Path string
+	Err error
+	Report *SaveReport
+}
+
+// BatchResult holds the per-file outcomes of a batch operation in input order.
+type BatchResult struct {
+	Results []FileResult
+}
+
+func (result *BatchResult) add(path string, err error) {
+	if err != nil {
+		err = &FileError{path, err}
+	}
+
+	result.Results = append(result.Results, FileResult{Path: path, Err: err})
+}
+
+// Succeeded returns the paths that were processed successfully.
+func (result *BatchResult) Succeeded() (paths []string) {
+	for _, fileResult := range result.Results {
+		if fileResult.Err == nil {
+			paths = append(paths, fileResult.Path)
+		}
+	}
+
+	return
+}
+
+// Failed returns the paths that could not be processed, so callers can retry just those.
+func (result *BatchResult) Failed() (paths []string) {
+	for _, fileResult := range result.Results {
+		if fileResult.Err != nil {
+			paths = append(paths, fileResult.Path)
+		}
+	}
+
+	return
+}
+
+// Err returns a *BatchError aggregating every failure, or nil if all files succeeded.
+func (result *BatchResult) Err() error {
+	var errs []error
+
+	for _, fileResult := range result.Results {
+		if fileResult.Err != nil {
+			errs = append(errs, fileResult.Err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &BatchError{errs}
+}
+
+// ErrSkipFile can be returned by the edit function given to EditFiles to leave a file unsaved without
+// recording a failure.
+var ErrSkipFile = errors.New("skip file")
+
+// BatchProgress reports a file finished by EditFiles. Done is the number of files finished so far, out of Total.
+type BatchProgress struct {
+	Path string
+	Err error
+	Done int
+	Total int
+}
+
+// EditFiles parses each path, applies edit and saves the file with options, working on up to workers files at
+// once. Files are not saved when edit fails or returns ErrSkipFile. If progress is not nil it receives an update
+// as each file finishes and is closed before EditFiles returns, so it must be read from another goroutine.
+// The result lists the outcome for every path in input order. With WithDryRun each file is reported on in its
+// own FileResult.Report and the report given to WithDryRun is left untouched.
+func EditFiles(paths []string, workers int, edit func(*FLAC) error, progress chan<- BatchProgress, options ...SaveOption) (result *BatchResult) {
+	result = &BatchResult{
+		Results: make([]FileResult, len(paths)),
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wait sync.WaitGroup
+	var mutex sync.Mutex
+
+	indices := make(chan int)
+	done := 0
+	dryRun := newSaveOptions(options).report != nil
+
+	for worker := 0; worker < workers; worker++ {
+		wait.Add(1)
+
+		go func() {
+			defer wait.Done()
+
+			for index := range indices {
+				path := paths[index]
+				report, err := editFile(path, edit, options, dryRun)
+
+				if err != nil {
+					err = &FileError{path, err}
+				}
+
+				mutex.Lock()
+				result.Results[index] = FileResult{path, err, report}
+				done++
+
+				if progress != nil {
+					progress <- BatchProgress{path, err, done, len(paths)}
+				}
+
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for index := range paths {
+		indices <- index
+	}
+
+	close(indices)
+	wait.Wait()
+
+	if progress != nil {
+		close(progress)
+	}
+
+	return
+}
+
+// editFile parses, edits and saves a single file for EditFiles, reporting on the save instead when dryRun is set.
+func editFile(path string, edit func(*FLAC) error, options []SaveOption, dryRun bool) (report *SaveReport, err error) {
+	flac, err := Parse(path)
+
+	if err != nil {
+		return
+	}
+
+	defer flac.Close()
+
+	err = edit(flac)
+
+	if errors.Is(err, ErrSkipFile) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	if dryRun {
+		report = &SaveReport{}
+		options = append(slices.Clone(options), WithDryRun(report))
+	}
+
+	err = flac.Save(options...)
+
+	if err != nil {
+		report = nil
+	}
+
+	return
+}
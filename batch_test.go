@@ -0,0 +1,108 @@
+package flac
+
+import (
+	"os"
+	"errors"
+)
+
+func (suite *FLACTestSuite) TestBatchResult() {
+	result := &BatchResult{}
+
+	suite.assert.Nil(result.Err())
+
+	result.add("a.flac", nil)
+	result.add("b.flac", os.ErrNotExist)
+	result.add("c.flac", errors.New("corrupt"))
+
+	suite.assert.Equal([]string{"a.flac"}, result.Succeeded())
+	suite.assert.Equal([]string{"b.flac", "c.flac"}, result.Failed())
+
+	err := result.Err()
+	var fileErr *FileError
+
+	suite.assert.ErrorIs(err, os.ErrNotExist)
+	suite.assert.True(errors.As(err, &fileErr))
+	suite.assert.Equal("b.flac", fileErr.Path)
+	suite.assert.Equal("2 files failed: b.flac: file does not exist; c.flac: corrupt", err.Error())
+}
+
+func (suite *FLACTestSuite) TestEditFiles() {
+	var paths []string
+
+	for index := 0; index < 5; index++ {
+		path, _ := suite.copySample()
+		paths = append(paths, path)
+	}
+
+	paths = append(paths, "missing.flac")
+	progress := make(chan BatchProgress)
+	finished := make(chan []BatchProgress)
+
+	go func() {
+		var updates []BatchProgress
+
+		for update := range progress {
+			updates = append(updates, update)
+		}
+
+		finished <- updates
+	}()
+
+	result := EditFiles(paths, 3, func(flac *FLAC) error {
+		if flac.path == paths[0] {
+			return ErrSkipFile
+		}
+
+		if flac.path == paths[1] {
+			return errors.New("refused")
+		}
+
+		flac.VorbisComment().SetTitle("Batch")
+
+		return nil
+	}, progress)
+
+	updates := <-finished
+
+	suite.assert.Equal(len(paths), len(updates))
+	suite.assert.Equal(len(paths), updates[len(updates) - 1].Done)
+	suite.assert.Equal(len(paths), updates[0].Total)
+	suite.assert.Equal(append([]string{paths[0]}, paths[2:5]...), result.Succeeded())
+	suite.assert.Equal([]string{paths[1], "missing.flac"}, result.Failed())
+
+	for index, path := range paths[:5] {
+		flac, err := Parse(path)
+
+		suite.assert.NoError(err)
+
+		if index < 2 {
+			suite.assert.Equal("", flac.VorbisComment().Title())
+		} else {
+			suite.assert.Equal("Batch", flac.VorbisComment().Title())
+		}
+
+		flac.Close()
+	}
+
+	shared := &SaveReport{}
+	result = EditFiles(paths[2:5], 3, func(flac *FLAC) error {
+		flac.VorbisComment().SetTitle("Dry")
+
+		return nil
+	}, nil, WithDryRun(shared))
+
+	suite.assert.Nil(result.Err())
+	suite.assert.Equal(SaveReport{}, *shared)
+
+	for index, fileResult := range result.Results {
+		suite.assert.NotNil(fileResult.Report)
+		suite.assert.Contains(fileResult.Report.Changed, 3)
+
+		flac, err := Parse(paths[index + 2])
+
+		suite.assert.NoError(err)
+		suite.assert.Equal("Batch", flac.VorbisComment().Title())
+
+		flac.Close()
+	}
+}
@@ -0,0 +1,242 @@
+package flac
+
+import (
+	"io"
+	"fmt"
+	"slices"
+	"errors"
+	"encoding/binary"
+	"github.com/garfunkel/go-bitbuffer"
+)
+
+// New creates a FLAC holding only the given stream info block, for assembling metadata from scratch.
+func New(streamInfo *FLACMetadataBlockStreamInfo) *FLAC {
+	flac := &FLAC{
+		buffer: bitbuffer.NewBitBuffer(binary.BigEndian),
+		Marker: FLACMarker,
+		StreamInfo: streamInfo,
+	}
+
+	streamInfo.FLACMetadataBlock.FLAC = flac
+	streamInfo.FLACMetadataBlock.Last = true
+
+	return flac
+}
+
+// NewStreamInfo creates a stream info block with fixed 4096 sample blocks, unknown frame sizes and an unset MD5 signature.
+// A numSamples of zero means the total is unknown.
+func NewStreamInfo(sampleRate uint32, channels uint8, bitsPerSample uint8, numSamples uint64) (block *FLACMetadataBlockStreamInfo, err error) {
+	switch {
+		case sampleRate == 0 || sampleRate >= 1 << 20:
+			err = fmt.Errorf("invalid sample rate %d", sampleRate)
+
+		case channels == 0 || channels > 8:
+			err = fmt.Errorf("invalid channel count %d, expected 1 to 8", channels)
+
+		case bitsPerSample < 4 || bitsPerSample > 32:
+			err = fmt.Errorf("invalid bits per sample %d, expected 4 to 32", bitsPerSample)
+
+		case numSamples >= 1 << 36:
+			err = errors.New("total samples does not fit in 36 bits")
+	}
+
+	if err != nil {
+		return
+	}
+
+	block = &FLACMetadataBlockStreamInfo{
+		FLACMetadataBlock: FLACMetadataBlock{
+			Type: StreamInfo,
+			DataLength: 34,
+		},
+		MinBlockSize: 4096,
+		MaxBlockSize: 4096,
+		SampleRate: sampleRate,
+		Channels: channels,
+		BitsPerSample: bitsPerSample,
+		NumSamples: numSamples,
+		UnencodedMD5: make([]byte, 16),
+	}
+
+	return
+}
+
+// NewVorbisComment creates an empty vorbis comment block with the given vendor string, or with
+// DefaultVendorString written as its vendor if vendor is empty.
+func NewVorbisComment(vendor string) *FLACMetadataBlockVorbisComment {
+	return &FLACMetadataBlockVorbisComment{
+		FLACMetadataBlock: FLACMetadataBlock{
+			Type: VorbisComment,
+			DataLength: uint32(8 + len(vendor)),
+		},
+		VendorString: vendor,
+		Comments: []Comment{},
+		vendorSet: vendor != "",
+	}
+}
+
+// NewPicture creates a picture block holding data, with the dimensions and colour depth read from the image.
+// A picture linking to a URL holds the URL as its data and is created with the dimensions unset.
+func NewPicture(pictureType PictureType, mimeType string, description string, data []byte) (block *FLACMetadataBlockPicture, err error) {
+	block = newPicture(pictureType, mimeType, description, data)
+
+	if block.IsURL() {
+		return
+	}
+
+	err = block.FixDimensions()
+
+	if err != nil {
+		block = nil
+	}
+
+	return
+}
+
+// newPicture creates a picture block holding data with the dimensions and colour depth left unset.
+func newPicture(pictureType PictureType, mimeType string, description string, data []byte) (block *FLACMetadataBlockPicture) {
+	block = &FLACMetadataBlockPicture{
+		FLACMetadataBlock: FLACMetadataBlock{
+			Type: Picture,
+			DataLength: uint32(32 + len(mimeType) + len(description) + len(data)),
+		},
+		Type: pictureType,
+		MIMEType: mimeType,
+		Description: description,
+		PictureLength: uint32(len(data)),
+		Picture: data,
+	}
+
+	if !block.IsURL() {
+		block.PictureMD5 = pictureMD5(data)
+	}
+
+	return
+}
+
+// NewPadding creates a padding block of length bytes.
+func NewPadding(length uint32) *FLACMetadataBlockPadding {
+	return &FLACMetadataBlockPadding{
+		FLACMetadataBlock: FLACMetadataBlock{
+			Type: Padding,
+			DataLength: length,
+		},
+		NumBytes: length,
+	}
+}
+
+// AppendBlock adds a block after the existing metadata blocks. Stream info blocks cannot be appended.
+func (flac *FLAC) AppendBlock(block IFLACMetadataBlock) error {
+	return flac.InsertBlock(len(flac.MetadataBlocks), block)
+}
+
+// InsertBlock inserts a block before MetadataBlocks[index]; an index equal to the number of blocks appends it.
+// Stream info blocks cannot be inserted.
+func (flac *FLAC) InsertBlock(index int, block IFLACMetadataBlock) (err error) {
+	if block == nil || block.header().Type == StreamInfo {
+		err = errors.New("only blocks other than stream info can be inserted")
+
+		return
+	}
+
+	if index < 0 || index > len(flac.MetadataBlocks) {
+		err = fmt.Errorf("block index %d out of range", index)
+
+		return
+	}
+
+	block.header().FLAC = flac
+	flac.MetadataBlocks = slices.Insert(flac.MetadataBlocks, index, block)
+	flac.updateLastFlags()
+
+	return
+}
+
+// RemoveBlock removes MetadataBlocks[index].
+func (flac *FLAC) RemoveBlock(index int) (err error) {
+	if index < 0 || index >= len(flac.MetadataBlocks) {
+		err = fmt.Errorf("block index %d out of range", index)
+
+		return
+	}
+
+	flac.MetadataBlocks = slices.Delete(flac.MetadataBlocks, index, index + 1)
+	flac.updateLastFlags()
+
+	return
+}
+
+// RemoveBlocksOfType removes every metadata block of the given type and returns how many were removed.
+// The stream info block is never removed.
+func (flac *FLAC) RemoveBlocksOfType(blockType BlockType) (removed int) {
+	count := len(flac.MetadataBlocks)
+	flac.MetadataBlocks = slices.DeleteFunc(flac.MetadataBlocks, func(block IFLACMetadataBlock) bool {
+		return block.header().Type == blockType
+	})
+	removed = count - len(flac.MetadataBlocks)
+
+	flac.updateLastFlags()
+
+	return
+}
+
+// StripMetadata removes every metadata block whose type is not in keep, as metaflac --remove-all does, and returns
+// how many were removed. The stream info block is always kept. Padding is removed unless kept; Save fills the freed
+// space with padding, while SaveAs with WithTargetPadding writes a file with just the padding asked for.
+func (flac *FLAC) StripMetadata(keep ...BlockType) (removed int) {
+	count := len(flac.MetadataBlocks)
+	flac.MetadataBlocks = slices.DeleteFunc(flac.MetadataBlocks, func(block IFLACMetadataBlock) bool {
+		return !slices.Contains(keep, block.header().Type)
+	})
+	removed = count - len(flac.MetadataBlocks)
+
+	flac.updateLastFlags()
+
+	return
+}
+
+// MoveBlock moves MetadataBlocks[from] so that it ends up at index to.
+func (flac *FLAC) MoveBlock(from, to int) (err error) {
+	if from < 0 || from >= len(flac.MetadataBlocks) || to < 0 || to >= len(flac.MetadataBlocks) {
+		err = fmt.Errorf("cannot move block %d to %d", from, to)
+
+		return
+	}
+
+	block := flac.MetadataBlocks[from]
+	flac.MetadataBlocks = slices.Insert(slices.Delete(flac.MetadataBlocks, from, from + 1), to, block)
+	flac.updateLastFlags()
+
+	return
+}
+
+// updateLastFlags sets the last-block flag on the final block only.
+func (flac *FLAC) updateLastFlags() {
+	if flac.StreamInfo != nil {
+		flac.StreamInfo.Last = len(flac.MetadataBlocks) == 0
+	}
+
+	for index, block := range flac.MetadataBlocks {
+		block.header().Last = index == len(flac.MetadataBlocks) - 1
+	}
+}
+
+// WriteMetadata writes the marker and the metadata blocks to w, ready for audio frames to follow.
+func (flac *FLAC) WriteMetadata(w io.Writer) (n int64, err error) {
+	if flac.blocksLeftOut > 0 {
+		err = ErrIncompleteMetadata
+
+		return
+	}
+
+	data, _, err := encodeMetadata(slices.Collect(flac.Blocks()))
+
+	if err != nil {
+		return
+	}
+
+	written, err := w.Write(append([]byte(FLACMarker), data...))
+	n = int64(written)
+
+	return
+}
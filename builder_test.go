@@ -0,0 +1,123 @@
+package flac
+
+import (
+	"os"
+	"bytes"
+)
+
+func (suite *FLACTestSuite) TestBuilder() {
+	_, err := NewStreamInfo(44100, 9, 16, 0)
+
+	suite.assert.Error(err)
+
+	streamInfo, err := NewStreamInfo(44100, 2, 16, 441000)
+
+	suite.assert.NoError(err)
+
+	flac := New(streamInfo)
+	comments := NewVorbisComment("go-flac")
+	comments.AddTag("TITLE", "Song")
+
+	picture, err := NewPicture(FrontCover, "image/jpeg", "", suite.flac.FrontCover().Picture)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(2448, picture.Width)
+	suite.assert.Equal(3264, picture.Height)
+	suite.assert.Equal(24, picture.ColourDepth)
+
+	_, err = NewPicture(FrontCover, "image/jpeg", "", []byte("not an image"))
+
+	suite.assert.Error(err)
+	suite.assert.Error(flac.AppendBlock(streamInfo))
+	suite.assert.NoError(flac.AppendBlock(comments))
+	suite.assert.NoError(flac.AppendBlock(picture))
+	suite.assert.NoError(flac.AppendBlock(NewPadding(1024)))
+	suite.assert.False(streamInfo.Last)
+	suite.assert.False(picture.Last)
+
+	var buffer bytes.Buffer
+
+	n, err := flac.WriteMetadata(&buffer)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(buffer.Len(), n)
+
+	parsed, err := ParseReader(bytes.NewReader(buffer.Bytes()))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(buffer.Len(), parsed.AudioOffset)
+	suite.assert.Equal(0, len(Diff(flac, parsed)))
+	suite.assert.Equal(3, len(parsed.MetadataBlocks))
+	suite.assert.Equal(picture.DataLength, parsed.FrontCover().DataLength)
+}
+
+func (suite *FLACTestSuite) TestBlockManipulation() {
+	flac := suite.flac.Clone()
+	types := func() (types []BlockType) {
+		for block := range flac.Blocks() {
+			types = append(types, block.header().Type)
+
+			suite.assert.Equal(len(types) == len(flac.MetadataBlocks) + 1, block.isLast())
+		}
+
+		return
+	}
+
+	suite.assert.NoError(flac.MoveBlock(5, 0))
+	suite.assert.Equal([]BlockType{StreamInfo, Padding, SeekTable, Application, VorbisComment, Picture, CueSheet}, types())
+	suite.assert.NoError(flac.MoveBlock(0, 5))
+	suite.assert.Equal([]BlockType{StreamInfo, SeekTable, Application, VorbisComment, Picture, CueSheet, Padding}, types())
+	suite.assert.NoError(flac.RemoveBlock(5))
+	suite.assert.Equal([]BlockType{StreamInfo, SeekTable, Application, VorbisComment, Picture, CueSheet}, types())
+	suite.assert.NoError(flac.InsertBlock(0, NewPadding(10)))
+	suite.assert.NoError(flac.InsertBlock(6, NewPadding(20)))
+	suite.assert.Equal(2, flac.RemoveBlocksOfType(Padding))
+	suite.assert.Equal(0, flac.RemoveBlocksOfType(StreamInfo))
+	suite.assert.Equal([]BlockType{StreamInfo, SeekTable, Application, VorbisComment, Picture, CueSheet}, types())
+	suite.assert.Error(flac.RemoveBlock(6))
+	suite.assert.Error(flac.MoveBlock(0, 6))
+	suite.assert.Error(flac.InsertBlock(-1, NewPadding(0)))
+
+	for range 4 {
+		suite.assert.NoError(flac.RemoveBlock(0))
+	}
+
+	suite.assert.False(flac.StreamInfo.Last)
+	suite.assert.NoError(flac.RemoveBlock(0))
+	suite.assert.True(flac.StreamInfo.Last)
+	suite.assert.Equal(7, len(suite.flac.MetadataBlocks) + 1)
+}
+
+func (suite *FLACTestSuite) TestStripMetadata() {
+	path, original := suite.copySample()
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	suite.assert.Equal(4, flac.StripMetadata(SeekTable, Padding))
+	suite.assert.Equal(2, len(flac.MetadataBlocks))
+	suite.assert.Nil(flac.VorbisComment())
+	suite.assert.True(flac.MetadataBlocks[1].isLast())
+
+	suite.assert.Equal(2, flac.StripMetadata())
+	suite.assert.True(flac.StreamInfo.isLast())
+
+	suite.assert.NoError(flac.SaveAs(path, WithTargetPadding(1024)))
+
+	saved, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer saved.Close()
+
+	suite.assert.Equal(1, len(saved.MetadataBlocks))
+	suite.assert.Equal(1024, saved.MetadataBlocks[0].header().DataLength)
+	suite.assert.Equal(int64(4 + 38 + 4 + 1024), saved.AudioOffset)
+
+	data, err := os.ReadFile(path)
+
+	suite.assert.NoError(err)
+	suite.assert.True(bytes.Equal(original[suite.flac.AudioOffset:], data[saved.AudioOffset:]))
+}
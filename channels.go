@@ -0,0 +1,157 @@
+package flac
+
+import (
+	"fmt"
+	"strings"
+	"strconv"
+)
+
+// Speaker identifies a loudspeaker position. Values match the WAVEFORMATEXTENSIBLE speaker bits.
+type Speaker uint32
+
+// Enum indicating speaker positions.
+const (
+	FrontLeft Speaker = 1 << iota
+	FrontRight
+	FrontCenter
+	LowFrequency
+	BackLeft
+	BackRight
+	FrontLeftOfCenter
+	FrontRightOfCenter
+	BackCenter
+	SideLeft
+	SideRight
+	TopCenter
+	TopFrontLeft
+	TopFrontCenter
+	TopFrontRight
+	TopBackLeft
+	TopBackCenter
+	TopBackRight
+)
+
+var speakerNames = map[Speaker]string{
+	FrontLeft: "FL",
+	FrontRight: "FR",
+	FrontCenter: "FC",
+	LowFrequency: "LFE",
+	BackLeft: "BL",
+	BackRight: "BR",
+	FrontLeftOfCenter: "FLC",
+	FrontRightOfCenter: "FRC",
+	BackCenter: "BC",
+	SideLeft: "SL",
+	SideRight: "SR",
+	TopCenter: "TC",
+	TopFrontLeft: "TFL",
+	TopFrontCenter: "TFC",
+	TopFrontRight: "TFR",
+	TopBackLeft: "TBL",
+	TopBackCenter: "TBC",
+	TopBackRight: "TBR",
+}
+
+func (speaker Speaker) String() string {
+	if name, ok := speakerNames[speaker]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// defaultChannelOrders holds the channel assignments defined by the FLAC format for 1 to 8 channels.
+var defaultChannelOrders = [][]Speaker{
+	nil,
+	{FrontCenter},
+	{FrontLeft, FrontRight},
+	{FrontLeft, FrontRight, FrontCenter},
+	{FrontLeft, FrontRight, BackLeft, BackRight},
+	{FrontLeft, FrontRight, FrontCenter, BackLeft, BackRight},
+	{FrontLeft, FrontRight, FrontCenter, LowFrequency, BackLeft, BackRight},
+	{FrontLeft, FrontRight, FrontCenter, LowFrequency, BackCenter, SideLeft, SideRight},
+	{FrontLeft, FrontRight, FrontCenter, LowFrequency, BackLeft, BackRight, SideLeft, SideRight},
+}
+
+// ChannelOrder returns the speaker assigned to each channel of a stream with the given number of channels,
+// in the order channels are stored. It returns nil for channel counts the format does not define.
+func ChannelOrder(channels uint8) []Speaker {
+	if channels == 0 || int(channels) >= len(defaultChannelOrders) {
+		return nil
+	}
+
+	return append([]Speaker(nil), defaultChannelOrders[channels]...)
+}
+
+// ChannelOrder returns the speaker assigned to each channel of the stream.
+func (block *FLACMetadataBlockStreamInfo) ChannelOrder() []Speaker {
+	return ChannelOrder(block.Channels)
+}
+
+// ChannelMaskTag is the vorbis comment field holding a WAVEFORMATEXTENSIBLE speaker mask.
+const ChannelMaskTag = "WAVEFORMATEXTENSIBLE_CHANNEL_MASK"
+
+// ChannelMask is a set of speakers, stored as WAVEFORMATEXTENSIBLE dwChannelMask bits.
+type ChannelMask uint32
+
+// NewChannelMask creates a ChannelMask containing the given speakers.
+func NewChannelMask(speakers ...Speaker) (mask ChannelMask) {
+	for _, speaker := range speakers {
+		mask |= ChannelMask(speaker)
+	}
+
+	return
+}
+
+// ParseChannelMask parses a mask written as a hexadecimal ("0x0003") or decimal number.
+func ParseChannelMask(value string) (mask ChannelMask, err error) {
+	parsed, err := strconv.ParseUint(strings.TrimSpace(value), 0, 32)
+
+	if err != nil {
+		return
+	}
+
+	mask = ChannelMask(parsed)
+
+	return
+}
+
+// Speakers returns the speakers in the mask in channel order.
+func (mask ChannelMask) Speakers() (speakers []Speaker) {
+	for bit := uint(0); bit < 32; bit++ {
+		if mask & (1 << bit) != 0 {
+			speakers = append(speakers, Speaker(1 << bit))
+		}
+	}
+
+	return
+}
+
+// Channels returns the number of speakers in the mask.
+func (mask ChannelMask) Channels() int {
+	return len(mask.Speakers())
+}
+
+func (mask ChannelMask) String() string {
+	return fmt.Sprintf("0x%04X", uint32(mask))
+}
+
+// ChannelMask returns the speaker mask stored in the WAVEFORMATEXTENSIBLE_CHANNEL_MASK comment.
+// The boolean result is false if the comment is absent.
+func (block *FLACMetadataBlockVorbisComment) ChannelMask() (mask ChannelMask, ok bool, err error) {
+	value := block.firstComment(ChannelMaskTag)
+
+	if value == "" {
+		return
+	}
+
+	ok = true
+	mask, err = ParseChannelMask(value)
+
+	return
+}
+
+// SetChannelMask replaces the WAVEFORMATEXTENSIBLE_CHANNEL_MASK comment with the given mask.
+func (block *FLACMetadataBlockVorbisComment) SetChannelMask(mask ChannelMask) {
+	block.SetTag(ChannelMaskTag, mask.String())
+}
@@ -0,0 +1,49 @@
+package flac
+
+func (suite *FLACTestSuite) TestChannelOrder() {
+	suite.assert.Equal([]Speaker{FrontLeft, FrontRight}, suite.flac.StreamInfo.ChannelOrder())
+	suite.assert.Equal([]Speaker{FrontLeft, FrontRight, FrontCenter, LowFrequency, BackLeft, BackRight}, ChannelOrder(6))
+	suite.assert.Equal(8, len(ChannelOrder(8)))
+	suite.assert.Nil(ChannelOrder(0))
+	suite.assert.Nil(ChannelOrder(9))
+	suite.assert.Equal("LFE", LowFrequency.String())
+	suite.assert.Equal(0x400, SideRight)
+}
+
+func (suite *FLACTestSuite) TestChannelMask() {
+	mask := NewChannelMask(ChannelOrder(6)...)
+
+	suite.assert.Equal(0x3f, mask)
+	suite.assert.Equal("0x003F", mask.String())
+	suite.assert.Equal(6, mask.Channels())
+	suite.assert.Equal(ChannelOrder(6), mask.Speakers())
+
+	parsed, err := ParseChannelMask("0x0603")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(NewChannelMask(FrontLeft, FrontRight, SideLeft, SideRight), parsed)
+
+	parsed, err = ParseChannelMask("3")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(3, parsed)
+
+	_, err = ParseChannelMask("stereo")
+
+	suite.assert.Error(err)
+
+	comments := suite.flac.VorbisComment()
+	_, ok, err := comments.ChannelMask()
+
+	suite.assert.False(ok)
+	suite.assert.NoError(err)
+
+	comments.AddTag("waveformatextensible_channel_mask", "0x0001")
+	comments.SetChannelMask(mask)
+	parsed, ok, err = comments.ChannelMask()
+
+	suite.assert.True(ok)
+	suite.assert.NoError(err)
+	suite.assert.Equal(mask, parsed)
+	suite.assert.Equal(2, len(comments.Comments))
+}
@@ -0,0 +1,81 @@
+package flac
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// CharsetDetector converts text that is not valid UTF-8 into UTF-8. It returns the name of the character
+// set it decoded from, or false if it does not recognise the text. Detectors for multi-byte encodings such
+// as Shift-JIS can be built on golang.org/x/text.
+type CharsetDetector interface {
+	Decode(text string) (decoded string, charset string, ok bool)
+}
+
+// RepairedComment records a comment value transcoded by RecoverEncoding. Index is the position of the comment in Comments.
+type RepairedComment struct {
+	Key string `json:"key"`
+	Index int `json:"index"`
+	Charset string `json:"charset"`
+	Original string `json:"original"`
+}
+
+type windows1252 struct{}
+
+// Windows1252 is a CharsetDetector that decodes text as Windows-1252.
+var Windows1252 CharsetDetector = windows1252{}
+
+// windows1252Runes maps bytes 0x80 to 0x9f, where Windows-1252 differs from ISO-8859-1. Zero marks undefined bytes.
+var windows1252Runes = [32]rune{
+	0x20ac, 0, 0x201a, 0x0192, 0x201e, 0x2026, 0x2020, 0x2021, 0x02c6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017d, 0,
+	0, 0x2018, 0x2019, 0x201c, 0x201d, 0x2022, 0x2013, 0x2014, 0x02dc, 0x2122, 0x0161, 0x203a, 0x0153, 0, 0x017e, 0x0178,
+}
+
+func (windows1252) Decode(text string) (decoded string, charset string, ok bool) {
+	var builder strings.Builder
+
+	for index := 0; index < len(text); index++ {
+		c := text[index]
+
+		switch {
+			case c < 0x80 || c >= 0xa0:
+				builder.WriteRune(rune(c))
+
+			case windows1252Runes[c - 0x80] != 0:
+				builder.WriteRune(windows1252Runes[c - 0x80])
+
+			default:
+				return
+		}
+	}
+
+	return builder.String(), "windows-1252", true
+}
+
+// RecoverEncoding transcodes comment values that are not valid UTF-8 using the detector, records them in
+// Repaired and returns the values it changed. Values the detector cannot decode are left untouched.
+func (block *FLACMetadataBlockVorbisComment) RecoverEncoding(detector CharsetDetector) (repaired []RepairedComment) {
+	for index, comment := range block.Comments {
+		if utf8.ValidString(comment.Value) {
+			continue
+		}
+
+		decoded, charset, ok := detector.Decode(comment.Value)
+
+		if !ok || !utf8.ValidString(decoded) {
+			continue
+		}
+
+		block.Comments[index].Value = decoded
+		repaired = append(repaired, RepairedComment{
+			Key: comment.Key,
+			Index: index,
+			Charset: charset,
+			Original: comment.Value,
+		})
+	}
+
+	block.Repaired = append(block.Repaired, repaired...)
+
+	return
+}
@@ -0,0 +1,15 @@
+package flac
+
+func (suite *FLACTestSuite) TestRecoverEncoding() {
+	comments := suite.flac.VorbisComment()
+	comments.SetTag("TITLE", "Caf\xe9 \x93Noir\x94", "fine")
+	comments.SetTag("ARTIST", "bad \x81 byte")
+
+	repaired := comments.RecoverEncoding(Windows1252)
+
+	suite.assert.Equal([]RepairedComment{{"TITLE", 1, "windows-1252", "Caf\xe9 \x93Noir\x94"}}, repaired)
+	suite.assert.Equal(repaired, comments.Repaired)
+	suite.assert.Equal([]string{"Café “Noir”", "fine"}, comments.GetTag("TITLE"))
+	suite.assert.Equal([]string{"bad \x81 byte"}, comments.GetTag("ARTIST"))
+	suite.assert.Equal([]string{"fish"}, comments.GetTag("example"))
+}
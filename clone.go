@@ -0,0 +1,133 @@
+package flac
+
+import (
+	"bytes"
+	"slices"
+	"github.com/garfunkel/go-bitbuffer"
+	"encoding/binary"
+)
+
+// Clone returns a deep copy of the parsed metadata that can be modified without affecting the original.
+// The copy does not share the original's open file; lazy operations on it reopen the file by path.
+// Decoded application values are shared, as their types are opaque to this package.
+func (flac *FLAC) Clone() *FLAC {
+	clone := &FLAC{
+		buffer: bitbuffer.NewBitBuffer(binary.BigEndian),
+		Marker: flac.Marker,
+		AudioOffset: flac.AudioOffset,
+		Warnings: slices.Clone(flac.Warnings),
+		path: flac.path,
+		fsys: flac.fsys,
+		options: flac.options,
+		blocksLeftOut: flac.blocksLeftOut,
+		size: flac.size,
+		modTime: flac.modTime,
+		quickHash: bytes.Clone(flac.quickHash),
+		imageTransformer: flac.imageTransformer,
+	}
+
+	if flac.StreamInfo != nil {
+		clone.StreamInfo = flac.StreamInfo.clone(clone).(*FLACMetadataBlockStreamInfo)
+	}
+
+	if flac.Gap != nil {
+		gap := *flac.Gap
+		clone.Gap = &gap
+	}
+
+	if flac.MetadataBlocks != nil {
+		clone.MetadataBlocks = make([]IFLACMetadataBlock, len(flac.MetadataBlocks))
+
+		for index, block := range flac.MetadataBlocks {
+			clone.MetadataBlocks[index] = block.clone(clone)
+		}
+	}
+
+	return clone
+}
+
+// cloneHeader copies the block header, attaching it to flac.
+func (block *FLACMetadataBlock) cloneHeader(flac *FLAC) FLACMetadataBlock {
+	clone := *block
+	clone.FLAC = flac
+	clone.RawData = bytes.Clone(block.RawData)
+
+	return clone
+}
+
+func (block *FLACMetadataBlockStreamInfo) clone(flac *FLAC) IFLACMetadataBlock {
+	clone := *block
+	clone.FLACMetadataBlock = block.FLACMetadataBlock.cloneHeader(flac)
+	clone.UnencodedMD5 = bytes.Clone(block.UnencodedMD5)
+
+	return &clone
+}
+
+func (block *FLACMetadataBlockPadding) clone(flac *FLAC) IFLACMetadataBlock {
+	clone := *block
+	clone.FLACMetadataBlock = block.FLACMetadataBlock.cloneHeader(flac)
+
+	return &clone
+}
+
+func (block *FLACMetadataBlockApplication) clone(flac *FLAC) IFLACMetadataBlock {
+	clone := *block
+	clone.FLACMetadataBlock = block.FLACMetadataBlock.cloneHeader(flac)
+	clone.AppData = bytes.Clone(block.AppData)
+
+	return &clone
+}
+
+func (block *FLACMetadataBlockSeekTable) clone(flac *FLAC) IFLACMetadataBlock {
+	clone := *block
+	clone.FLACMetadataBlock = block.FLACMetadataBlock.cloneHeader(flac)
+	clone.SeekPoints = slices.Clone(block.SeekPoints)
+
+	return &clone
+}
+
+func (block *FLACMetadataBlockVorbisComment) clone(flac *FLAC) IFLACMetadataBlock {
+	clone := *block
+	clone.FLACMetadataBlock = block.FLACMetadataBlock.cloneHeader(flac)
+	clone.Repaired = slices.Clone(block.Repaired)
+
+	clone.Comments = slices.Clone(block.Comments)
+
+	return &clone
+}
+
+func (block *FLACMetadataBlockCueSheet) clone(flac *FLAC) IFLACMetadataBlock {
+	clone := *block
+	clone.FLACMetadataBlock = block.FLACMetadataBlock.cloneHeader(flac)
+	clone.CueSheetTracks = slices.Clone(block.CueSheetTracks)
+
+	for index, track := range clone.CueSheetTracks {
+		clone.CueSheetTracks[index].CueSheetTrackIndices = slices.Clone(track.CueSheetTrackIndices)
+	}
+
+	return &clone
+}
+
+func (block *FLACMetadataBlockPicture) clone(flac *FLAC) IFLACMetadataBlock {
+	clone := *block
+	clone.FLACMetadataBlock = block.FLACMetadataBlock.cloneHeader(flac)
+	clone.Picture = bytes.Clone(block.Picture)
+	clone.PictureMD5 = bytes.Clone(block.PictureMD5)
+
+	return &clone
+}
+
+func (block *FLACMetadataBlockReserved) clone(flac *FLAC) IFLACMetadataBlock {
+	clone := *block
+	clone.FLACMetadataBlock = block.FLACMetadataBlock.cloneHeader(flac)
+	clone.Data = bytes.Clone(block.Data)
+
+	return &clone
+}
+
+func (block *FLACMetadataBlockSkipped) clone(flac *FLAC) IFLACMetadataBlock {
+	clone := *block
+	clone.FLACMetadataBlock = block.FLACMetadataBlock.cloneHeader(flac)
+
+	return &clone
+}
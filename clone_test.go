@@ -0,0 +1,37 @@
+package flac
+
+import (
+	"reflect"
+)
+
+func (suite *FLACTestSuite) TestClone() {
+	clone := suite.flac.Clone()
+
+	suite.assert.Equal(len(suite.flac.MetadataBlocks), len(clone.MetadataBlocks))
+	suite.assert.Nil(clone.file)
+
+	for index, block := range clone.MetadataBlocks {
+		suite.assert.True(block != suite.flac.MetadataBlocks[index])
+		suite.assert.True(block.header().FLAC == clone)
+		suite.assert.Equal(reflect.TypeOf(suite.flac.MetadataBlocks[index]), reflect.TypeOf(block))
+	}
+
+	suite.assert.Equal(suite.flac.VorbisComment().Comments, clone.VorbisComment().Comments)
+	suite.assert.Equal(suite.flac.FrontCover().Picture, clone.FrontCover().Picture)
+
+	clone.StreamInfo.UnencodedMD5[0] ^= 0xff
+	clone.VorbisComment().Comments[0].Value = "chips"
+	clone.VorbisComment().AddTag("title", "Song")
+	clone.FrontCover().Picture[0] = 0
+	clone.CueSheet().CueSheetTracks[0].CueSheetTrackIndices[0].Offset = 42
+	clone.SeekTable().SeekPoints[0].Sample = 42
+	clone.Applications()[0].AppData[0] = 0
+
+	suite.assert.NotEqual(suite.flac.StreamInfo.UnencodedMD5, clone.StreamInfo.UnencodedMD5)
+	suite.assert.Equal([]string{"fish"}, suite.flac.VorbisComment().GetTag("example"))
+	suite.assert.Equal(1, len(suite.flac.VorbisComment().Comments))
+	suite.assert.Equal(0xff, suite.flac.FrontCover().Picture[0])
+	suite.assert.Equal(0, suite.flac.CueSheet().CueSheetTracks[0].CueSheetTrackIndices[0].Offset)
+	suite.assert.Equal(0, suite.flac.SeekTable().SeekPoints[0].Sample)
+	suite.assert.Equal('C', suite.flac.Applications()[0].AppData[0])
+}
@@ -0,0 +1,443 @@
+package flac
+
+import (
+	"io"
+	"fmt"
+	"math"
+	"bufio"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Comment is a single NAME=value field of a vorbis comment block. Comments keep the order they are stored
+// in and a name may appear more than once.
+type Comment struct {
+	Key string `json:"key"`
+	Value string `json:"value"`
+}
+
+// validTagKey reports whether key is a valid vorbis comment field name: one or more ASCII characters
+// from 0x20 to 0x7D other than '='.
+func validTagKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for index := 0; index < len(key); index++ {
+		if key[index] < 0x20 || key[index] > 0x7d || key[index] == '=' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetTag returns the values of the field key in order, matched case-insensitively as the specification requires.
+func (block *FLACMetadataBlockVorbisComment) GetTag(key string) (values []string) {
+	for _, comment := range block.Comments {
+		if strings.EqualFold(comment.Key, key) {
+			values = append(values, comment.Value)
+		}
+	}
+
+	return
+}
+
+// SetTag replaces every value of the field key, whatever its case, with values. The new values take the place
+// of the first existing value and keep its case, or are appended with key as given if the field is new.
+// Setting no values removes the field.
+func (block *FLACMetadataBlockVorbisComment) SetTag(key string, values ...string) (err error) {
+	if !validTagKey(key) {
+		err = &InvalidTagKeyError{key}
+
+		return
+	}
+
+	position := -1
+	comments := make([]Comment, 0, len(block.Comments) + len(values))
+
+	for _, comment := range block.Comments {
+		if !strings.EqualFold(comment.Key, key) {
+			comments = append(comments, comment)
+		} else if position < 0 {
+			key = comment.Key
+			position = len(comments)
+		}
+	}
+
+	if position < 0 {
+		position = len(comments)
+	}
+
+	added := make([]Comment, len(values))
+
+	for index, value := range values {
+		added[index] = Comment{
+			Key: key,
+			Value: value,
+		}
+	}
+
+	block.Comments = slices.Insert(comments, position, added...)
+
+	return
+}
+
+// AddTag adds value to the field key after its existing values, keeping the case the field already has.
+// A new field is appended after the other comments.
+func (block *FLACMetadataBlockVorbisComment) AddTag(key string, value string) (err error) {
+	if !validTagKey(key) {
+		err = &InvalidTagKeyError{key}
+
+		return
+	}
+
+	position := len(block.Comments)
+
+	for index, comment := range block.Comments {
+		if strings.EqualFold(comment.Key, key) {
+			key = comment.Key
+			position = index + 1
+		}
+	}
+
+	block.Comments = slices.Insert(block.Comments, position, Comment{
+		Key: key,
+		Value: value,
+	})
+
+	return
+}
+
+// RemoveTag removes the field key, whatever its case, and returns the number of values removed.
+func (block *FLACMetadataBlockVorbisComment) RemoveTag(key string) (removed int) {
+	comments := block.Comments[:0]
+
+	for _, comment := range block.Comments {
+		if strings.EqualFold(comment.Key, key) {
+			removed++
+		} else {
+			comments = append(comments, comment)
+		}
+	}
+
+	block.Comments = comments
+
+	return
+}
+
+// Keys returns the distinct field names in the order they first appear, with the case of their first appearance.
+func (block *FLACMetadataBlockVorbisComment) Keys() (keys []string) {
+	seen := make(map[string]bool)
+
+	for _, comment := range block.Comments {
+		folded := strings.ToUpper(comment.Key)
+
+		if !seen[folded] {
+			seen[folded] = true
+			keys = append(keys, comment.Key)
+		}
+	}
+
+	return
+}
+
+// Map returns the comments grouped by upper-cased field name, each with its values in order.
+func (block *FLACMetadataBlockVorbisComment) Map() map[string][]string {
+	comments := make(map[string][]string)
+
+	for _, comment := range block.Comments {
+		key := strings.ToUpper(comment.Key)
+		comments[key] = append(comments[key], comment.Value)
+	}
+
+	return comments
+}
+
+// NormalizeKeys replaces every field name with the result of normalize, such as strings.ToUpper, and returns the
+// number of names changed. Names are otherwise kept exactly as they were read so that saving a file does not
+// change them.
+func (block *FLACMetadataBlockVorbisComment) NormalizeKeys(normalize func(string) string) (changed int) {
+	for index, comment := range block.Comments {
+		if key := normalize(comment.Key); key != comment.Key {
+			block.Comments[index].Key = key
+			changed++
+		}
+	}
+
+	return
+}
+
+// InvalidTagKeyError is returned when a vorbis comment field name contains characters the specification forbids.
+type InvalidTagKeyError struct {
+	Key string
+}
+
+func (err *InvalidTagKeyError) Error() string {
+	return "invalid vorbis comment field name " + strconv.Quote(err.Key)
+}
+
+// TagViolation describes a vorbis comment that breaks the rules of the specification. Index is the position
+// of the comment in Comments, or -1 for the vendor string and the comment count.
+type TagViolation struct {
+	Index int
+	Key string
+	Message string
+}
+
+func (violation TagViolation) String() string {
+	if violation.Index < 0 {
+		return violation.Message
+	}
+
+	return fmt.Sprintf("comment %d (%s): %s", violation.Index, violation.Key, violation.Message)
+}
+
+// ValidateTags checks that field names use only the characters 0x20 to 0x7D other than '=', that the vendor
+// string and values are valid UTF-8 and that every length fits its 32-bit length prefix.
+func (block *FLACMetadataBlockVorbisComment) ValidateTags() (violations []TagViolation) {
+	if !utf8.ValidString(block.VendorString) {
+		violations = append(violations, TagViolation{-1, "", "vendor string is not valid UTF-8"})
+	}
+
+	if uint64(len(block.VendorString)) > math.MaxUint32 {
+		violations = append(violations, TagViolation{-1, "", "vendor string is longer than a 32-bit length allows"})
+	}
+
+	if uint64(len(block.Comments)) > math.MaxUint32 {
+		violations = append(violations, TagViolation{-1, "", "more comments than a 32-bit count allows"})
+	}
+
+	for index, comment := range block.Comments {
+		if !validTagKey(comment.Key) {
+			violations = append(violations, TagViolation{index, comment.Key, fmt.Sprintf("field name %q contains invalid characters", comment.Key)})
+		}
+
+		if !utf8.ValidString(comment.Value) {
+			violations = append(violations, TagViolation{index, comment.Key, "value is not valid UTF-8"})
+		}
+
+		if uint64(len(comment.Key)) + 1 + uint64(len(comment.Value)) > math.MaxUint32 {
+			violations = append(violations, TagViolation{index, comment.Key, "comment is longer than a 32-bit length allows"})
+		}
+	}
+
+	return
+}
+
+// SanitizeTags makes the comments valid: characters not allowed in field names are dropped, invalid UTF-8 in
+// the vendor string and values is replaced with U+FFFD, and comments left without a name or too long to
+// store are removed. It returns the number of comments changed or removed.
+func (block *FLACMetadataBlockVorbisComment) SanitizeTags() (changed int) {
+	block.VendorString = strings.ToValidUTF8(block.VendorString, "\uFFFD")
+	comments := block.Comments[:0]
+
+	for _, comment := range block.Comments {
+		key := strings.Map(func(c rune) rune {
+			if c < 0x20 || c > 0x7d || c == '=' {
+				return -1
+			}
+
+			return c
+		}, comment.Key)
+		value := strings.ToValidUTF8(comment.Value, "\uFFFD")
+
+		if key != comment.Key || value != comment.Value {
+			changed++
+		}
+
+		if key == "" || uint64(len(key)) + 1 + uint64(len(value)) > math.MaxUint32 {
+			if key == comment.Key && value == comment.Value {
+				changed++
+			}
+
+			continue
+		}
+
+		comments = append(comments, Comment{
+			Key: key,
+			Value: value,
+		})
+	}
+
+	block.Comments = comments
+
+	return
+}
+
+// InvalidTagsError is returned when writing a vorbis comment block that fails ValidateTags.
+type InvalidTagsError struct {
+	Violations []TagViolation
+}
+
+func (err *InvalidTagsError) Error() string {
+	message := "invalid vorbis comments: " + err.Violations[0].String()
+
+	if len(err.Violations) > 1 {
+		message += fmt.Sprintf(" (and %d more)", len(err.Violations) - 1)
+	}
+
+	return message
+}
+
+// tagEscaper escapes values for ExportEscaped in the style of vorbiscomment --escapes.
+var tagEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r", "\x00", "\\0")
+
+// Export writes the comments as raw NAME=value lines, as metaflac --export-tags-to does. Values holding
+// newlines run over several lines, which Import joins back together.
+func (block *FLACMetadataBlockVorbisComment) Export(w io.Writer) error {
+	return block.export(w, false)
+}
+
+// ExportEscaped writes the comments as NAME=value lines with backslashes, newlines, carriage returns and NULs
+// in values escaped as \\, \n, \r and \0, as vorbiscomment --escapes does, so that multi-line values survive
+// ImportEscaped.
+func (block *FLACMetadataBlockVorbisComment) ExportEscaped(w io.Writer) error {
+	return block.export(w, true)
+}
+
+func (block *FLACMetadataBlockVorbisComment) export(w io.Writer, escape bool) (err error) {
+	writer := bufio.NewWriter(w)
+
+	for _, comment := range block.Comments {
+		value := comment.Value
+
+		if escape {
+			value = tagEscaper.Replace(value)
+		}
+
+		_, err = writer.WriteString(comment.Key + "=" + value + "\n")
+
+		if err != nil {
+			return
+		}
+	}
+
+	return writer.Flush()
+}
+
+// unescapeTag reverses the escaping done by ExportEscaped.
+func unescapeTag(value string) (unescaped string, err error) {
+	if !strings.Contains(value, "\\") {
+		return value, nil
+	}
+
+	var builder strings.Builder
+
+	for index := 0; index < len(value); index++ {
+		if value[index] != '\\' {
+			builder.WriteByte(value[index])
+
+			continue
+		}
+
+		index++
+
+		if index == len(value) {
+			err = errors.New("value ends with an unfinished escape")
+
+			return
+		}
+
+		switch value[index] {
+			case '\\':
+				builder.WriteByte('\\')
+
+			case 'n':
+				builder.WriteByte('\n')
+
+			case 'r':
+				builder.WriteByte('\r')
+
+			case '0':
+				builder.WriteByte(0)
+
+			default:
+				err = fmt.Errorf("unknown escape \\%c", value[index])
+
+				return
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// Import reads raw NAME=value lines in the format written by Export and metaflac --export-tags-to and appends
+// them to the comments, as metaflac --import-tags-from does. A line that does not start with a field name and
+// '=' continues the previous value after a newline. Blank lines between comments are skipped; a continuation
+// before the first comment fails the import without changing the comments.
+func (block *FLACMetadataBlockVorbisComment) Import(r io.Reader) error {
+	return block.importTags(r, false)
+}
+
+// ImportEscaped reads NAME=value lines escaped as ExportEscaped and vorbiscomment --escapes write them and
+// appends them to the comments as Import does. As values cannot span lines, lines without a field name and
+// unknown escapes fail the import.
+func (block *FLACMetadataBlockVorbisComment) ImportEscaped(r io.Reader) error {
+	return block.importTags(r, true)
+}
+
+func (block *FLACMetadataBlockVorbisComment) importTags(r io.Reader, escaped bool) (err error) {
+	var comments []Comment
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxBlockLength)
+	line := 0
+	blank := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if text == "" {
+			blank++
+
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+
+		if (!ok || !validTagKey(key)) && !escaped && len(comments) > 0 {
+			comments[len(comments) - 1].Value += strings.Repeat("\n", blank + 1) + text
+			blank = 0
+
+			continue
+		}
+
+		blank = 0
+
+		if !ok || !validTagKey(key) {
+			err = fmt.Errorf("line %d: malformed comment", line)
+
+			return
+		}
+
+		if escaped {
+			value, err = unescapeTag(value)
+
+			if err != nil {
+				err = fmt.Errorf("line %d: %w", line, err)
+
+				return
+			}
+		}
+
+		comments = append(comments, Comment{
+			Key: key,
+			Value: value,
+		})
+	}
+
+	err = scanner.Err()
+
+	if err != nil {
+		return
+	}
+
+	block.Comments = append(block.Comments, comments...)
+
+	return
+}
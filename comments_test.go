@@ -0,0 +1,174 @@
+package flac
+
+import (
+	"bytes"
+	"strings"
+)
+
+func (suite *FLACTestSuite) TestTags() {
+	comments := suite.flac.VorbisComment()
+
+	suite.assert.Equal([]string{"fish"}, comments.GetTag("EXAMPLE"))
+	suite.assert.Equal([]string{"fish"}, comments.GetTag("Example"))
+	suite.assert.Empty(comments.GetTag("TITLE"))
+
+	suite.assert.NoError(comments.AddTag("ARTIST", "Someone"))
+	suite.assert.NoError(comments.AddTag("EXAMPLE", "chips"))
+	suite.assert.Equal([]string{"fish", "chips"}, comments.GetTag("example"))
+	suite.assert.Equal([]Comment{{"example", "fish"}, {"example", "chips"}, {"ARTIST", "Someone"}}, comments.Comments)
+
+	suite.assert.NoError(comments.SetTag("Example", "peas"))
+	suite.assert.Equal([]Comment{{"example", "peas"}, {"ARTIST", "Someone"}}, comments.Comments)
+
+	suite.assert.Equal(1, comments.RemoveTag("artist"))
+	suite.assert.Equal(0, comments.RemoveTag("artist"))
+
+	suite.assert.NoError(comments.SetTag("EXAMPLE"))
+	suite.assert.Equal(0, len(comments.Comments))
+
+	var invalid *InvalidTagKeyError
+
+	err := comments.SetTag("A=B", "value")
+
+	suite.assert.IsType(invalid, err)
+	suite.assert.Error(comments.AddTag("", "value"))
+	suite.assert.Error(comments.AddTag("CAFÉ", "value"))
+
+	empty := &FLACMetadataBlockVorbisComment{}
+
+	suite.assert.NoError(empty.AddTag("TITLE", "Song"))
+	suite.assert.Equal([]string{"Song"}, empty.GetTag("title"))
+}
+
+func (suite *FLACTestSuite) TestCommentOrder() {
+	comments := NewVorbisComment("go-flac")
+
+	comments.AddTag("TITLE", "Song")
+	comments.AddTag("ARTIST", "B")
+	comments.AddTag("artist", "A")
+	comments.AddTag("ALBUM", "Record")
+	comments.AddTag("Artist", "C")
+
+	suite.assert.Equal([]string{"TITLE", "ARTIST", "ALBUM"}, comments.Keys())
+	suite.assert.Equal(map[string][]string{"TITLE": {"Song"}, "ARTIST": {"B", "A", "C"}, "ALBUM": {"Record"}}, comments.Map())
+
+	comments.Comments = append(comments.Comments, Comment{"TITLE", "Again"})
+
+	data, err := comments.MarshalBinary()
+
+	suite.assert.NoError(err)
+
+	decoded := &FLACMetadataBlockVorbisComment{}
+
+	suite.assert.NoError(decoded.UnmarshalBinary(data))
+	suite.assert.Equal(comments.Comments, decoded.Comments)
+}
+
+func (suite *FLACTestSuite) TestKeyCase() {
+	path, _ := suite.copySample()
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	comments := flac.VorbisComment()
+
+	comments.AddTag("Album Artist", "Someone")
+	comments.SetTitle("Song")
+	comments.SetTag("EXAMPLE", "chips")
+
+	suite.assert.NoError(flac.Save())
+
+	saved, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer saved.Close()
+
+	comments = saved.VorbisComment()
+
+	suite.assert.Equal([]string{"example", "Album Artist", "TITLE"}, comments.Keys())
+	suite.assert.Equal([]string{"Someone"}, comments.GetTag("ALBUM ARTIST"))
+	suite.assert.Equal(2, comments.NormalizeKeys(strings.ToUpper))
+	suite.assert.Equal([]string{"EXAMPLE", "ALBUM ARTIST", "TITLE"}, comments.Keys())
+}
+
+func (suite *FLACTestSuite) TestValidateTags() {
+	comments := suite.flac.VorbisComment()
+
+	suite.assert.Empty(comments.ValidateTags())
+
+	comments.Comments = append(comments.Comments, Comment{"BAD=KEY", "value"}, Comment{"TITLE", "bad \xff"}, Comment{"\x01", "gone"})
+
+	violations := comments.ValidateTags()
+
+	suite.assert.Equal([]TagViolation{
+		{1, "BAD=KEY", `field name "BAD=KEY" contains invalid characters`},
+		{2, "TITLE", "value is not valid UTF-8"},
+		{3, "\x01", `field name "\x01" contains invalid characters`},
+	}, violations)
+	suite.assert.Equal("comment 2 (TITLE): value is not valid UTF-8", violations[1].String())
+
+	_, err := comments.MarshalBinary()
+
+	suite.assert.IsType(&InvalidTagsError{}, err)
+
+	path, _ := suite.copySample()
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	flac.VorbisComment().Comments = comments.Comments
+
+	suite.assert.Error(flac.Save())
+	suite.assert.NoError(flac.Save(WithSanitizeTags()))
+	suite.assert.Equal([]Comment{{"example", "fish"}, {"BADKEY", "value"}, {"TITLE", "bad �"}}, flac.VorbisComment().Comments)
+}
+
+func (suite *FLACTestSuite) TestExportImportTags() {
+	comments := NewVorbisComment("go-flac")
+
+	comments.AddTag(TitleTag, "Song")
+	comments.AddTag(LyricsTag, "line one\nline two\\three")
+	comments.AddTag(ArtistTag, "A=B")
+
+	var buffer bytes.Buffer
+
+	suite.assert.NoError(comments.ExportEscaped(&buffer))
+	suite.assert.Equal("TITLE=Song\nLYRICS=line one\\nline two\\\\three\nARTIST=A=B\n", buffer.String())
+
+	imported := NewVorbisComment("go-flac")
+
+	imported.AddTag("EXAMPLE", "fish")
+
+	suite.assert.NoError(imported.ImportEscaped(strings.NewReader(buffer.String() + "\r\n")))
+	suite.assert.Equal(append([]Comment{{"EXAMPLE", "fish"}}, comments.Comments...), imported.Comments)
+
+	suite.assert.Error(imported.ImportEscaped(strings.NewReader("TITLE=ok\nno separator\n")))
+	suite.assert.Error(imported.ImportEscaped(strings.NewReader("TITLE=bad \\q\n")))
+	suite.assert.Equal(4, len(imported.Comments))
+
+	raw := NewVorbisComment("go-flac")
+
+	raw.AddTag("PATH", "C:\\Music")
+	raw.AddTag(ArtistTag, "AC\\DC")
+	buffer.Reset()
+
+	suite.assert.NoError(raw.Export(&buffer))
+	suite.assert.Equal("PATH=C:\\Music\nARTIST=AC\\DC\n", buffer.String())
+
+	raw.AddTag(LyricsTag, "line one\n\nline two")
+	buffer.Reset()
+
+	suite.assert.NoError(raw.Export(&buffer))
+
+	imported = NewVorbisComment("go-flac")
+
+	suite.assert.NoError(imported.Import(strings.NewReader(buffer.String() + "\n")))
+	suite.assert.Equal(raw.Comments, imported.Comments)
+	suite.assert.Error(imported.Import(strings.NewReader("no separator\nTITLE=ok\n")))
+	suite.assert.Equal(3, len(imported.Comments))
+}
@@ -0,0 +1,275 @@
+package flac
+
+import (
+	"fmt"
+	"slices"
+	"unicode/utf8"
+)
+
+// RequirementLevel is the RFC 2119 keyword a conformance violation breaks.
+type RequirementLevel uint
+
+// Enum indicating the requirement level of a violation.
+const (
+	Must RequirementLevel = iota
+	Should
+)
+
+func (level RequirementLevel) String() string {
+	if level == Should {
+		return "SHOULD"
+	}
+
+	return "MUST"
+}
+
+// Violation describes a departure from RFC 9639, keyed by the section of the specification it breaks.
+type Violation struct {
+	Section string
+	Level RequirementLevel
+	Block int
+	Message string
+}
+
+func (violation Violation) String() string {
+	return fmt.Sprintf("RFC 9639 section %s (%s): block %d: %s", violation.Section, violation.Level, violation.Block, violation.Message)
+}
+
+// Conformance evaluates the parsed metadata against RFC 9639 and returns the violations found.
+// Block numbers are 0 for STREAMINFO and count up through MetadataBlocks.
+func (flac *FLAC) Conformance() (violations []Violation) {
+	report := func(section string, level RequirementLevel, block int, format string, args ...interface{}) {
+		violations = append(violations, Violation{
+			Section: section,
+			Level: level,
+			Block: block,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if flac.StreamInfo == nil {
+		report("8.2", Must, 0, "missing STREAMINFO block")
+
+		return
+	}
+
+	streamInfo := flac.StreamInfo
+
+	if streamInfo.MinBlockSize < 16 {
+		report("8.2", Must, 0, "minimum block size %d is below 16", streamInfo.MinBlockSize)
+	}
+
+	if streamInfo.MaxBlockSize < 16 {
+		report("8.2", Must, 0, "maximum block size %d is below 16", streamInfo.MaxBlockSize)
+	}
+
+	if streamInfo.MinBlockSize > streamInfo.MaxBlockSize {
+		report("8.2", Must, 0, "minimum block size %d exceeds maximum block size %d", streamInfo.MinBlockSize, streamInfo.MaxBlockSize)
+	}
+
+	if streamInfo.MinFrameSize != 0 && streamInfo.MaxFrameSize != 0 && streamInfo.MinFrameSize > streamInfo.MaxFrameSize {
+		report("8.2", Must, 0, "minimum frame size %d exceeds maximum frame size %d", streamInfo.MinFrameSize, streamInfo.MaxFrameSize)
+	}
+
+	if streamInfo.SampleRate == 0 {
+		report("8.2", Must, 0, "sample rate is 0")
+	}
+
+	if streamInfo.BitsPerSample < 4 {
+		report("8.2", Must, 0, "%d bits per sample is below 4", streamInfo.BitsPerSample)
+	}
+
+	if streamInfo.NumSamples == 0 {
+		report("8.2", Should, 0, "total number of samples is unknown")
+	}
+
+	violations = append(violations, layoutViolations(slices.Collect(flac.Blocks()))...)
+
+	for index, iBlock := range flac.MetadataBlocks {
+		blockNumber := index + 1
+
+		switch block := iBlock.(type) {
+			case *FLACMetadataBlockSeekTable:
+				block.conformance(blockNumber, report)
+
+			case *FLACMetadataBlockVorbisComment:
+				block.conformance(blockNumber, report)
+
+			case *FLACMetadataBlockPicture:
+				if block.Type == FileIcon && (block.MIMEType != "image/png" || block.Width != 32 || block.Height != 32) {
+					report("8.8", Must, blockNumber, "file icon is not a 32x32 PNG")
+				}
+
+				for _, c := range block.MIMEType {
+					if c < 0x20 || c > 0x7e {
+						report("8.8", Must, blockNumber, "MIME type contains characters outside printable ASCII")
+
+						break
+					}
+				}
+
+				if !utf8.ValidString(block.Description) {
+					report("8.8", Must, blockNumber, "description is not valid UTF-8")
+				}
+
+			case *FLACMetadataBlockReserved:
+				report("8.1", Should, blockNumber, "reserved block type %d", block.FLACMetadataBlock.Type)
+		}
+	}
+
+	return
+}
+
+// layoutViolations checks the structural rules a sequence of blocks must follow to be written: STREAMINFO first
+// and only once, at most one SEEKTABLE and VORBIS_COMMENT block, at most one picture of each icon type, and
+// well-formed cue sheets. Block numbers are positions in blocks.
+func layoutViolations(blocks []IFLACMetadataBlock) (violations []Violation) {
+	report := func(section string, level RequirementLevel, block int, format string, args ...interface{}) {
+		violations = append(violations, Violation{
+			Section: section,
+			Level: level,
+			Block: block,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	counts := make(map[BlockType]int)
+	pictureTypes := make(map[PictureType]int)
+
+	for blockNumber, iBlock := range blocks {
+		blockType := iBlock.header().Type
+		counts[blockType]++
+
+		switch {
+			case blockNumber == 0 && blockType != StreamInfo:
+				report("8.2", Must, blockNumber, "first block is of type %d, not STREAMINFO", blockType)
+
+			case blockNumber > 0 && blockType == StreamInfo:
+				report("8.2", Must, blockNumber, "additional STREAMINFO block")
+
+			case blockType == SeekTable && counts[blockType] > 1:
+				report("8.5", Must, blockNumber, "more than one SEEKTABLE block")
+
+			case blockType == VorbisComment && counts[blockType] > 1:
+				report("8.6", Must, blockNumber, "more than one VORBIS_COMMENT block")
+		}
+
+		switch block := iBlock.(type) {
+			case *FLACMetadataBlockCueSheet:
+				block.conformance(blockNumber, report)
+
+			case *FLACMetadataBlockPicture:
+				pictureTypes[block.Type]++
+
+				if (block.Type == FileIcon || block.Type == OtherFileIcon) && pictureTypes[block.Type] > 1 {
+					report("8.8", Must, blockNumber, "more than one picture of type %d", block.Type)
+				}
+		}
+	}
+
+	return
+}
+
+// ValidationError is returned when metadata to be written breaks the structural rules of RFC 9639.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (err *ValidationError) Error() string {
+	message := "invalid metadata layout: " + err.Violations[0].String()
+
+	if len(err.Violations) > 1 {
+		message += fmt.Sprintf(" (and %d more)", len(err.Violations) - 1)
+	}
+
+	return message
+}
+
+func (block *FLACMetadataBlockSeekTable) conformance(blockNumber int, report func(string, RequirementLevel, int, string, ...interface{})) {
+	for index := 1; index < len(block.SeekPoints); index++ {
+		previous := block.SeekPoints[index - 1]
+		current := block.SeekPoints[index]
+
+		if previous.Sample == SeekPlaceholder {
+			if current.Sample != SeekPlaceholder {
+				report("8.5", Must, blockNumber, "seek point %d follows a placeholder point", index)
+			}
+
+			continue
+		}
+
+		if current.Sample == previous.Sample {
+			report("8.5", Must, blockNumber, "seek point %d duplicates sample %d", index, current.Sample)
+		} else if current.Sample != SeekPlaceholder && current.Sample < previous.Sample {
+			report("8.5", Must, blockNumber, "seek point %d is not in ascending sample order", index)
+		}
+	}
+}
+
+func (block *FLACMetadataBlockVorbisComment) conformance(blockNumber int, report func(string, RequirementLevel, int, string, ...interface{})) {
+	for _, violation := range block.ValidateTags() {
+		report("8.6", Must, blockNumber, "%s", violation)
+	}
+}
+
+func (block *FLACMetadataBlockCueSheet) conformance(blockNumber int, report func(string, RequirementLevel, int, string, ...interface{})) {
+	numTracks := len(block.CueSheetTracks)
+
+	if numTracks == 0 {
+		report("8.7", Must, blockNumber, "cue sheet has no lead-out track")
+
+		return
+	}
+
+	if block.IsCD && numTracks > 100 {
+		report("8.7", Must, blockNumber, "CD-DA cue sheet has %d tracks, more than 100", numTracks)
+	}
+
+	leadOut := block.CueSheetTracks[numTracks - 1]
+
+	if block.IsCD && leadOut.Track != 170 {
+		report("8.7.1", Must, blockNumber, "CD-DA lead-out track number is %d, not 170", leadOut.Track)
+	} else if !block.IsCD && leadOut.Track != 255 {
+		report("8.7.1", Must, blockNumber, "lead-out track number is %d, not 255", leadOut.Track)
+	}
+
+	seen := make(map[uint8]bool)
+
+	for index, track := range block.CueSheetTracks {
+		if track.Track == 0 {
+			report("8.7.1", Must, blockNumber, "track %d has track number 0", index)
+		}
+
+		if seen[track.Track] {
+			report("8.7.1", Must, blockNumber, "track number %d is not unique", track.Track)
+		}
+
+		seen[track.Track] = true
+
+		if block.IsCD && track.Offset % 588 != 0 {
+			report("8.7.1", Must, blockNumber, "CD-DA track %d offset is not a multiple of 588 samples", track.Track)
+		}
+
+		if index < numTracks - 1 && len(track.CueSheetTrackIndices) == 0 {
+			report("8.7.1", Must, blockNumber, "track %d has no index points", track.Track)
+		}
+
+		if index == numTracks - 1 && len(track.CueSheetTrackIndices) != 0 {
+			report("8.7.1", Must, blockNumber, "lead-out track has index points")
+		}
+
+		for indexIndex, trackIndex := range track.CueSheetTrackIndices {
+			if indexIndex == 0 && trackIndex.IndexNumber > 1 {
+				report("8.7.1.1", Must, blockNumber, "first index point of track %d is %d, not 0 or 1", track.Track, trackIndex.IndexNumber)
+			}
+
+			if indexIndex > 0 && trackIndex.IndexNumber != track.CueSheetTrackIndices[indexIndex - 1].IndexNumber + 1 {
+				report("8.7.1.1", Must, blockNumber, "index points of track %d are not sequential", track.Track)
+			}
+
+			if block.IsCD && trackIndex.Offset % 588 != 0 {
+				report("8.7.1.1", Must, blockNumber, "CD-DA index offset of track %d is not a multiple of 588 samples", track.Track)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package flac
+
+import (
+	"io"
+	"errors"
+)
+
+func (suite *FLACTestSuite) TestConformance() {
+	suite.assert.Equal(0, len(suite.flac.Conformance()))
+
+	suite.flac.StreamInfo.MinBlockSize = 8
+
+	for _, iBlock := range suite.flac.MetadataBlocks {
+		if block, ok := iBlock.(*FLACMetadataBlockCueSheet); ok {
+			block.IsCD = true
+		}
+	}
+
+	violations := suite.flac.Conformance()
+
+	suite.assert.Equal(3, len(violations))
+	suite.assert.Equal("8.2", violations[0].Section)
+	suite.assert.Equal(Must, violations[0].Level)
+	suite.assert.Equal(0, violations[0].Block)
+	suite.assert.Equal("8.7.1", violations[1].Section)
+	suite.assert.Equal("8.7.1", violations[2].Section)
+	suite.assert.Equal("RFC 9639 section 8.7.1 (MUST): block 5: CD-DA lead-out track number is 255, not 170", violations[1].String())
+}
+
+func (suite *FLACTestSuite) TestLayoutValidation() {
+	flac := suite.flac.Clone()
+
+	suite.assert.NoError(flac.AppendBlock(NewVorbisComment("second")))
+	suite.assert.NoError(flac.InsertBlock(0, NewPadding(8)))
+
+	_, err := flac.WriteMetadata(io.Discard)
+
+	var validation *ValidationError
+
+	suite.assert.True(errors.As(err, &validation))
+	suite.assert.Equal(1, len(validation.Violations))
+	suite.assert.Equal(8, validation.Violations[0].Block)
+	suite.assert.Equal("8.6", validation.Violations[0].Section)
+
+	flac.RemoveBlock(len(flac.MetadataBlocks) - 1)
+	flac.CueSheet().CueSheetTracks = nil
+
+	_, err = flac.WriteMetadata(io.Discard)
+
+	suite.assert.True(errors.As(err, &validation))
+	suite.assert.Equal("RFC 9639 section 8.7 (MUST): block 6: cue sheet has no lead-out track", validation.Violations[0].String())
+
+	_, _, err = encodeMetadata([]IFLACMetadataBlock{NewPadding(0), flac.StreamInfo})
+
+	suite.assert.True(errors.As(err, &validation))
+	suite.assert.Equal(2, len(validation.Violations))
+}
@@ -0,0 +1,121 @@
+package flac
+
+import (
+	"io"
+	"os"
+	"fmt"
+	"path"
+	"bytes"
+	"errors"
+	"io/fs"
+	"strings"
+	"net/http"
+	"encoding/hex"
+)
+
+// CoverArtHandler is an http.Handler serving the front cover of the FLAC files in FS, addressed by their slash
+// separated path relative to its root. Only the metadata is parsed and the picture is streamed from the file
+// without being loaded. Responses carry the picture's MIME type, an ETag from the file's modification time and
+// the picture's position, and the modification time, and honour conditional and range requests.
+type CoverArtHandler struct {
+	FS fs.FS
+}
+
+// NewCoverArtHandler returns a handler serving the front covers of the FLAC files under the root directory.
+func NewCoverArtHandler(root string) *CoverArtHandler {
+	return &CoverArtHandler{
+		FS: os.DirFS(root),
+	}
+}
+
+func (handler *CoverArtHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/" + r.URL.Path), "/")
+
+	if !fs.ValidPath(name) || !strings.EqualFold(path.Ext(name), ".flac") {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	file, err := handler.FS.Open(name)
+
+	if err != nil {
+		serveError(w, r, err)
+
+		return
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		serveError(w, r, err)
+
+		return
+	}
+
+	flac, err := ParseReader(file, WithLazyPictures(), WithBlockTypes(Picture))
+
+	if err != nil {
+		serveError(w, r, err)
+
+		return
+	}
+
+	block := flac.FrontCover()
+
+	if block == nil || block.IsURL() {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	var content io.ReadSeeker
+	readerAt, ok := file.(io.ReaderAt)
+
+	switch {
+		case block.Loaded():
+			content = bytes.NewReader(block.Picture)
+
+		case ok:
+			content = io.NewSectionReader(readerAt, block.PictureOffset, int64(block.PictureLength))
+
+		default:
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", block.MIMEType)
+
+	if len(block.PictureMD5) > 0 {
+		header.Set("ETag", `"` + hex.EncodeToString(block.PictureMD5) + `"`)
+	} else {
+		header.Set("ETag", fmt.Sprintf(`"%x-%x-%x"`, info.ModTime().UnixNano(), block.PictureOffset, block.PictureLength))
+	}
+
+	http.ServeContent(w, r, "", info.ModTime(), content)
+}
+
+// serveError responds to a failure opening or parsing a file for CoverArtHandler.
+func serveError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+		case errors.Is(err, fs.ErrNotExist):
+			http.NotFound(w, r)
+
+		case errors.Is(err, fs.ErrPermission):
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+
+		default:
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,63 @@
+package flac
+
+import (
+	"os"
+	"net/http"
+	"path/filepath"
+	"net/http/httptest"
+)
+
+func (suite *FLACTestSuite) TestCoverArtHandler() {
+	dir := suite.T().TempDir()
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+	suite.assert.NoError(os.MkdirAll(filepath.Join(dir, "album"), 0755))
+	suite.assert.NoError(os.WriteFile(filepath.Join(dir, "album", "track.flac"), data, 0644))
+	suite.assert.NoError(os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0644))
+
+	handler := NewCoverArtHandler(dir)
+	cover := suite.flac.FrontCover()
+	serve := func(method string, target string, header map[string]string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, target, nil)
+
+		for key, value := range header {
+			request.Header.Set(key, value)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	response := serve(http.MethodGet, "/album/track.flac", nil)
+
+	suite.assert.Equal(http.StatusOK, response.Code)
+	suite.assert.Equal("image/jpeg", response.Header().Get("Content-Type"))
+	suite.assert.Equal(cover.Picture, response.Body.Bytes())
+
+	etag := response.Header().Get("ETag")
+
+	suite.assert.NotEmpty(etag)
+
+	response = serve(http.MethodGet, "/album/track.flac", map[string]string{"Range": "bytes=0-3"})
+
+	suite.assert.Equal(http.StatusPartialContent, response.Code)
+	suite.assert.Equal(cover.Picture[:4], response.Body.Bytes())
+	suite.assert.Equal("image/jpeg", response.Header().Get("Content-Type"))
+
+	response = serve(http.MethodGet, "/album/track.flac", map[string]string{"If-None-Match": etag})
+
+	suite.assert.Equal(http.StatusNotModified, response.Code)
+
+	response = serve(http.MethodHead, "/album/track.flac", nil)
+
+	suite.assert.Equal(http.StatusOK, response.Code)
+	suite.assert.Empty(response.Body.Bytes())
+
+	suite.assert.Equal(http.StatusNotFound, serve(http.MethodGet, "/album/missing.flac", nil).Code)
+	suite.assert.Equal(http.StatusNotFound, serve(http.MethodGet, "/notes.txt", nil).Code)
+	suite.assert.Equal(http.StatusNotFound, serve(http.MethodGet, "/../sample.flac", nil).Code)
+	suite.assert.Equal(http.StatusMethodNotAllowed, serve(http.MethodPost, "/album/track.flac", nil).Code)
+}
@@ -0,0 +1,142 @@
+package flac
+
+import (
+	"fmt"
+	"errors"
+	"strings"
+)
+
+const (
+	// SamplesPerCDSector is the number of samples in one CD-DA sector (1/75 second at 44.1kHz).
+	SamplesPerCDSector = 588
+
+	// CDLeadInSamples is the number of lead-in samples recorded for CD-DA cue sheets.
+	CDLeadInSamples = 88200
+
+	// CDLeadOutTrack is the track number of the lead-out track on CD-DA cue sheets.
+	CDLeadOutTrack = 170
+)
+
+// CDTrack is one track of a CD table of contents as read by a ripper.
+// LBA is the sector of index 1; Pregap is the number of sectors of index 0 preceding it.
+type CDTrack struct {
+	Number uint8
+	LBA uint32
+	Pregap uint32
+	ISRC string
+	Data bool
+	PreEmphasis bool
+}
+
+// CDTOC is a CD table of contents. LBAs are relative to the start of the first track's audio.
+type CDTOC struct {
+	CatalogNumber string
+	Tracks []CDTrack
+	LeadOut uint32
+}
+
+func padNUL(value string, length int) string {
+	return value + strings.Repeat("\x00", length - len(value))
+}
+
+// NewCueSheetFromTOC builds a CD-DA cue sheet block from a table of contents.
+func NewCueSheetFromTOC(toc CDTOC) (block *FLACMetadataBlockCueSheet, err error) {
+	if len(toc.Tracks) == 0 || len(toc.Tracks) > 99 {
+		err = fmt.Errorf("table of contents has %d tracks, expected 1 to 99", len(toc.Tracks))
+
+		return
+	}
+
+	if len(toc.CatalogNumber) != 0 && (len(toc.CatalogNumber) != 13 || strings.Trim(toc.CatalogNumber, "0123456789") != "") {
+		err = errors.New("media catalog number must be 13 digits")
+
+		return
+	}
+
+	block = &FLACMetadataBlockCueSheet{
+		FLACMetadataBlock: FLACMetadataBlock{
+			Type: CueSheet,
+		},
+		MediaCatalogNumber: padNUL(toc.CatalogNumber, 128),
+		NumLeadInSamples: CDLeadInSamples,
+		IsCD: true,
+	}
+
+	dataLength := 396
+	previousLBA := uint32(0)
+	previousNumber := uint8(0)
+
+	for index, track := range toc.Tracks {
+		if track.Number == 0 || track.Number > 99 {
+			err = fmt.Errorf("invalid track number %d", track.Number)
+
+			return
+		}
+
+		if track.Number == previousNumber {
+			err = fmt.Errorf("track number %d is not unique", track.Number)
+
+			return
+		}
+
+		if track.Number < previousNumber {
+			err = fmt.Errorf("track number %d follows track number %d", track.Number, previousNumber)
+
+			return
+		}
+
+		if track.Pregap > track.LBA || index > 0 && track.LBA - track.Pregap < previousLBA {
+			err = fmt.Errorf("track %d starts before the previous track", track.Number)
+
+			return
+		}
+
+		if len(track.ISRC) != 0 && len(track.ISRC) != 12 {
+			err = fmt.Errorf("track %d ISRC must be 12 characters", track.Number)
+
+			return
+		}
+
+		cueTrack := CueSheetTrack{
+			Offset: uint64(track.LBA - track.Pregap) * SamplesPerCDSector,
+			Track: track.Number,
+			ISRC: padNUL(track.ISRC, 12),
+			IsAudio: !track.Data,
+			PreEmphasis: track.PreEmphasis,
+		}
+
+		if track.Pregap > 0 {
+			cueTrack.CueSheetTrackIndices = append(cueTrack.CueSheetTrackIndices, CueSheetTrackIndex{
+				Offset: 0,
+				IndexNumber: 0,
+			})
+		}
+
+		cueTrack.CueSheetTrackIndices = append(cueTrack.CueSheetTrackIndices, CueSheetTrackIndex{
+			Offset: uint64(track.Pregap) * SamplesPerCDSector,
+			IndexNumber: 1,
+		})
+
+		block.CueSheetTracks = append(block.CueSheetTracks, cueTrack)
+		dataLength += 36 + 12 * len(cueTrack.CueSheetTrackIndices)
+		previousLBA = track.LBA
+		previousNumber = track.Number
+	}
+
+	if toc.LeadOut <= previousLBA {
+		err = errors.New("lead-out must follow the last track")
+
+		return
+	}
+
+	block.CueSheetTracks = append(block.CueSheetTracks, CueSheetTrack{
+		Offset: uint64(toc.LeadOut) * SamplesPerCDSector,
+		Track: CDLeadOutTrack,
+		ISRC: padNUL("", 12),
+		IsAudio: true,
+	})
+
+	block.FLACMetadataBlock.DataLength = uint32(dataLength + 36)
+
+	return
+}
@@ -0,0 +1,79 @@
+package flac
+
+import (
+	"fmt"
+)
+
+func (suite *FLACTestSuite) TestNewCueSheetFromTOC() {
+	block, err := NewCueSheetFromTOC(CDTOC{
+		CatalogNumber: "0123456789012",
+		Tracks: []CDTrack{
+			{Number: 1, LBA: 0, ISRC: "USABC0000001"},
+			{Number: 2, LBA: 15000, Pregap: 150},
+			{Number: 3, LBA: 30000, PreEmphasis: true},
+		},
+		LeadOut: 45000,
+	})
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(CueSheet, block.FLACMetadataBlock.Type)
+	suite.assert.Equal(396 + 4 * 36 + 4 * 12, block.FLACMetadataBlock.DataLength)
+	suite.assert.Equal(128, len(block.MediaCatalogNumber))
+	suite.assert.Equal(CDLeadInSamples, block.NumLeadInSamples)
+	suite.assert.True(block.IsCD)
+	suite.assert.Equal(4, len(block.CueSheetTracks))
+	suite.assert.Equal("USABC0000001", block.CueSheetTracks[0].ISRC)
+	suite.assert.Equal(14850 * 588, block.CueSheetTracks[1].Offset)
+	suite.assert.Equal([]CueSheetTrackIndex{{0, 0}, {150 * 588, 1}}, block.CueSheetTracks[1].CueSheetTrackIndices)
+	suite.assert.True(block.CueSheetTracks[2].PreEmphasis)
+	suite.assert.Equal(CDLeadOutTrack, block.CueSheetTracks[3].Track)
+	suite.assert.Equal(45000 * 588, block.CueSheetTracks[3].Offset)
+
+	flac := &FLAC{
+		StreamInfo: suite.flac.StreamInfo,
+		MetadataBlocks: []IFLACMetadataBlock{block},
+	}
+
+	for _, violation := range flac.Conformance() {
+		suite.assert.NotEqual("8.7.1", violation.Section, violation.Message)
+		suite.assert.NotEqual("8.7.1.1", violation.Section, violation.Message)
+	}
+
+	_, err = NewCueSheetFromTOC(CDTOC{
+		Tracks: []CDTrack{{Number: 1, LBA: 100}, {Number: 2, LBA: 50}},
+		LeadOut: 200,
+	})
+
+	suite.assert.Error(err)
+
+	_, err = NewCueSheetFromTOC(CDTOC{
+		Tracks: []CDTrack{{Number: 1, LBA: 0}},
+		LeadOut: 0,
+	})
+
+	suite.assert.Error(err)
+
+	_, err = NewCueSheetFromTOC(CDTOC{
+		Tracks: []CDTrack{{Number: 1, LBA: 0}, {Number: 1, LBA: 100}},
+		LeadOut: 200,
+	})
+
+	suite.assert.Error(err)
+	suite.assert.Contains(fmt.Sprint(err), "track number 1 is not unique")
+
+	_, err = NewCueSheetFromTOC(CDTOC{
+		Tracks: []CDTrack{{Number: 2, LBA: 0}, {Number: 1, LBA: 100}},
+		LeadOut: 200,
+	})
+
+	suite.assert.Error(err)
+	suite.assert.Contains(fmt.Sprint(err), "track number 1 follows track number 2")
+
+	_, err = NewCueSheetFromTOC(CDTOC{
+		CatalogNumber: "012345678901X",
+		Tracks: []CDTrack{{Number: 1, LBA: 0}},
+		LeadOut: 200,
+	})
+
+	suite.assert.Error(err)
+}
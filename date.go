@@ -0,0 +1,144 @@
+package flac
+
+import (
+	"fmt"
+	"time"
+	"strconv"
+	"strings"
+)
+
+// PartialDate is a date that may only be known to the year or month, as DATE fields often are.
+// A zero Month or Day means that part is unknown.
+type PartialDate struct {
+	Year int
+	Month time.Month
+	Day int
+}
+
+// IsZero reports whether no date is set.
+func (date PartialDate) IsZero() bool {
+	return date.Year == 0
+}
+
+// String formats the date in ISO 8601 as "2007", "2007-02" or "2007-02-13", or returns "" for the zero date.
+func (date PartialDate) String() string {
+	switch {
+		case date.Year == 0:
+			return ""
+
+		case date.Month == 0:
+			return fmt.Sprintf("%04d", date.Year)
+
+		case date.Day == 0:
+			return fmt.Sprintf("%04d-%02d", date.Year, date.Month)
+	}
+
+	return fmt.Sprintf("%04d-%02d-%02d", date.Year, date.Month, date.Day)
+}
+
+// valid reports whether the month and day exist, treating zeros as unknown.
+func (date PartialDate) valid() bool {
+	if date.Month < 0 || date.Month > 12 || date.Day < 0 || (date.Month == 0 && date.Day != 0) {
+		return false
+	}
+
+	return date.Day == 0 || time.Date(date.Year, date.Month, date.Day, 0, 0, 0, 0, time.UTC).Day() == date.Day
+}
+
+// ParseDate parses the dates found in DATE fields: ISO 8601 dates with or without a time, "2007/02/13",
+// "20070213", and day-first or month-first dates such as "13.02.2007" where the order is unambiguous or
+// defaults to day first. A month or day that does not exist is dropped, so "2007-00-00" is read as 2007.
+func ParseDate(value string) (date PartialDate, err error) {
+	fields := strings.FieldsFunc(value, func(c rune) bool {
+		return c < '0' || c > '9'
+	})
+
+	if len(fields) > 0 && (len(fields[0]) == 6 || len(fields[0]) == 8) {
+		compact := fields[0]
+		fields = []string{compact[:4], compact[4:6]}
+
+		if len(compact) == 8 {
+			fields = append(fields, compact[6:])
+		}
+	}
+
+	numbers := make([]int, len(fields))
+
+	for index, field := range fields {
+		numbers[index], _ = strconv.Atoi(field)
+	}
+
+	switch {
+		case len(fields) > 0 && len(fields[0]) == 4:
+			date.Year = numbers[0]
+
+			if len(numbers) > 1 {
+				date.Month = time.Month(numbers[1])
+			}
+
+			if len(numbers) > 2 {
+				date.Day = numbers[2]
+			}
+
+		case len(fields) > 2 && len(fields[0]) <= 2 && len(fields[1]) <= 2 && len(fields[2]) == 4:
+			date = PartialDate{numbers[2], time.Month(numbers[1]), numbers[0]}
+
+			if !date.valid() {
+				date = PartialDate{numbers[2], time.Month(numbers[0]), numbers[1]}
+			}
+	}
+
+	if date.Year == 0 {
+		err = fmt.Errorf("unrecognised date %q", value)
+		date = PartialDate{}
+
+		return
+	}
+
+	if !date.valid() {
+		date.Day = 0
+	}
+
+	if !date.valid() {
+		date.Month = 0
+	}
+
+	return
+}
+
+// firstDate returns the first of the values of keys that parses as a date.
+func (block *FLACMetadataBlockVorbisComment) firstDate(keys ...string) (date PartialDate) {
+	for _, key := range keys {
+		for _, value := range block.GetTag(key) {
+			if parsed, err := ParseDate(value); err == nil {
+				return parsed
+			}
+		}
+	}
+
+	return
+}
+
+// Date returns the release date from DATE, or from YEAR if there is no DATE field, reading the first value
+// that ParseDate accepts. It returns the zero date if there is none.
+func (block *FLACMetadataBlockVorbisComment) Date() PartialDate {
+	return block.firstDate(DateTag, YearTag)
+}
+
+// SetDate writes date to DATE in ISO 8601 and removes any YEAR field. The zero date removes both.
+func (block *FLACMetadataBlockVorbisComment) SetDate(date PartialDate) {
+	block.RemoveTag(YearTag)
+	block.setTag(DateTag, date.String())
+}
+
+// OriginalDate returns the original release date from ORIGINALDATE, or from ORIGINALYEAR if there is none.
+func (block *FLACMetadataBlockVorbisComment) OriginalDate() PartialDate {
+	return block.firstDate(OriginalDateTag, OriginalYearTag)
+}
+
+// SetOriginalDate writes date to ORIGINALDATE in ISO 8601 and removes any ORIGINALYEAR field. The zero date
+// removes both.
+func (block *FLACMetadataBlockVorbisComment) SetOriginalDate(date PartialDate) {
+	block.RemoveTag(OriginalYearTag)
+	block.setTag(OriginalDateTag, date.String())
+}
@@ -0,0 +1,63 @@
+package flac
+
+import (
+	"time"
+)
+
+func (suite *FLACTestSuite) TestParseDate() {
+	dates := map[string]PartialDate{
+		"2007": {2007, 0, 0},
+		"2007-02": {2007, time.February, 0},
+		"2007-02-13": {2007, time.February, 13},
+		" 2007/2/13 ": {2007, time.February, 13},
+		"2007-02-13T10:00:00Z": {2007, time.February, 13},
+		"20070213": {2007, time.February, 13},
+		"200702": {2007, time.February, 0},
+		"13.02.2007": {2007, time.February, 13},
+		"02/13/2007": {2007, time.February, 13},
+		"2007-00-00": {2007, 0, 0},
+		"2007-02-30": {2007, time.February, 0},
+		"2007 (remastered 2011)": {2007, 0, 0},
+	}
+
+	for value, expected := range dates {
+		date, err := ParseDate(value)
+
+		suite.assert.NoError(err, value)
+		suite.assert.Equal(expected, date, value)
+	}
+
+	for _, value := range []string{"", "unknown", "07", "0000"} {
+		_, err := ParseDate(value)
+
+		suite.assert.Error(err, value)
+	}
+
+	suite.assert.Equal("2007-02", PartialDate{2007, time.February, 0}.String())
+	suite.assert.Equal("0987-02-03", PartialDate{987, time.February, 3}.String())
+	suite.assert.Equal("", PartialDate{}.String())
+}
+
+func (suite *FLACTestSuite) TestDateTags() {
+	comments := NewVorbisComment("go-flac")
+
+	suite.assert.True(comments.Date().IsZero())
+
+	comments.AddTag(YearTag, "1999")
+	comments.AddTag(DateTag, "sometime")
+
+	suite.assert.Equal(PartialDate{Year: 1999}, comments.Date())
+
+	comments.SetDate(PartialDate{2007, time.February, 13})
+
+	suite.assert.Equal([]Comment{{DateTag, "2007-02-13"}}, comments.Comments)
+
+	comments.AddTag(OriginalYearTag, "1971")
+
+	suite.assert.Equal(PartialDate{Year: 1971}, comments.OriginalDate())
+
+	comments.SetOriginalDate(PartialDate{1971, time.November, 0})
+	comments.SetDate(PartialDate{})
+
+	suite.assert.Equal([]Comment{{OriginalDateTag, "1971-11"}}, comments.Comments)
+}
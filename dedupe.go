@@ -0,0 +1,182 @@
+package flac
+
+import (
+	"sort"
+	"crypto/md5"
+	"encoding/hex"
+)
+
+// digest returns the MD5 of the picture data, streaming data left unread by WithLazyPictures to compute it.
+func (block *FLACMetadataBlockPicture) digest() (sum []byte, err error) {
+	if block.PictureMD5 != nil {
+		return block.PictureMD5, nil
+	}
+
+	hash := md5.New()
+	_, err = block.Copy(hash)
+
+	if err != nil {
+		return
+	}
+
+	sum = hash.Sum(nil)
+
+	return
+}
+
+// PictureDuplicate is a picture block whose data repeats that of an earlier one. Block numbers are 0 for
+// STREAMINFO and count up through MetadataBlocks.
+type PictureDuplicate struct {
+	Block int
+	Original int
+	Size int64
+}
+
+// DuplicatePictures finds picture blocks holding the same image data as an earlier picture block. Pictures that
+// link to a URL are not compared.
+func (flac *FLAC) DuplicatePictures() (duplicates []PictureDuplicate, err error) {
+	return flac.duplicatePictures(false)
+}
+
+// duplicatePictures finds picture blocks repeating the image data of an earlier one and, with sameType, its type.
+func (flac *FLAC) duplicatePictures(sameType bool) (duplicates []PictureDuplicate, err error) {
+	type pictureKey struct {
+		sum string
+		pictureType PictureType
+	}
+
+	first := make(map[pictureKey]int)
+
+	for index, iBlock := range flac.MetadataBlocks {
+		block, ok := iBlock.(*FLACMetadataBlockPicture)
+
+		if !ok || block.IsURL() {
+			continue
+		}
+
+		var sum []byte
+
+		sum, err = block.digest()
+
+		if err != nil {
+			return
+		}
+
+		key := pictureKey{
+			sum: string(sum),
+		}
+
+		if sameType {
+			key.pictureType = block.Type
+		}
+
+		if original, ok := first[key]; ok {
+			duplicates = append(duplicates, PictureDuplicate{index + 1, original, int64(block.PictureLength)})
+		} else {
+			first[key] = index + 1
+		}
+	}
+
+	return
+}
+
+// RemoveDuplicatePictures removes picture blocks that repeat both the image data and the type of an earlier
+// picture block and returns the bytes of picture data saved. Repeated images with a different type are kept,
+// as the type is what tells players where to show them.
+func (flac *FLAC) RemoveDuplicatePictures() (saved int64, err error) {
+	duplicates, err := flac.duplicatePictures(true)
+
+	if err != nil {
+		return
+	}
+
+	for index := len(duplicates) - 1; index >= 0; index-- {
+		err = flac.RemoveBlock(duplicates[index].Block - 1)
+
+		if err != nil {
+			return
+		}
+
+		saved += duplicates[index].Size
+	}
+
+	return
+}
+
+// PictureLocation identifies a picture block in a file by its block number.
+type PictureLocation struct {
+	Path string
+	Block int
+	Size int64
+}
+
+// PictureStats groups the pictures embedded across files by the hex MD5 of their image data.
+type PictureStats map[string][]PictureLocation
+
+// CollectPictureStats parses each path and groups its pictures by image data, reporting per-file outcomes in
+// result. Picture data is streamed to hash it rather than held in memory, and pictures that link to a URL are left out.
+func CollectPictureStats(paths []string) (stats PictureStats, result *BatchResult) {
+	stats = make(PictureStats)
+	result = &BatchResult{}
+
+	for _, path := range paths {
+		flac, err := Parse(path, WithLazyPictures())
+
+		if err == nil {
+			err = stats.add(flac, path)
+			flac.Close()
+		}
+
+		result.add(path, err)
+	}
+
+	return
+}
+
+func (stats PictureStats) add(flac *FLAC, path string) (err error) {
+	for index, iBlock := range flac.MetadataBlocks {
+		block, ok := iBlock.(*FLACMetadataBlockPicture)
+
+		if !ok || block.IsURL() {
+			continue
+		}
+
+		var sum []byte
+
+		sum, err = block.digest()
+
+		if err != nil {
+			return
+		}
+
+		key := hex.EncodeToString(sum)
+		stats[key] = append(stats[key], PictureLocation{path, index + 1, int64(block.PictureLength)})
+	}
+
+	return
+}
+
+// Duplicates returns the MD5 sums of images embedded more than once, sorted.
+func (stats PictureStats) Duplicates() (sums []string) {
+	for sum, locations := range stats {
+		if len(locations) > 1 {
+			sums = append(sums, sum)
+		}
+	}
+
+	sort.Strings(sums)
+
+	return
+}
+
+// DuplicateBytes returns the bytes taken by every copy of an image after the first, which could be saved
+// by storing each image once, such as in a shared cover file.
+func (stats PictureStats) DuplicateBytes() (size int64) {
+	for _, locations := range stats {
+		for _, location := range locations[1:] {
+			size += location.Size
+		}
+	}
+
+	return
+}
@@ -0,0 +1,42 @@
+package flac
+
+func (suite *FLACTestSuite) TestRemoveDuplicatePictures() {
+	flac, err := Parse("sample.flac", WithLazyPictures())
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	cover := flac.FrontCover()
+	size := int64(cover.PictureLength)
+	icon := cover.clone(flac).(*FLACMetadataBlockPicture)
+	icon.Type = OtherFileIcon
+
+	flac.AppendBlock(cover.clone(flac))
+	flac.AppendBlock(icon)
+	flac.AppendBlock(cover.clone(flac))
+	flac.AppendBlock(icon.clone(flac))
+
+	duplicates, err := flac.DuplicatePictures()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal([]PictureDuplicate{{7, 4, size}, {8, 4, size}, {9, 4, size}, {10, 4, size}}, duplicates)
+
+	saved, err := flac.RemoveDuplicatePictures()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(3 * size, saved)
+	suite.assert.Equal(2, len(flac.Pictures()))
+	suite.assert.Equal(OtherFileIcon, flac.Pictures()[1].Type)
+	suite.assert.False(cover.Loaded())
+}
+
+func (suite *FLACTestSuite) TestCollectPictureStats() {
+	path, _ := suite.copySample()
+	stats, result := CollectPictureStats([]string{"sample.flac", path, "missing.flac"})
+
+	suite.assert.Equal([]string{"missing.flac"}, result.Failed())
+	suite.assert.Equal([]string{"c6f3cec420be726d74ca3ccfb7461f65"}, stats.Duplicates())
+	suite.assert.Equal([]PictureLocation{{"sample.flac", 4, 1661438 - 42}, {path, 4, 1661438 - 42}}, stats["c6f3cec420be726d74ca3ccfb7461f65"])
+	suite.assert.Equal(1661438 - 42, stats.DuplicateBytes())
+}
@@ -0,0 +1,175 @@
+package flac
+
+import (
+	"fmt"
+	"sort"
+	"slices"
+	"reflect"
+	"strings"
+	"encoding/hex"
+)
+
+// ChangeKind identifies how a Change affects the metadata.
+type ChangeKind uint
+
+// Enum indicating the kinds of change reported by Diff.
+const (
+	Added ChangeKind = iota
+	Removed
+	Modified
+)
+
+// Change is a single difference reported by Diff. Index counts blocks of the same type in file order.
+// Field names the changed block field, or the upper-cased name for vorbis comments, and is empty when
+// a whole block was added or removed.
+type Change struct {
+	Kind ChangeKind
+	Type BlockType
+	Index int
+	Field string
+	Old string
+	New string
+}
+
+// diffSkipFields are block fields left out of comparisons, being positional, derived or compared separately.
+var diffSkipFields = map[string]bool{
+	"FLACMetadataBlock": true,
+	"PictureOffset": true,
+	"Picture": true,
+	"Value": true,
+	"Comments": true,
+	"Repaired": true,
+}
+
+// Diff reports the blocks, fields, tags and pictures that differ between a and b. Blocks are paired by type
+// and position among blocks of that type, so a block moved elsewhere in the file is not a change.
+func Diff(a, b *FLAC) (changes []Change) {
+	types := make(map[BlockType]bool)
+
+	for block := range a.Blocks() {
+		types[block.header().Type] = true
+	}
+
+	for block := range b.Blocks() {
+		types[block.header().Type] = true
+	}
+
+	sortedTypes := make([]BlockType, 0, len(types))
+
+	for blockType := range types {
+		sortedTypes = append(sortedTypes, blockType)
+	}
+
+	sort.Slice(sortedTypes, func(i, j int) bool {
+		return sortedTypes[i] < sortedTypes[j]
+	})
+
+	for _, blockType := range sortedTypes {
+		oldBlocks := slices.Collect(a.BlocksOfType(blockType))
+		newBlocks := slices.Collect(b.BlocksOfType(blockType))
+
+		for index := 0; index < len(oldBlocks) || index < len(newBlocks); index++ {
+			switch {
+				case index >= len(newBlocks):
+					changes = append(changes, Change{Kind: Removed, Type: blockType, Index: index})
+
+				case index >= len(oldBlocks):
+					changes = append(changes, Change{Kind: Added, Type: blockType, Index: index})
+
+				default:
+					changes = append(changes, diffBlocks(blockType, index, oldBlocks[index], newBlocks[index])...)
+			}
+		}
+	}
+
+	return
+}
+
+func formatDiffValue(value reflect.Value) string {
+	if data, ok := value.Interface().([]byte); ok {
+		return hex.EncodeToString(data)
+	}
+
+	return fmt.Sprint(value.Interface())
+}
+
+// diffBlocks compares the fields of two blocks of the same type.
+func diffBlocks(blockType BlockType, index int, oldBlock, newBlock IFLACMetadataBlock) (changes []Change) {
+	oldValue := reflect.ValueOf(oldBlock).Elem()
+	newValue := reflect.ValueOf(newBlock).Elem()
+
+	if oldValue.Type() != newValue.Type() {
+		return []Change{{Kind: Modified, Type: blockType, Index: index, Old: oldValue.Type().Name(), New: newValue.Type().Name()}}
+	}
+
+	for field := 0; field < oldValue.NumField(); field++ {
+		name := oldValue.Type().Field(field).Name
+
+		if diffSkipFields[name] || !oldValue.Type().Field(field).IsExported() || reflect.DeepEqual(oldValue.Field(field).Interface(), newValue.Field(field).Interface()) {
+			continue
+		}
+
+		changes = append(changes, Change{
+			Kind: Modified,
+			Type: blockType,
+			Index: index,
+			Field: name,
+			Old: formatDiffValue(oldValue.Field(field)),
+			New: formatDiffValue(newValue.Field(field)),
+		})
+	}
+
+	oldComments, ok := oldBlock.(*FLACMetadataBlockVorbisComment)
+
+	if ok {
+		changes = append(changes, diffComments(index, oldComments, newBlock.(*FLACMetadataBlockVorbisComment))...)
+	}
+
+	return
+}
+
+func diffComments(index int, oldBlock, newBlock *FLACMetadataBlockVorbisComment) (changes []Change) {
+	oldComments := oldBlock.Map()
+	newComments := newBlock.Map()
+	keys := make([]string, 0, len(oldComments) + len(newComments))
+
+	for key := range oldComments {
+		keys = append(keys, key)
+	}
+
+	for key := range newComments {
+		if _, ok := oldComments[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		oldValues, inOld := oldComments[key]
+		newValues, inNew := newComments[key]
+		change := Change{
+			Kind: Modified,
+			Type: VorbisComment,
+			Index: index,
+			Field: key,
+			Old: strings.Join(oldValues, "; "),
+			New: strings.Join(newValues, "; "),
+		}
+
+		switch {
+			case !inNew:
+				change.Kind = Removed
+
+			case !inOld:
+				change.Kind = Added
+
+			case slices.Equal(oldValues, newValues):
+				continue
+		}
+
+		changes = append(changes, change)
+	}
+
+	return
+}
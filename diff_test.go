@@ -0,0 +1,32 @@
+package flac
+
+func (suite *FLACTestSuite) TestDiff() {
+	suite.assert.Equal(0, len(Diff(suite.flac, suite.flac.Clone())))
+
+	edited := suite.flac.Clone()
+	comments := edited.VorbisComment()
+	comments.AddTag("EXAMPLE", "chips")
+	comments.AddTag("TITLE", "Song")
+	edited.FrontCover().Description = "Cover"
+	edited.FrontCover().PictureMD5 = []byte{0xab}
+	edited.MetadataBlocks = append(edited.MetadataBlocks, &FLACMetadataBlockPadding{
+		FLACMetadataBlock: FLACMetadataBlock{
+			Type: Padding,
+		},
+	})
+
+	changes := Diff(suite.flac, edited)
+
+	suite.assert.Equal([]Change{
+		{Kind: Added, Type: Padding, Index: 1},
+		{Kind: Modified, Type: VorbisComment, Field: "EXAMPLE", Old: "fish", New: "fish; chips"},
+		{Kind: Added, Type: VorbisComment, Field: "TITLE", New: "Song"},
+		{Kind: Modified, Type: Picture, Field: "Description", New: "Cover"},
+		{Kind: Modified, Type: Picture, Field: "PictureMD5", Old: "c6f3cec420be726d74ca3ccfb7461f65", New: "ab"},
+	}, changes)
+
+	changes = Diff(edited, suite.flac)
+
+	suite.assert.Equal(Removed, changes[0].Kind)
+	suite.assert.Equal(Removed, changes[2].Kind)
+}
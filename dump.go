@@ -0,0 +1,203 @@
+package flac
+
+import (
+	"io"
+	"fmt"
+	"bytes"
+)
+
+var blockTypeNames = map[BlockType]string{
+	StreamInfo: "STREAMINFO",
+	Padding: "PADDING",
+	Application: "APPLICATION",
+	SeekTable: "SEEKTABLE",
+	VorbisComment: "VORBIS_COMMENT",
+	CueSheet: "CUESHEET",
+	Picture: "PICTURE",
+}
+
+var pictureTypeNames = []string{
+	"Other",
+	"32x32 pixels 'file icon' (PNG only)",
+	"Other file icon",
+	"Cover (front)",
+	"Cover (back)",
+	"Leaflet page",
+	"Media (e.g. label side of CD)",
+	"Lead artist/lead performer/soloist",
+	"Artist/performer",
+	"Conductor",
+	"Band/Orchestra",
+	"Composer",
+	"Lyricist/text writer",
+	"Recording Location",
+	"During recording",
+	"During performance",
+	"Movie/video screen capture",
+	"A bright coloured fish",
+	"Illustration",
+	"Band/artist logotype",
+	"Publisher/Studio logotype",
+}
+
+// hexDump writes data sixteen bytes to a line in the layout used by metaflac.
+func hexDump(buffer *bytes.Buffer, data []byte, indent string) {
+	for offset := 0; offset < len(data); offset += 16 {
+		line := make([]byte, 16)
+		text := []byte("                ")
+
+		copy(line, data[offset:])
+
+		for index := 0; index < 16 && offset + index < len(data); index++ {
+			text[index] = '.'
+
+			if line[index] >= 0x20 && line[index] < 0x7f {
+				text[index] = line[index]
+			}
+		}
+
+		fmt.Fprintf(buffer, "%s%08X: % X %s\n", indent, offset, line, text)
+	}
+}
+
+func (flac *FLAC) dump(buffer *bytes.Buffer) {
+	blockNumber := 0
+
+	for iBlock := range flac.Blocks() {
+		header := iBlock.header()
+		typeName, ok := blockTypeNames[header.Type]
+
+		if !ok {
+			typeName = "UNKNOWN"
+		}
+
+		fmt.Fprintf(buffer, "METADATA block #%d\n", blockNumber)
+		fmt.Fprintf(buffer, "  type: %d (%s)\n", header.Type, typeName)
+		fmt.Fprintf(buffer, "  is last: %t\n", header.Last)
+		fmt.Fprintf(buffer, "  length: %d\n", header.DataLength)
+
+		blockNumber++
+
+		switch block := iBlock.(type) {
+			case *FLACMetadataBlockStreamInfo:
+				fmt.Fprintf(buffer, "  minimum blocksize: %d samples\n", block.MinBlockSize)
+				fmt.Fprintf(buffer, "  maximum blocksize: %d samples\n", block.MaxBlockSize)
+				fmt.Fprintf(buffer, "  minimum framesize: %d bytes\n", block.MinFrameSize)
+				fmt.Fprintf(buffer, "  maximum framesize: %d bytes\n", block.MaxFrameSize)
+				fmt.Fprintf(buffer, "  sample_rate: %d Hz\n", block.SampleRate)
+				fmt.Fprintf(buffer, "  channels: %d\n", block.Channels)
+				fmt.Fprintf(buffer, "  bits-per-sample: %d\n", block.BitsPerSample)
+				fmt.Fprintf(buffer, "  total samples: %d\n", block.NumSamples)
+				fmt.Fprintf(buffer, "  MD5 signature: %x\n", block.UnencodedMD5)
+
+			case *FLACMetadataBlockApplication:
+				fmt.Fprintf(buffer, "  application ID: %x\n", block.AppID)
+				fmt.Fprintf(buffer, "  data contents:\n")
+				hexDump(buffer, block.AppData, "    ")
+
+			case *FLACMetadataBlockSeekTable:
+				fmt.Fprintf(buffer, "  seek points: %d\n", len(block.SeekPoints))
+
+				for index, point := range block.SeekPoints {
+					if point.Sample == 0xffffffffffffffff {
+						fmt.Fprintf(buffer, "    point %d: PLACEHOLDER\n", index)
+
+						continue
+					}
+
+					fmt.Fprintf(buffer, "    point %d: sample_number=%d, stream_offset=%d, frame_samples=%d\n", index, point.Sample, point.ByteOffset, point.NumSamples)
+				}
+
+			case *FLACMetadataBlockVorbisComment:
+				fmt.Fprintf(buffer, "  vendor string: %s\n", block.VendorString)
+				fmt.Fprintf(buffer, "  comments: %d\n", len(block.Comments))
+
+				for index, comment := range block.Comments {
+					fmt.Fprintf(buffer, "    comment[%d]: %s=%s\n", index, comment.Key, comment.Value)
+				}
+
+			case *FLACMetadataBlockCueSheet:
+				fmt.Fprintf(buffer, "  media catalog number: %s\n", bytes.TrimRight([]byte(block.MediaCatalogNumber), "\x00"))
+				fmt.Fprintf(buffer, "  lead-in: %d\n", block.NumLeadInSamples)
+				fmt.Fprintf(buffer, "  is CD: %t\n", block.IsCD)
+				fmt.Fprintf(buffer, "  number of tracks: %d\n", len(block.CueSheetTracks))
+
+				for index, track := range block.CueSheetTracks {
+					fmt.Fprintf(buffer, "    track[%d]\n", index)
+					fmt.Fprintf(buffer, "      offset: %d\n", track.Offset)
+
+					if index == len(block.CueSheetTracks) - 1 {
+						fmt.Fprintf(buffer, "      number: %d (LEAD-OUT)\n", track.Track)
+
+						continue
+					}
+
+					trackType := "AUDIO"
+
+					if !track.IsAudio {
+						trackType = "DATA"
+					}
+
+					fmt.Fprintf(buffer, "      number: %d\n", track.Track)
+					fmt.Fprintf(buffer, "      ISRC: %s\n", bytes.TrimRight([]byte(track.ISRC), "\x00"))
+					fmt.Fprintf(buffer, "      type: %s\n", trackType)
+					fmt.Fprintf(buffer, "      pre-emphasis: %t\n", track.PreEmphasis)
+					fmt.Fprintf(buffer, "      number of index points: %d\n", len(track.CueSheetTrackIndices))
+
+					for indexIndex, trackIndex := range track.CueSheetTrackIndices {
+						fmt.Fprintf(buffer, "        index[%d]\n", indexIndex)
+						fmt.Fprintf(buffer, "          offset: %d\n", trackIndex.Offset)
+						fmt.Fprintf(buffer, "          number: %d\n", trackIndex.IndexNumber)
+					}
+				}
+
+			case *FLACMetadataBlockPicture:
+				pictureType := "UNDEFINED"
+
+				if int(block.Type) < len(pictureTypeNames) {
+					pictureType = pictureTypeNames[block.Type]
+				}
+
+				unindexed := ""
+
+				if block.NumColours == 0 {
+					unindexed = " (unindexed)"
+				}
+
+				fmt.Fprintf(buffer, "  type: %d (%s)\n", block.Type, pictureType)
+				fmt.Fprintf(buffer, "  MIME type: %s\n", block.MIMEType)
+				fmt.Fprintf(buffer, "  description: %s\n", block.Description)
+				fmt.Fprintf(buffer, "  width: %d\n", block.Width)
+				fmt.Fprintf(buffer, "  height: %d\n", block.Height)
+				fmt.Fprintf(buffer, "  depth: %d\n", block.ColourDepth)
+				fmt.Fprintf(buffer, "  colors: %d%s\n", block.NumColours, unindexed)
+				fmt.Fprintf(buffer, "  data length: %d\n", block.PictureLength)
+				fmt.Fprintf(buffer, "  data:\n")
+				hexDump(buffer, block.Picture, "    ")
+
+			case *FLACMetadataBlockReserved:
+				fmt.Fprintf(buffer, "  data contents:\n")
+				hexDump(buffer, block.Data, "    ")
+		}
+	}
+}
+
+// Dump writes every metadata block in the layout of `metaflac --list`, with application data shown
+// as with --application-data-format=hexdump. Picture data is dumped only if it has been loaded.
+func (flac *FLAC) Dump(w io.Writer) (err error) {
+	var buffer bytes.Buffer
+
+	flac.dump(&buffer)
+	_, err = buffer.WriteTo(w)
+
+	return
+}
+
+// String returns the output of Dump.
+func (flac *FLAC) String() string {
+	var buffer bytes.Buffer
+
+	flac.dump(&buffer)
+
+	return buffer.String()
+}
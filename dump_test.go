@@ -0,0 +1,35 @@
+package flac
+
+import (
+	"bytes"
+	"strings"
+)
+
+func (suite *FLACTestSuite) TestDump() {
+	flac, err := Parse("sample.flac", WithLazyPictures())
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	var buffer bytes.Buffer
+
+	suite.assert.NoError(flac.Dump(&buffer))
+	suite.assert.Equal(flac.String(), buffer.String())
+
+	lines := strings.Split(buffer.String(), "\n")
+
+	suite.assert.Equal([]string{
+		"METADATA block #0",
+		"  type: 0 (STREAMINFO)",
+		"  is last: false",
+		"  length: 34",
+	}, lines[:4])
+	suite.assert.Contains(buffer.String(), "METADATA block #1\n  type: 3 (SEEKTABLE)\n  is last: false\n  length: 18\n  seek points: 1\n    point 0: sample_number=0, stream_offset=0, frame_samples=4096\n")
+	suite.assert.Contains(buffer.String(), "  application ID: 41544348\n  data contents:\n    00000000: 43 40 4B 33 00 00 00 00 00 00 00 00 00 00 00 00 C@K3            \n")
+	suite.assert.Contains(buffer.String(), "  vendor string: reference libFLAC 1.1.4 20070213\n  comments: 1\n    comment[0]: example=fish\n")
+	suite.assert.Contains(buffer.String(), "  type: 3 (Cover (front))\n  MIME type: image/jpeg\n")
+	suite.assert.Contains(buffer.String(), "  colors: 0 (unindexed)\n")
+	suite.assert.Contains(buffer.String(), "      number: 255 (LEAD-OUT)\n")
+	suite.assert.Contains(buffer.String(), "METADATA block #6\n  type: 1 (PADDING)\n  is last: true\n  length: 7596\n")
+}
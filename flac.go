@@ -1,7 +1,11 @@
 package flac
 
 import (
+	"io"
 	"os"
+	"io/fs"
+	"fmt"
+	"time"
 	"bytes"
 	"strings"
 	"errors"
@@ -61,121 +65,260 @@ const (
 
 // SeekPoint is a structure for storing the points at which a stream can be seeked.
 type SeekPoint struct {
-	Sample uint64
-	ByteOffset uint64
-	NumSamples uint16
+	Sample uint64 `json:"sample"`
+	ByteOffset uint64 `json:"byteOffset"`
+	NumSamples uint16 `json:"numSamples"`
 }
 
 // CueSheetTrackIndex is a structure for each cue index.
 type CueSheetTrackIndex struct {
-	Offset uint64
-	IndexNumber uint8
+	Offset uint64 `json:"offset"`
+	IndexNumber uint8 `json:"number"`
 }
 
 // CueSheetTrack is a structure representing a cuesheet track.
 type CueSheetTrack struct {
-	Offset uint64
-	Track uint8
-	ISRC string
-	IsAudio bool
-	PreEmphasis bool
-	CueSheetTrackIndices []CueSheetTrackIndex
+	Offset uint64 `json:"offset"`
+	Track uint8 `json:"number"`
+	ISRC string `json:"isrc"`
+	IsAudio bool `json:"isAudio"`
+	PreEmphasis bool `json:"preEmphasis"`
+	CueSheetTrackIndices []CueSheetTrackIndex `json:"indices"`
 }
 
 // IFLACMetadataBlock is an interface for common behaviour of a metadata block.
 type IFLACMetadataBlock interface {
-	parse(*os.File) error
+	parse(io.Reader) error
 	isLast() bool
+	header() *FLACMetadataBlock
+	clone(*FLAC) IFLACMetadataBlock
+	marshal() ([]byte, error)
 }
 
 // FLACMetadataBlock sets out basic attributes for all metadata blocks.
+// HeaderOffset is the position of the block header in the stream and Offset the position of the block data.
+// RawData holds the original block data when parsing with WithRawData.
 type FLACMetadataBlock struct {
-	FLAC *FLAC
-	Last bool
-	Type BlockType
-	DataLength uint32
+	FLAC *FLAC `json:"-"`
+	Last bool `json:"last"`
+	Type BlockType `json:"type"`
+	DataLength uint32 `json:"length"`
+	HeaderOffset int64 `json:"headerOffset"`
+	Offset int64 `json:"offset"`
+	RawData []byte `json:"-"`
+}
+
+func (block *FLACMetadataBlock) header() *FLACMetadataBlock {
+	return block
 }
 
 // FLACMetadataBlockStreamInfo sets out the structure for stream information.
 type FLACMetadataBlockStreamInfo struct {
 	FLACMetadataBlock
-	MinBlockSize uint16
-	MaxBlockSize uint16
-	MinFrameSize uint32
-	MaxFrameSize uint32
-	SampleRate uint32
-	Channels uint8
-	BitsPerSample uint8
-	NumSamples uint64
-	UnencodedMD5 []byte
+	MinBlockSize uint16 `json:"minBlockSize"`
+	MaxBlockSize uint16 `json:"maxBlockSize"`
+	MinFrameSize uint32 `json:"minFrameSize"`
+	MaxFrameSize uint32 `json:"maxFrameSize"`
+	SampleRate uint32 `json:"sampleRate"`
+	Channels uint8 `json:"channels"`
+	BitsPerSample uint8 `json:"bitsPerSample"`
+	NumSamples uint64 `json:"numSamples"`
+	UnencodedMD5 []byte `json:"md5"`
 }
 
 // FLACMetadataBlockPadding represents padding metadata blocks.
 type FLACMetadataBlockPadding struct {
 	FLACMetadataBlock
-	NumBytes uint32
+	NumBytes uint32 `json:"numBytes"`
 }
 
 // FLACMetadataBlockApplication represents application/binary metadata blocks.
+// Value holds AppData decoded by the codec registered for AppID, if any; when set it is encoded in place of
+// AppData when the block is written.
 type FLACMetadataBlockApplication struct {
 	FLACMetadataBlock
-	AppID string
-	AppData []byte
+	AppID string `json:"id"`
+	AppData []byte `json:"data"`
+	Value interface{} `json:"value,omitempty"`
 }
 
 // FLACMetadataBlockSeekTable represents the seek metadata block for a stream.
 type FLACMetadataBlockSeekTable struct {
 	FLACMetadataBlock
-	SeekPoints []SeekPoint
+	SeekPoints []SeekPoint `json:"seekPoints"`
 }
 
 // FLACMetadataBlockVorbisComment represents s tagging/vorbis comment metadata block.
 type FLACMetadataBlockVorbisComment struct {
 	FLACMetadataBlock
-	VendorString string
-	Comments map[string][]string
+	VendorString string `json:"vendor"`
+	Comments []Comment `json:"comments"`
+	Repaired []RepairedComment `json:"repaired,omitempty"`
+	vendorSet bool
 }
 
 // FLACMetadataBlockCueSheet sets out the structure of a cuesheet metadata block.
 type FLACMetadataBlockCueSheet struct {
 	FLACMetadataBlock
-	MediaCatalogNumber string
-	NumLeadInSamples uint64
-	IsCD bool
-	CueSheetTracks []CueSheetTrack
+	MediaCatalogNumber string `json:"mediaCatalogNumber"`
+	NumLeadInSamples uint64 `json:"leadInSamples"`
+	IsCD bool `json:"isCD"`
+	CueSheetTracks []CueSheetTrack `json:"tracks"`
 }
 
 // FLACMetadataBlockPicture sets out the structure used for a picture metadata block.
 type FLACMetadataBlockPicture struct {
 	FLACMetadataBlock
-	Type PictureType
-	MIMEType string
-	Description string
-	Width uint32
-	Height uint32
-	ColourDepth uint32
-	NumColours uint32
-	Picture []byte
-	PictureMD5 []byte
-}
-
-// FLACMetadataBlockReserved is an unused/reserved metadata block.
+	Type PictureType `json:"pictureType"`
+	MIMEType string `json:"mimeType"`
+	Description string `json:"description"`
+	Width uint32 `json:"width"`
+	Height uint32 `json:"height"`
+	ColourDepth uint32 `json:"colourDepth"`
+	NumColours uint32 `json:"numColours"`
+	PictureLength uint32 `json:"pictureLength"`
+	PictureOffset int64 `json:"pictureOffset"`
+	Picture []byte `json:"-"`
+	PictureMD5 []byte `json:"pictureMD5,omitempty"`
+}
+
+// FLACMetadataBlockReserved is an unused/reserved metadata block. Its payload is kept as is so it can be written back unchanged.
 type FLACMetadataBlockReserved struct {
 	FLACMetadataBlock
+	Data []byte `json:"data"`
+}
+
+// Sentinel errors returned, possibly wrapped, by the parse functions. ErrNotFLAC means the input is not a FLAC
+// stream at all, while the others mean it is a corrupt one.
+var (
+	ErrNotFLAC = errors.New("FLAC marker not found")
+	ErrInvalidBlockType = errors.New("invalid metadata block type")
+	ErrTruncated = errors.New("FLAC metadata truncated")
+	ErrMalformedVorbisComment = errors.New("malformed vorbis comment")
+	ErrIncompleteMetadata = errors.New("metadata was parsed with blocks left out by WithMaxBlockCount")
+)
+
+// CommentLengthError is returned when a length in a vorbis comment block exceeds the data remaining in the block.
+type CommentLengthError struct {
+	Field string
+	Length uint64
+	Remaining uint64
+}
+
+func (err *CommentLengthError) Error() string {
+	return fmt.Sprintf("vorbis comment %s length %d exceeds %d remaining bytes", err.Field, err.Length, err.Remaining)
+}
+
+// Unwrap allows errors.Is to match ErrMalformedVorbisComment.
+func (err *CommentLengthError) Unwrap() error {
+	return ErrMalformedVorbisComment
+}
+
+// ParseError reports the metadata block that failed to parse. Index counts blocks from the stream info block at 0,
+// and Offset is the position of the block header in the stream. Type is Invalid when the block header
+// itself could not be read.
+type ParseError struct {
+	Index int
+	Type BlockType
+	Offset int64
+	Err error
+}
+
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("metadata block %d (type %d) at offset %d: %v", err.Index, err.Type, err.Offset, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *ParseError) Unwrap() error {
+	return err.Err
+}
+
+// newParseError wraps err with the position of the block, marking an unexpected end of input as ErrTruncated.
+func newParseError(index int, offset int64, block IFLACMetadataBlock, err error) *ParseError {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = fmt.Errorf("%w: %w", ErrTruncated, err)
+	}
+
+	parseErr := &ParseError{
+		Index: index,
+		Type: Invalid,
+		Offset: offset,
+		Err: err,
+	}
+
+	if block != nil {
+		parseErr.Type = block.header().Type
+	}
+
+	return parseErr
+}
+
+// Warning describes a recoverable problem that was passed over while parsing with WithLenient.
+// Offset is the position of the block header in the stream.
+type Warning struct {
+	Type BlockType `json:"type"`
+	Offset int64 `json:"offset"`
+	Message string `json:"message"`
+}
+
+// tolerate records problem as a warning and returns nil when parsing leniently, or returns problem otherwise.
+func (block *FLACMetadataBlock) tolerate(problem error) error {
+	if block.FLAC == nil || !block.FLAC.options.isLenient() {
+		return problem
+	}
+
+	block.FLAC.Warnings = append(block.FLAC.Warnings, Warning{
+		Type: block.Type,
+		Offset: block.HeaderOffset,
+		Message: problem.Error(),
+	})
+
+	return nil
+}
+
+// tolerateTrailing tolerates length unused bytes at the end of the block data, draining them from buffer.
+func (block *FLACMetadataBlock) tolerateTrailing(buffer *bitbuffer.BitBuffer, length uint64) (err error) {
+	if length == 0 {
+		return
+	}
+
+	err = block.tolerate(fmt.Errorf("%d unexpected trailing bytes", length))
+
+	if err != nil {
+		return
+	}
+
+	_, err = buffer.Read(length * 8)
+
+	return
 }
 
 // FLAC is the primary structure for operations on FLAC files.
+// When marshalled to JSON, block and picture types are written by name, MD5 signatures as hex strings
+// and NUL padding is trimmed from cue sheet strings; picture data is omitted.
 type FLAC struct {
 	buffer *bitbuffer.BitBuffer
-	Marker string
-	StreamInfo *FLACMetadataBlockStreamInfo
-	MetadataBlocks []IFLACMetadataBlock
+	Marker string `json:"marker"`
+	StreamInfo *FLACMetadataBlockStreamInfo `json:"streamInfo"`
+	MetadataBlocks []IFLACMetadataBlock `json:"blocks"`
+	AudioOffset int64 `json:"audioOffset"`
+	Gap *FrameGap `json:"gap,omitempty"`
+	Warnings []Warning `json:"warnings,omitempty"`
+	path string
+	fsys fs.FS
+	file fs.File
+	options *parseOptions
+	blocksLeftOut int
+	size int64
+	modTime time.Time
+	quickHash []byte
+	imageTransformer ImageTransformer
 }
 
-func (block *FLACMetadataBlockStreamInfo) parse(handle *os.File) (err error) {
+func (block *FLACMetadataBlockStreamInfo) parse(reader io.Reader) (err error) {
 	blockData := make([]byte, block.FLACMetadataBlock.DataLength)
 
-	_, err = handle.Read(blockData)
+	_, err = io.ReadFull(reader, blockData)
 
 	if err != nil {
 		return
@@ -239,6 +382,12 @@ func (block *FLACMetadataBlockStreamInfo) parse(handle *os.File) (err error) {
 
 	block.UnencodedMD5, err = block.FLACMetadataBlock.FLAC.buffer.Read(128)
 
+	if err != nil {
+		return
+	}
+
+	err = block.tolerateTrailing(block.FLACMetadataBlock.FLAC.buffer, uint64(len(blockData)) - 34)
+
 	return
 }
 
@@ -246,10 +395,10 @@ func (block *FLACMetadataBlockStreamInfo) isLast() bool {
 	return block.FLACMetadataBlock.Last
 }
 
-func (block *FLACMetadataBlockPadding) parse(handle *os.File) (err error) {
+func (block *FLACMetadataBlockPadding) parse(reader io.Reader) (err error) {
 	blockData := make([]byte, block.FLACMetadataBlock.DataLength)
 
-	_, err = handle.Read(blockData)
+	_, err = io.ReadFull(reader, blockData)
 
 	if err != nil {
 		return
@@ -264,10 +413,10 @@ func (block *FLACMetadataBlockPadding) isLast() bool {
 	return block.FLACMetadataBlock.Last
 }
 
-func (block *FLACMetadataBlockApplication) parse(handle *os.File) (err error) {
+func (block *FLACMetadataBlockApplication) parse(reader io.Reader) (err error) {
 	data := make([]byte, block.FLACMetadataBlock.DataLength)
 
-	_, err = handle.Read(data)
+	_, err = io.ReadFull(reader, data)
 
 	if err != nil {
 		return
@@ -284,6 +433,12 @@ func (block *FLACMetadataBlockApplication) parse(handle *os.File) (err error) {
 
 	block.AppData, err = buffer.Read(uint64(block.FLACMetadataBlock.DataLength * 8 - 32))
 
+	if err != nil {
+		return
+	}
+
+	block.decode()
+
 	return
 }
 
@@ -291,15 +446,25 @@ func (block *FLACMetadataBlockApplication) isLast() bool {
 	return block.FLACMetadataBlock.Last
 }
 
-func (block *FLACMetadataBlockSeekTable) parse(handle *os.File) (err error) {
+func (block *FLACMetadataBlockSeekTable) parse(reader io.Reader) (err error) {
 	data := make([]byte, block.FLACMetadataBlock.DataLength)
 
-	_, err = handle.Read(data)
+	_, err = io.ReadFull(reader, data)
 
 	if err != nil {
 		return
 	}
 
+	if len(data) % 18 != 0 {
+		err = block.tolerate(fmt.Errorf("seek table length %d is not a multiple of 18", len(data)))
+
+		if err != nil {
+			return
+		}
+
+		data = data[:len(data) / 18 * 18]
+	}
+
 	buffer := block.FLACMetadataBlock.FLAC.buffer
 
 	buffer.Feed(data)
@@ -337,10 +502,10 @@ func (block *FLACMetadataBlockSeekTable) isLast() bool {
 	return block.FLACMetadataBlock.Last
 }
 
-func (block *FLACMetadataBlockVorbisComment) parse(handle *os.File) (err error) {
+func (block *FLACMetadataBlockVorbisComment) parse(reader io.Reader) (err error) {
 	data := make([]byte, block.FLACMetadataBlock.DataLength)
 
-	_, err = handle.Read(data)
+	_, err = io.ReadFull(reader, data)
 
 	if err != nil {
 		return
@@ -350,36 +515,71 @@ func (block *FLACMetadataBlockVorbisComment) parse(handle *os.File) (err error)
 
 	buffer.Feed(data)
 
+	remaining := uint64(len(data)) - 4
 	length, err := buffer.ReadUint64(32)
 
 	if err != nil {
 		return
 	}
 
+	if length > remaining {
+		err = &CommentLengthError{"vendor string", length, remaining}
+
+		return
+	}
+
+	remaining -= length
 	block.VendorString, err = buffer.ReadString(length * 8)
 
 	if err != nil {
 		return
 	}
 
+	if remaining < 4 {
+		err = &CommentLengthError{"comment list length", 4, remaining}
+
+		return
+	}
+
+	remaining -= 4
 	length, err = buffer.ReadUint64(32)
 
 	if err != nil {
 		return
 	}
 
+	if length * 4 > remaining {
+		err = &CommentLengthError{"comment list", length * 4, remaining}
+
+		return
+	}
+
 	var commentLength uint64
 	var comment string
 
-	block.Comments = make(map[string][]string)
+	block.Comments = make([]Comment, 0, length)
+
+	for commentIndex := uint64(0); commentIndex < length; commentIndex++ {
+		if remaining < 4 {
+			err = &CommentLengthError{fmt.Sprintf("comment %d length", commentIndex), 4, remaining}
 
-	for commentIndex := 0; commentIndex < int(length); commentIndex++ {
+			return
+		}
+
+		remaining -= 4
 		commentLength, err = buffer.ReadUint64(32)
 
 		if err != nil {
 			return
 		}
 
+		if commentLength > remaining {
+			err = &CommentLengthError{fmt.Sprintf("comment %d", commentIndex), commentLength, remaining}
+
+			return
+		}
+
+		remaining -= commentLength
 		comment, err = buffer.ReadString(commentLength * 8)
 
 		if err != nil {
@@ -389,14 +589,29 @@ func (block *FLACMetadataBlockVorbisComment) parse(handle *os.File) (err error)
 		commentFields := strings.SplitN(comment, "=", 2)
 		
 		if len(commentFields) != 2 {
-			err = errors.New("malformed vorbis comment")
+			err = block.tolerate(fmt.Errorf("%w: comment %d has no '=' separator", ErrMalformedVorbisComment, commentIndex))
 
-			return
+			if err != nil {
+				return
+			}
+
+			continue
 		}
 
-		block.Comments[commentFields[0]] = append(block.Comments[commentFields[0]], commentFields[1])
+		block.Comments = append(block.Comments, Comment{
+			Key: commentFields[0],
+			Value: commentFields[1],
+		})
 	}
-	
+
+	if block.FLAC != nil && block.FLAC.options.normalizeTags() {
+		block.NormalizeTags(*block.FLAC.options.normalization)
+	}
+
+	if remaining > 0 {
+		err = block.tolerate(fmt.Errorf("%d unexpected trailing bytes", remaining))
+	}
+
 	return
 }
 
@@ -404,10 +619,10 @@ func (block *FLACMetadataBlockVorbisComment) isLast() bool {
 	return block.FLACMetadataBlock.Last
 }
 
-func (block *FLACMetadataBlockCueSheet) parse(handle *os.File) (err error) {
+func (block *FLACMetadataBlockCueSheet) parse(reader io.Reader) (err error) {
 	data := make([]byte, block.FLACMetadataBlock.DataLength)
 
-	_, err = handle.Read(data)
+	_, err = io.ReadFull(reader, data)
 
 	if err != nil {
 		return
@@ -449,6 +664,8 @@ func (block *FLACMetadataBlockCueSheet) parse(handle *os.File) (err error) {
 		return
 	}
 
+	consumed := uint64(396)
+
 	for trackIndex := uint8(0); trackIndex < numTracks; trackIndex++ {
 		var flag uint8
 		var numIndices uint8
@@ -524,9 +741,12 @@ func (block *FLACMetadataBlockCueSheet) parse(handle *os.File) (err error) {
 			track.CueSheetTrackIndices = append(track.CueSheetTrackIndices, index)
 		}
 
+		consumed += 36 + uint64(numIndices) * 12
 		block.CueSheetTracks = append(block.CueSheetTracks, track)
 	}
 
+	err = block.tolerateTrailing(buffer, uint64(len(data)) - consumed)
+
 	return
 }
 
@@ -534,18 +754,36 @@ func (block *FLACMetadataBlockCueSheet) isLast() bool {
 	return block.FLACMetadataBlock.Last
 }
 
-func (block *FLACMetadataBlockPicture) parse(handle *os.File) (err error) {
-	data := make([]byte, block.FLACMetadataBlock.DataLength)
+func (block *FLACMetadataBlockPicture) parse(reader io.Reader) (err error) {
+	remaining := uint64(block.FLACMetadataBlock.DataLength)
+	buffer := bitbuffer.NewBitBuffer(binary.BigEndian)
 
-	_, err = handle.Read(data)
+	// feed reads the next length bytes of the block into the buffer, refusing lengths beyond the block.
+	feed := func(length uint64) (err error) {
+		if length > remaining {
+			err = errors.New("picture field length exceeds block size")
+
+			return
+		}
+
+		data := make([]byte, length)
+		_, err = io.ReadFull(reader, data)
+
+		if err != nil {
+			return
+		}
+
+		remaining -= length
+		buffer.Feed(data)
 
-	if err != nil {
 		return
 	}
 
-	buffer := block.FLACMetadataBlock.FLAC.buffer
+	err = feed(8)
 
-	buffer.Feed(data)
+	if err != nil {
+		return
+	}
 
 	blockType, err := buffer.ReadUint32(32)
 
@@ -561,6 +799,12 @@ func (block *FLACMetadataBlockPicture) parse(handle *os.File) (err error) {
 		return
 	}
 
+	err = feed(mimeLength + 4)
+
+	if err != nil {
+		return
+	}
+
 	block.MIMEType, err = buffer.ReadString(mimeLength * 8)
 
 	if err != nil {
@@ -573,6 +817,12 @@ func (block *FLACMetadataBlockPicture) parse(handle *os.File) (err error) {
 		return
 	}
 
+	err = feed(descLength + 20)
+
+	if err != nil {
+		return
+	}
+
 	block.Description, err = buffer.ReadString(descLength * 8)
 
 	if err != nil {
@@ -603,38 +853,71 @@ func (block *FLACMetadataBlockPicture) parse(handle *os.File) (err error) {
 		return
 	}
 
-	hasher := md5.New()
-	picLength, err := buffer.ReadUint64(32)
+	block.PictureLength, err = buffer.ReadUint32(32)
 
 	if err != nil {
 		return
 	}
 
-	block.Picture, err = buffer.Read(picLength * 8)
+	counter, seekable := reader.(*countingReader)
 
-	if err != nil {
+	if seekable && block.FLACMetadataBlock.FLAC != nil && block.FLACMetadataBlock.FLAC.options.lazyPictures() {
+		if uint64(block.PictureLength) > remaining {
+			err = errors.New("picture field length exceeds block size")
+
+			return
+		}
+
+		block.PictureOffset = counter.count
+		remaining -= uint64(block.PictureLength)
+		err = counter.skip(int64(block.PictureLength))
+	} else {
+		err = feed(uint64(block.PictureLength))
+
+		if err != nil {
+			return
+		}
+
+		block.Picture, err = buffer.Read(uint64(block.PictureLength) * 8)
+
+		if err != nil {
+			return
+		}
+
+		if !block.IsURL() {
+			block.PictureMD5 = pictureMD5(block.Picture)
+		}
+	}
+
+	if err != nil || remaining == 0 {
 		return
 	}
 
-	_, err = hasher.Write(block.Picture)
+	err = block.tolerate(fmt.Errorf("%d unexpected trailing bytes", remaining))
 
 	if err != nil {
 		return
 	}
 
-	block.PictureMD5 = hasher.Sum(nil)
+	_, err = io.CopyN(io.Discard, reader, int64(remaining))
 
 	return
 }
 
+func pictureMD5(picture []byte) []byte {
+	hash := md5.Sum(picture)
+
+	return hash[:]
+}
+
 func (block *FLACMetadataBlockPicture) isLast() bool {
 	return block.FLACMetadataBlock.Last
 }
 
-func (block *FLACMetadataBlockReserved) parse(handle *os.File) (err error) {
-	data := make([]byte, block.FLACMetadataBlock.DataLength)
+func (block *FLACMetadataBlockReserved) parse(reader io.Reader) (err error) {
+	block.Data = make([]byte, block.FLACMetadataBlock.DataLength)
 
-	_, err = handle.Read(data)
+	_, err = io.ReadFull(reader, block.Data)
 
 	return
 }
@@ -643,10 +926,11 @@ func (block *FLACMetadataBlockReserved) isLast() bool {
 	return block.FLACMetadataBlock.Last
 }
 
-func (flac *FLAC) parseMetadataBlock(handle *os.File) (block IFLACMetadataBlock, err error) {
+func (flac *FLAC) parseMetadataBlock(reader *countingReader) (block IFLACMetadataBlock, err error) {
+	headerOffset := reader.count
 	blockHeaderData := make([]byte, 4)
 
-	_, err = handle.Read(blockHeaderData)
+	_, err = io.ReadFull(reader, blockHeaderData)
 
 	if err != nil {
 		return
@@ -669,6 +953,8 @@ func (flac *FLAC) parseMetadataBlock(handle *os.File) (block IFLACMetadataBlock,
 		Last: lastBlock,
 		Type: blockType,
 		DataLength: dataLength,
+		HeaderOffset: headerOffset,
+		Offset: headerOffset + 4,
 	}
 
 	switch blockType {
@@ -708,7 +994,7 @@ func (flac *FLAC) parseMetadataBlock(handle *os.File) (block IFLACMetadataBlock,
 			}
 
 		case Invalid:
-			err = errors.New("Invalid")
+			err = fmt.Errorf("%w: %d", ErrInvalidBlockType, blockType)
 
 			return
 
@@ -718,41 +1004,130 @@ func (flac *FLAC) parseMetadataBlock(handle *os.File) (block IFLACMetadataBlock,
 			}
 	}
 
-	err = block.parse(handle)
+	if blockType != StreamInfo && flac.options.skip(blockType) {
+		block = &FLACMetadataBlockSkipped{
+			FLACMetadataBlock: blockHeader,
+		}
+	}
+
+	limitReached := blockType != StreamInfo && flac.options.blockLimitReached(len(flac.MetadataBlocks))
+	tooLarge := blockType == Picture && flac.options.pictureTooLarge(dataLength)
+
+	if tooLarge && !limitReached {
+		flac.Warnings = append(flac.Warnings, Warning{
+			Type: blockType,
+			Offset: blockHeader.HeaderOffset,
+			Message: fmt.Sprintf("picture of %d bytes exceeds the maximum picture size and was skipped", dataLength),
+		})
+	}
+
+	if limitReached || tooLarge {
+		block = &FLACMetadataBlockSkipped{
+			FLACMetadataBlock: blockHeader,
+		}
+		err = block.parse(reader)
+
+		return
+	}
+
+	if flac.options.keepRawData() {
+		raw := make([]byte, dataLength)
+		_, err = io.ReadFull(reader, raw)
+
+		if err != nil {
+			return
+		}
+
+		block.header().RawData = raw
+		err = block.parse(&countingReader{
+			reader: bytes.NewReader(raw),
+			count: blockHeader.Offset,
+		})
+
+		return
+	}
+
+	err = block.parse(reader)
 
 	return
 }
 
-func (flac *FLAC) parseStreamInfo(handle *os.File) (err error) {
-	streamInfo, err := flac.parseMetadataBlock(handle)
+func (flac *FLAC) parseStreamInfo(reader *countingReader) (err error) {
+	offset := reader.count
+	streamInfo, err := flac.parseMetadataBlock(reader)
 
 	if err != nil {
+		err = newParseError(0, offset, streamInfo, err)
+
 		return
 	}
 
-	flac.StreamInfo = streamInfo.(*FLACMetadataBlockStreamInfo)
+	block, ok := streamInfo.(*FLACMetadataBlockStreamInfo)
+
+	if !ok {
+		err = newParseError(0, offset, streamInfo, fmt.Errorf("%w: expected stream info", ErrInvalidBlockType))
+
+		return
+	}
+
+	flac.StreamInfo = block
+
+	return
+}
+
+// parseStream parses the metadata from source, reporting to the Metrics in effect for the parse.
+func (flac *FLAC) parseStream(source io.Reader) (err error) {
+	reader, ok := source.(*countingReader)
+
+	if !ok {
+		reader = &countingReader{
+			reader: source,
+		}
+	}
+
+	metrics := flac.options.metrics()
+
+	if metrics == nil {
+		return flac.parseBlocks(reader)
+	}
+
+	start := time.Now()
+	count := reader.count
+	err = flac.parseBlocks(reader)
+
+	metrics.BytesRead(reader.count - count)
+
+	if err != nil {
+		metrics.ParseError(flac.path, err)
+	} else {
+		metrics.FileParsed(flac.path, time.Since(start))
+	}
 
 	return
 }
 
-func (flac *FLAC) parseStream(handle *os.File) (err error) {
+func (flac *FLAC) parseBlocks(reader *countingReader) (err error) {
 	marker := make([]byte, 4)
 
-	_, err = handle.Read(marker)
+	_, err = io.ReadFull(reader, marker)
 
 	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = ErrNotFLAC
+		}
+
 		return
 	}
 
 	flac.Marker = string(marker)
 
 	if flac.Marker != FLACMarker {
-		err = errors.New("FLAC marker not found")
+		err = ErrNotFLAC
 
 		return
 	}
 
-	err = flac.parseStreamInfo(handle)
+	err = flac.parseStreamInfo(reader)
 
 	if err != nil {
 		return
@@ -760,34 +1135,176 @@ func (flac *FLAC) parseStream(handle *os.File) (err error) {
 
 	last := flac.StreamInfo.FLACMetadataBlock.Last
 	var iBlock IFLACMetadataBlock
+	var leftOut *FLACMetadataBlock
 
 	for !last {
-		iBlock, err = flac.parseMetadataBlock(handle)
+		offset := reader.count
+		iBlock, err = flac.parseMetadataBlock(reader)
 
 		if err != nil {
+			err = newParseError(len(flac.MetadataBlocks) + 1, offset, iBlock, err)
+
 			return
 		}
 
-		flac.MetadataBlocks = append(flac.MetadataBlocks, iBlock)
 		last = iBlock.isLast()
+
+		if flac.options.blockLimitReached(len(flac.MetadataBlocks)) {
+			if flac.blocksLeftOut == 0 {
+				leftOut = iBlock.header()
+			}
+
+			flac.blocksLeftOut++
+
+			continue
+		}
+
+		flac.MetadataBlocks = append(flac.MetadataBlocks, iBlock)
+	}
+
+	if flac.blocksLeftOut > 0 {
+		flac.Warnings = append(flac.Warnings, Warning{
+			Type: leftOut.Type,
+			Offset: leftOut.HeaderOffset,
+			Message: fmt.Sprintf("%d metadata blocks beyond the maximum block count were left out", flac.blocksLeftOut),
+		})
+	}
+
+	flac.AudioOffset = reader.count
+
+	return
+}
+
+// ParseReader reads FLAC metadata from a reader. Reading stops after the last metadata block.
+func ParseReader(reader io.Reader, options ...ParseOption) (flac *FLAC, err error) {
+	flac = &FLAC{
+		buffer: bitbuffer.NewBitBuffer(binary.BigEndian),
+		options: newParseOptions(options),
+	}
+
+	err = flac.parseStream(reader)
+
+	return
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	reader io.Reader
+	count int64
+}
+
+func (reader *countingReader) Read(data []byte) (n int, err error) {
+	n, err = reader.reader.Read(data)
+	reader.count += int64(n)
+
+	return
+}
+
+// skip advances past n bytes, seeking instead of reading when the underlying reader allows it.
+func (reader *countingReader) skip(n int64) (err error) {
+	if seeker, ok := reader.reader.(io.Seeker); ok {
+		_, err = seeker.Seek(n, io.SeekCurrent)
+
+		if err == nil {
+			reader.count += n
+		}
+
+		return
+	}
+
+	_, err = io.CopyN(io.Discard, reader, n)
+
+	return
+}
+
+// parseFile parses an opened file, recording what is needed to later detect changes to it.
+func parseFile(file fs.File, path string, fsys fs.FS, options *parseOptions) (flac *FLAC, err error) {
+	flac = &FLAC{
+		buffer: bitbuffer.NewBitBuffer(binary.BigEndian),
+		path: path,
+		fsys: fsys,
+		options: options,
+	}
+
+	reader := &countingReader{
+		reader: file,
+	}
+
+	err = flac.parseStream(reader)
+	metadataOnly := options != nil && options.metadataOnly
+
+	if err == nil {
+		flac.size, flac.modTime, flac.quickHash, err = fileState(file, !metadataOnly)
+	}
+
+	if err != nil || metadataOnly {
+		file.Close()
+	} else {
+		flac.file = file
 	}
 
 	return
 }
 
 // Parse is the primary method for reading in a FLAC file and creating a handle.
-func Parse(path string) (flac *FLAC, err error) {
+// The file is held open for lazy operations until Close is called.
+func Parse(path string, options ...ParseOption) (flac *FLAC, err error) {
 	handle, err := os.Open(path)
 
 	if err != nil {
 		return
 	}
 
-	flac = &FLAC{
-		buffer: bitbuffer.NewBitBuffer(binary.BigEndian),
+	flac, err = parseFile(handle, path, nil, newParseOptions(options))
+
+	return
+}
+
+// ParseFS reads in the named FLAC file from a file system such as an embed.FS or zip archive.
+// The file is held open for lazy operations until Close is called.
+func ParseFS(fsys fs.FS, name string, options ...ParseOption) (flac *FLAC, err error) {
+	file, err := fsys.Open(name)
+
+	if err != nil {
+		return
+	}
+
+	flac, err = parseFile(file, name, fsys, newParseOptions(options))
+
+	return
+}
+
+// ParseMetadata reads only the metadata region of a FLAC file, closing it before returning.
+// Nothing past the last metadata block is read; AudioOffset holds the position where audio frames begin.
+func ParseMetadata(path string, options ...ParseOption) (flac *FLAC, audioOffset int64, err error) {
+	handle, err := os.Open(path)
+
+	if err != nil {
+		return
+	}
+
+	parseOptions := newParseOptions(options)
+	parseOptions.metadataOnly = true
+	flac, err = parseFile(handle, path, nil, parseOptions)
+
+	if err != nil {
+		return
+	}
+
+	audioOffset = flac.AudioOffset
+
+	return
+}
+
+// Close releases the file held open since parsing. It is safe to call more than once, and the
+// parsed metadata remains usable afterwards; lazy operations reopen the file by path.
+func (flac *FLAC) Close() (err error) {
+	if flac.file == nil {
+		return
 	}
 
-	err = flac.parseStream(handle)
+	err = flac.file.Close()
+	flac.file = nil
 
 	return
 }
@@ -1,7 +1,14 @@
 package flac
 
 import (
+	"io"
+	"os"
+	"bytes"
 	"testing"
+	"testing/iotest"
+	"testing/fstest"
+	"path/filepath"
+	"encoding/binary"
 	"encoding/hex"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -23,12 +30,157 @@ func (suite *FLACTestSuite) SetupTest() {
 	suite.assert.Equal("fLaC", suite.flac.Marker)
 }
 
+func (suite *FLACTestSuite) TearDownTest() {
+	suite.NoError(suite.flac.Close())
+}
+
+func (suite *FLACTestSuite) TestClose() {
+	flac, err := Parse("sample.flac", WithLazyPictures())
+
+	suite.assert.NoError(err)
+	suite.assert.NotNil(flac.file)
+
+	data, err := io.ReadAll(flac.FrontCover().Open())
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(suite.flac.FrontCover().Picture, data)
+	suite.assert.NoError(flac.Close())
+	suite.assert.Nil(flac.file)
+	suite.assert.NoError(flac.Close())
+
+	data, err = io.ReadAll(flac.FrontCover().Open())
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(suite.flac.FrontCover().Picture, data)
+
+	flac, _, err = ParseMetadata("sample.flac")
+
+	suite.assert.NoError(err)
+	suite.assert.Nil(flac.file)
+}
+
+func (suite *FLACTestSuite) TestParseReader() {
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	flac, err := ParseReader(iotest.OneByteReader(bytes.NewReader(data)))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(suite.flac.StreamInfo.NumSamples, flac.StreamInfo.NumSamples)
+	suite.assert.Equal(len(suite.flac.MetadataBlocks), len(flac.MetadataBlocks))
+
+	_, err = ParseReader(bytes.NewReader(data[:1000]))
+
+	suite.assert.Error(err)
+
+	_, err = ParseReader(bytes.NewReader([]byte("RIFF....WAVE")))
+
+	suite.assert.Error(err)
+}
+
+func (suite *FLACTestSuite) TestParseErrors() {
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	corrupt := func(index int, value byte) []byte {
+		copied := bytes.Clone(data)
+		copied[index] = value
+
+		return copied
+	}
+
+	_, err = ParseReader(bytes.NewReader([]byte("RIFF....WAVE")))
+
+	suite.assert.ErrorIs(err, ErrNotFLAC)
+
+	_, err = ParseReader(bytes.NewReader([]byte("fL")))
+
+	suite.assert.ErrorIs(err, ErrNotFLAC)
+
+	_, err = ParseReader(bytes.NewReader(data[:1000]))
+
+	suite.assert.ErrorIs(err, ErrTruncated)
+
+	parseErr, ok := err.(*ParseError)
+
+	suite.assert.True(ok)
+	suite.assert.Equal(4, parseErr.Index)
+	suite.assert.Equal(Picture, parseErr.Type)
+	suite.assert.Equal(int64(4 + 38 + 22 + 12 + 60), parseErr.Offset)
+
+	_, err = ParseReader(bytes.NewReader(corrupt(4, 0x7f)))
+
+	suite.assert.ErrorIs(err, ErrInvalidBlockType)
+
+	_, err = ParseReader(bytes.NewReader(corrupt(4, byte(Padding))))
+
+	suite.assert.ErrorIs(err, ErrInvalidBlockType)
+
+	_, err = ParseReader(bytes.NewReader(corrupt(bytes.Index(data, []byte("example=fish")) + 7, '_')))
+
+	suite.assert.ErrorIs(err, ErrMalformedVorbisComment)
+}
+
+func (suite *FLACTestSuite) TestParseFS() {
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	fsys := fstest.MapFS{
+		"music/sample.flac": &fstest.MapFile{Data: data},
+	}
+
+	flac, err := ParseFS(fsys, "music/sample.flac")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(suite.flac.StreamInfo.NumSamples, flac.StreamInfo.NumSamples)
+	suite.assert.Nil(flac.Gap)
+
+	changed, err := flac.Changed()
+
+	suite.assert.NoError(err)
+	suite.assert.False(changed)
+
+	flac, err = ParseFS(os.DirFS("."), "sample.flac")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(len(suite.flac.MetadataBlocks), len(flac.MetadataBlocks))
+
+	_, err = ParseFS(fsys, "missing.flac")
+
+	suite.assert.Error(err)
+}
+
+func (suite *FLACTestSuite) TestParseMetadata() {
+	m := &recordingMetrics{}
+
+	SetMetrics(m)
+	defer SetMetrics(nil)
+
+	flac, audioOffset, err := ParseMetadata("sample.flac")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(4 + 4 * 7 + 34 + 18 + 8 + 56 + 1661438 + 576 + 7596, audioOffset)
+	suite.assert.Equal(audioOffset, flac.AudioOffset)
+	suite.assert.Equal(suite.flac.AudioOffset, audioOffset)
+	suite.assert.Equal(audioOffset, m.bytes)
+
+	changed, err := flac.Changed()
+
+	suite.assert.NoError(err)
+	suite.assert.False(changed)
+}
+
 func (suite *FLACTestSuite) TestFLACMetadataBlockStreamInfo() {
 	suite.assert.NotNil(suite.flac.StreamInfo)
 	suite.assert.Equal(suite.flac, suite.flac.StreamInfo.FLACMetadataBlock.FLAC)
 	suite.assert.False(suite.flac.StreamInfo.FLACMetadataBlock.Last)
 	suite.assert.Equal(StreamInfo, suite.flac.StreamInfo.FLACMetadataBlock.Type)
 	suite.assert.Equal(34, suite.flac.StreamInfo.FLACMetadataBlock.DataLength)
+	suite.assert.Equal(4, suite.flac.StreamInfo.FLACMetadataBlock.HeaderOffset)
+	suite.assert.Equal(8, suite.flac.StreamInfo.FLACMetadataBlock.Offset)
 	suite.assert.Equal(4096, suite.flac.StreamInfo.MinBlockSize)
 	suite.assert.Equal(4096, suite.flac.StreamInfo.MaxBlockSize)
 	suite.assert.Equal(7822, suite.flac.StreamInfo.MinFrameSize)
@@ -41,6 +193,21 @@ func (suite *FLACTestSuite) TestFLACMetadataBlockStreamInfo() {
 		hex.EncodeToString(suite.flac.StreamInfo.UnencodedMD5))
 }
 
+func (suite *FLACTestSuite) TestFLACMetadataBlockOffsets() {
+	offset := suite.flac.StreamInfo.FLACMetadataBlock.Offset + 34
+
+	for _, iBlock := range suite.flac.MetadataBlocks {
+		header := iBlock.header()
+
+		suite.assert.Equal(offset, header.HeaderOffset)
+		suite.assert.Equal(offset + 4, header.Offset)
+
+		offset = header.Offset + int64(header.DataLength)
+	}
+
+	suite.assert.Equal(suite.flac.AudioOffset, offset)
+}
+
 func (suite *FLACTestSuite) TestFLACMetadataBlockSeekTable() {
 	testedBlocks := 0
 
@@ -106,13 +273,7 @@ func (suite *FLACTestSuite) TestFLACMetadataBlockVorbisComment() {
 		suite.assert.Equal(VorbisComment, block.FLACMetadataBlock.Type)
 		suite.assert.Equal(56, block.FLACMetadataBlock.DataLength)
 		suite.assert.Equal("reference libFLAC 1.1.4 20070213", block.VendorString)
-		suite.assert.Equal(1, len(block.Comments))
-
-		value, ok := block.Comments["example"]
-
-		suite.assert.True(ok)
-		suite.assert.Equal(1, len(value))
-		suite.assert.Contains(value, "fish")
+		suite.assert.Equal([]Comment{{"example", "fish"}}, block.Comments)
 	}
 
 	suite.assert.Equal(1, testedBlocks)
@@ -253,6 +414,73 @@ func (suite *FLACTestSuite) TestFLACMetadataBlockPadding() {
 	suite.assert.Equal(1, testedBlocks)
 }
 
+func (suite *FLACTestSuite) TestFLACMetadataBlockVorbisCommentLengths() {
+	payloads := [][]byte{
+		{0xff, 0xff, 0xff, 0xff, 'x', 0, 0, 0, 0},
+		{1, 0, 0, 0, 'x', 0xff, 0xff, 0xff, 0xff},
+		{1, 0, 0, 0, 'x', 1, 0, 0, 0, 0xfe, 0xff, 0xff, 0xff, 'a', '=', 'b'},
+	}
+
+	for _, payload := range payloads {
+		path := filepath.Join(suite.T().TempDir(), "vorbis")
+
+		suite.assert.NoError(os.WriteFile(path, payload, 0644))
+
+		handle, err := os.Open(path)
+
+		suite.assert.NoError(err)
+
+		block := &FLACMetadataBlockVorbisComment{
+			FLACMetadataBlock: FLACMetadataBlock{
+				Type: VorbisComment,
+				DataLength: uint32(len(payload)),
+			},
+		}
+
+		err = block.parse(handle)
+		handle.Close()
+
+		_, ok := err.(*CommentLengthError)
+
+		suite.assert.True(ok)
+		suite.assert.ErrorIs(err, ErrMalformedVorbisComment)
+	}
+}
+
+func (suite *FLACTestSuite) TestFLACMetadataBlockStreamInfoBitDepths() {
+	for _, bitsPerSample := range []uint64{4, 12, 20, 24, 32} {
+		payload := make([]byte, 34)
+
+		binary.BigEndian.PutUint16(payload[0:], 4096)
+		binary.BigEndian.PutUint16(payload[2:], 4096)
+		binary.BigEndian.PutUint64(payload[10:], 48000 << 44 | 5 << 41 | (bitsPerSample - 1) << 36 | 123456789)
+
+		path := filepath.Join(suite.T().TempDir(), "streaminfo")
+
+		suite.assert.NoError(os.WriteFile(path, payload, 0644))
+
+		handle, err := os.Open(path)
+
+		suite.assert.NoError(err)
+
+		block := &FLACMetadataBlockStreamInfo{
+			FLACMetadataBlock: FLACMetadataBlock{
+				FLAC: suite.flac,
+				Type: StreamInfo,
+				DataLength: 34,
+			},
+		}
+
+		suite.assert.NoError(block.parse(handle))
+		handle.Close()
+
+		suite.assert.Equal(48000, block.SampleRate)
+		suite.assert.Equal(6, block.Channels)
+		suite.assert.Equal(bitsPerSample, block.BitsPerSample)
+		suite.assert.Equal(123456789, block.NumSamples)
+	}
+}
+
 func TestFLACTestSuite(t *testing.T) {
 	suite.Run(t, new(FLACTestSuite))
 }
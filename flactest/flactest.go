@@ -0,0 +1,270 @@
+// Package flactest synthesizes valid FLAC files for tests, so integrations can be tested without binary fixtures.
+package flactest
+
+import (
+	"io"
+	"os"
+	"fmt"
+	"bytes"
+	"image"
+	"testing"
+	"image/png"
+	"hash/crc32"
+	"crypto/md5"
+	"path/filepath"
+	"encoding/binary"
+	"github.com/garfunkel/go-flac"
+)
+
+// blockSize is the number of samples in each frame but the last, matching the stream info from flac.NewStreamInfo.
+const blockSize = 4096
+
+// Spec describes a file to synthesize. Zero SampleRate, Channels and BitsPerSample default to 44.1kHz 16 bit
+// stereo. Samples is the length of the silent audio written after the metadata, which is left out when zero.
+// Blocks are written in order after the stream info.
+type Spec struct {
+	SampleRate uint32
+	Channels uint8
+	BitsPerSample uint8
+	Samples uint64
+	Blocks []flac.IFLACMetadataBlock
+}
+
+// Build returns the bytes of a FLAC file as described by spec. The audio is silence coded as constant subframes,
+// in frames of 4096 samples, and the stream info holds its MD5 signature.
+func Build(spec Spec) (data []byte, err error) {
+	if spec.SampleRate == 0 {
+		spec.SampleRate = 44100
+	}
+
+	if spec.Channels == 0 {
+		spec.Channels = 2
+	}
+
+	if spec.BitsPerSample == 0 {
+		spec.BitsPerSample = 16
+	}
+
+	streamInfo, err := flac.NewStreamInfo(spec.SampleRate, spec.Channels, spec.BitsPerSample, spec.Samples)
+
+	if err != nil {
+		return
+	}
+
+	streamInfo.UnencodedMD5 = silenceMD5(spec)
+	file := flac.New(streamInfo)
+
+	for index, block := range spec.Blocks {
+		err = file.AppendBlock(block)
+
+		if err != nil {
+			err = fmt.Errorf("block %d: %w", index, err)
+
+			return
+		}
+	}
+
+	var buffer bytes.Buffer
+
+	_, err = file.WriteMetadata(&buffer)
+
+	if err != nil {
+		return
+	}
+
+	data = buffer.Bytes()
+
+	for frame := uint64(0); frame * blockSize < spec.Samples; frame++ {
+		data = appendFrame(data, frame, min(spec.Samples - frame * blockSize, blockSize), spec)
+	}
+
+	return
+}
+
+// WriteFile writes the file described by spec to path.
+func WriteFile(path string, spec Spec) (err error) {
+	data, err := Build(spec)
+
+	if err != nil {
+		return
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// TempFile writes the file described by spec to a temporary directory removed when the test ends, failing the
+// test if it cannot, and returns its path.
+func TempFile(t testing.TB, spec Spec) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.flac")
+
+	if err := WriteFile(path, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// PNG returns a black greyscale PNG image of the given size.
+func PNG(width int, height int) []byte {
+	var buffer bytes.Buffer
+
+	if err := png.Encode(&buffer, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		panic(err)
+	}
+
+	return buffer.Bytes()
+}
+
+// Picture returns a picture block holding a small PNG image grown to size bytes with a private ancillary chunk,
+// which decoders skip, for testing large pictures cheaply.
+func Picture(pictureType flac.PictureType, size int) (block *flac.FLACMetadataBlockPicture, err error) {
+	data := PNG(16, 16)
+
+	// The filler chunk goes before the 12 byte IEND chunk and takes 12 bytes of its own around its data.
+	if filler := size - len(data) - 12; filler >= 0 {
+		chunk := make([]byte, 12 + filler)
+
+		binary.BigEndian.PutUint32(chunk, uint32(filler))
+		copy(chunk[4:], "flPd")
+		binary.BigEndian.PutUint32(chunk[8 + filler:], crc32.ChecksumIEEE(chunk[4:8 + filler]))
+
+		end := len(data) - 12
+		data = append(data[:end:end], append(chunk, data[end:]...)...)
+	}
+
+	return flac.NewPicture(pictureType, "image/png", "", data)
+}
+
+// Comments returns a vorbis comment block holding count comments, named COMMENT1, COMMENT2 and so on.
+func Comments(count int) (block *flac.FLACMetadataBlockVorbisComment, err error) {
+	block = flac.NewVorbisComment("")
+
+	for index := 1; index <= count; index++ {
+		err = block.AddTag(fmt.Sprintf("COMMENT%d", index), fmt.Sprintf("value %d", index))
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Placeholders returns a seek table holding only count placeholder points.
+func Placeholders(count int) *flac.FLACMetadataBlockSeekTable {
+	block := &flac.FLACMetadataBlockSeekTable{
+		FLACMetadataBlock: flac.FLACMetadataBlock{
+			Type: flac.SeekTable,
+			DataLength: uint32(18 * count),
+		},
+		SeekPoints: []flac.SeekPoint{},
+	}
+
+	for point := 0; point < count; point++ {
+		block.SeekPoints = append(block.SeekPoints, flac.SeekPoint{Sample: flac.SeekPlaceholder})
+	}
+
+	return block
+}
+
+// silenceMD5 returns the MD5 signature of spec.Samples of silence, which is the signature of as many zero bytes
+// as the unencoded samples take.
+func silenceMD5(spec Spec) []byte {
+	hash := md5.New()
+	length := int64(spec.Samples) * int64(spec.Channels) * int64((spec.BitsPerSample + 7) / 8)
+
+	io.CopyN(hash, zeros{}, length)
+
+	return hash.Sum(nil)
+}
+
+// zeros reads an endless stream of zero bytes.
+type zeros struct{}
+
+func (zeros) Read(data []byte) (n int, err error) {
+	clear(data)
+
+	return len(data), nil
+}
+
+// appendFrame appends frame number frame, holding samples samples of silence, to data. The sample rate and sample
+// size are taken from the stream info and every channel is a constant subframe of zero.
+func appendFrame(data []byte, frame uint64, samples uint64, spec Spec) []byte {
+	start := len(data)
+
+	// A full frame uses the 4096 block size code; a shorter last frame stores its size in 16 bits.
+	if samples == blockSize {
+		data = append(data, 0xff, 0xf8, 12 << 4, (spec.Channels - 1) << 4)
+		data = appendNumber(data, frame)
+	} else {
+		data = append(data, 0xff, 0xf8, 7 << 4, (spec.Channels - 1) << 4)
+		data = appendNumber(data, frame)
+		data = binary.BigEndian.AppendUint16(data, uint16(samples - 1))
+	}
+
+	data = append(data, crc8(data[start:]))
+
+	// Each subframe is an 8 bit header of zeros for a constant subframe followed by the zero value, and the
+	// frame is padded to a whole byte.
+	bits := int(spec.Channels) * (8 + int(spec.BitsPerSample))
+	data = append(data, make([]byte, (bits + 7) / 8)...)
+
+	return binary.BigEndian.AppendUint16(data, crc16(data[start:]))
+}
+
+// appendNumber appends number coded like UTF-8, as frame numbers are.
+func appendNumber(data []byte, number uint64) []byte {
+	if number < 0x80 {
+		return append(data, byte(number))
+	}
+
+	length := 2
+
+	for number >> (5 * length + 1) != 0 {
+		length++
+	}
+
+	data = append(data, byte(0xff00 >> length | number >> (6 * (length - 1))))
+
+	for shift := 6 * (length - 2); shift >= 0; shift -= 6 {
+		data = append(data, 0x80 | byte(number >> shift) & 0x3f)
+	}
+
+	return data
+}
+
+// crc8 computes the CRC-8 that protects a frame header, with polynomial x^8 + x^2 + x + 1.
+func crc8(data []byte) (crc byte) {
+	for _, value := range data {
+		crc ^= value
+
+		for bit := 0; bit < 8; bit++ {
+			if crc & 0x80 != 0 {
+				crc = crc << 1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return
+}
+
+// crc16 computes the CRC-16 that ends a frame, with polynomial x^16 + x^15 + x^2 + 1.
+func crc16(data []byte) (crc uint16) {
+	for _, value := range data {
+		crc ^= uint16(value) << 8
+
+		for bit := 0; bit < 8; bit++ {
+			if crc & 0x8000 != 0 {
+				crc = crc << 1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,88 @@
+package flactest
+
+import (
+	"testing"
+	"crypto/md5"
+	"github.com/garfunkel/go-flac"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	assert := assert.New(t)
+	picture, err := Picture(flac.FrontCover, 1 << 20)
+
+	assert.NoError(err)
+
+	comments, err := Comments(500)
+
+	assert.NoError(err)
+
+	path := TempFile(t, Spec{
+		Channels: 6,
+		BitsPerSample: 24,
+		SampleRate: 96000,
+		Samples: 3 * 4096 + 100,
+		Blocks: []flac.IFLACMetadataBlock{Placeholders(4), comments, picture, flac.NewPadding(1024)},
+	})
+	parsed, err := flac.Parse(path)
+
+	assert.NoError(err)
+
+	defer parsed.Close()
+
+	assert.Equal(uint8(6), parsed.StreamInfo.Channels)
+	assert.Equal(uint8(24), parsed.StreamInfo.BitsPerSample)
+	assert.Equal(uint32(96000), parsed.StreamInfo.SampleRate)
+	assert.Equal(uint64(3 * 4096 + 100), parsed.StreamInfo.NumSamples)
+	assert.Len(parsed.SeekTable().SeekPoints, 4)
+	assert.Len(parsed.VorbisComment().Comments, 500)
+	assert.Equal([]string{"value 7"}, parsed.VorbisComment().GetTag("COMMENT7"))
+	assert.Len(parsed.FrontCover().Picture, 1 << 20)
+	assert.Equal(uint32(16), parsed.FrontCover().Width)
+	assert.Empty(parsed.Conformance())
+
+	silence := md5.Sum(make([]byte, (3 * 4096 + 100) * 6 * 3))
+
+	assert.Equal(silence[:], parsed.StreamInfo.UnencodedMD5)
+
+	// Frames are 6 header bytes, a 4 byte constant subframe per channel and a 2 byte CRC; the last frame also
+	// holds its 16 bit block size.
+	table, err := parsed.GenerateSeekTable("4096;8192;12288")
+
+	assert.NoError(err)
+	assert.Equal([]flac.SeekPoint{
+		{Sample: 4096, ByteOffset: 32, NumSamples: 4096},
+		{Sample: 8192, ByteOffset: 64, NumSamples: 4096},
+		{Sample: 12288, ByteOffset: 96, NumSamples: 100},
+	}, table.SeekPoints)
+}
+
+func TestBuildEmpty(t *testing.T) {
+	assert := assert.New(t)
+	data, err := Build(Spec{})
+
+	assert.NoError(err)
+	assert.Len(data, 4 + 4 + 34)
+
+	_, err = Build(Spec{Channels: 9})
+
+	assert.Error(err)
+}
+
+func TestAppendNumber(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]byte{0x7f}, appendNumber(nil, 0x7f))
+	assert.Equal([]byte("\u0080"), appendNumber(nil, 0x80))
+	assert.Equal([]byte("߿"), appendNumber(nil, 0x7ff))
+	assert.Equal([]byte("ࠀ"), appendNumber(nil, 0x800))
+	assert.Equal([]byte("\U0010ffff"), appendNumber(nil, 0x10ffff))
+	assert.Equal([]byte{0xfd, 0xbf, 0xbf, 0xbf, 0xbf, 0xbf}, appendNumber(nil, 0x7fffffff))
+}
+
+func TestCRC(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(byte(0xf4), crc8([]byte("123456789")))
+	assert.Equal(uint16(0xfee8), crc16([]byte("123456789")))
+}
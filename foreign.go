@@ -0,0 +1,364 @@
+package flac
+
+import (
+	"io"
+	"bytes"
+	"errors"
+	"strings"
+	"strconv"
+	"encoding/binary"
+)
+
+// ForeignTagType identifies a tag format written after the audio frames by tools for other formats.
+type ForeignTagType uint
+
+// Enum indicating the foreign tag formats that are detected.
+const (
+	APEv2 ForeignTagType = iota
+	ID3v1
+)
+
+// ForeignTag is a tag found after the audio frames, with its fields converted to vorbis comment names.
+// Offset and Size give the bytes it occupies in the file.
+type ForeignTag struct {
+	Type ForeignTagType
+	Offset int64
+	Size int64
+	Tags *TagMap
+}
+
+// apeFooterSize is the size of the APEv2 header and footer.
+const apeFooterSize = 32
+
+// apeKeys maps APEv2 item keys, upper-cased, that differ from the vorbis comment names they correspond to.
+var apeKeys = map[string]string{
+	"YEAR": "DATE",
+	"TRACK": "TRACKNUMBER",
+	"DISC": "DISCNUMBER",
+	"ALBUM ARTIST": "ALBUMARTIST",
+	"DEBUT ALBUM": "ALBUM",
+}
+
+// apePictureTypes maps APEv2 cover art item keys to picture types.
+var apePictureTypes = map[string]PictureType{
+	"COVER ART (FRONT)": FrontCover,
+	"COVER ART (BACK)": BackCover,
+	"COVER ART (OTHER)": Other,
+	"COVER ART (ICON)": FileIcon,
+	"COVER ART (MEDIA)": Media,
+	"COVER ART (LEAFLET)": LeafletPage,
+	"COVER ART (ARTIST)": Artist,
+}
+
+// id3v1Genres are the genres an ID3v1 genre byte indexes, including the Winamp extensions from 80 on.
+var id3v1Genres = []string{
+	"Blues", "Classic Rock", "Country", "Dance", "Disco", "Funk", "Grunge", "Hip-Hop", "Jazz", "Metal",
+	"New Age", "Oldies", "Other", "Pop", "R&B", "Rap", "Reggae", "Rock", "Techno", "Industrial",
+	"Alternative", "Ska", "Death Metal", "Pranks", "Soundtrack", "Euro-Techno", "Ambient", "Trip-Hop", "Vocal", "Jazz+Funk",
+	"Fusion", "Trance", "Classical", "Instrumental", "Acid", "House", "Game", "Sound Clip", "Gospel", "Noise",
+	"AlternRock", "Bass", "Soul", "Punk", "Space", "Meditative", "Instrumental Pop", "Instrumental Rock", "Ethnic", "Gothic",
+	"Darkwave", "Techno-Industrial", "Electronic", "Pop-Folk", "Eurodance", "Dream", "Southern Rock", "Comedy", "Cult", "Gangsta",
+	"Top 40", "Christian Rap", "Pop/Funk", "Jungle", "Native American", "Cabaret", "New Wave", "Psychadelic", "Rave", "Showtunes",
+	"Trailer", "Lo-Fi", "Tribal", "Acid Punk", "Acid Jazz", "Polka", "Retro", "Musical", "Rock & Roll", "Hard Rock",
+	"Folk", "Folk-Rock", "National Folk", "Swing", "Fast Fusion", "Bebop", "Latin", "Revival", "Celtic", "Bluegrass",
+	"Avantgarde", "Gothic Rock", "Progressive Rock", "Psychedelic Rock", "Symphonic Rock", "Slow Rock", "Big Band", "Chorus", "Easy Listening", "Acoustic",
+	"Humour", "Speech", "Chanson", "Opera", "Chamber Music", "Sonata", "Symphony", "Booty Bass", "Primus", "Porn Groove",
+	"Satire", "Slow Jam", "Club", "Tango", "Samba", "Folklore", "Ballad", "Power Ballad", "Rhythmic Soul", "Freestyle",
+	"Duet", "Punk Rock", "Drum Solo", "A capella", "Euro-House", "Dance Hall", "Goa", "Drum & Bass", "Club-House", "Hardcore Techno",
+	"Terror", "Indie", "BritPop", "Negerpunk", "Polsk Punk", "Beat", "Christian Gangsta Rap", "Heavy Metal", "Black Metal", "Crossover",
+	"Contemporary Christian", "Christian Rock", "Merengue", "Salsa", "Thrash Metal", "Anime", "Jpop", "Synthpop", "Abstract", "Art Rock",
+	"Baroque", "Bhangra", "Big Beat", "Breakbeat", "Chillout", "Downtempo", "Dub", "EBM", "Eclectic", "Electro",
+	"Electroclash", "Emo", "Experimental", "Garage", "Global", "IDM", "Illbient", "Industro-Goth", "Jam Band", "Krautrock",
+	"Leftfield", "Lounge", "Math Rock", "New Romantic", "Nu-Breakz", "Post-Punk", "Post-Rock", "Psytrance", "Shoegaze", "Space Rock",
+	"Trop Rock", "World Music", "Neoclassical", "Audiobook", "Audio Theatre", "Neue Deutsche Welle", "Podcast", "Indie Rock", "G-Funk", "Dubstep",
+	"Garage Rock", "Psybient",
+}
+
+// ForeignTags looks for APEv2 and ID3v1 tags after the audio frames of the file the metadata was parsed from
+// and returns them in the order they appear.
+func (flac *FLAC) ForeignTags() (tags []ForeignTag, err error) {
+	if flac.path == "" {
+		err = errors.New("FLAC was not parsed from a file")
+
+		return
+	}
+
+	file, err := flac.open()
+
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return
+	}
+
+	readerAt, ok := file.(io.ReaderAt)
+
+	if !ok {
+		err = errors.New("file does not support random access")
+
+		return
+	}
+
+	end := info.Size()
+
+	if end - 128 >= flac.AudioOffset {
+		var tag *ForeignTag
+
+		tag, err = parseID3v1(readerAt, end - 128)
+
+		if err != nil {
+			return
+		}
+
+		if tag != nil {
+			tags = append(tags, *tag)
+			end = tag.Offset
+		}
+	}
+
+	if end - apeFooterSize >= flac.AudioOffset {
+		var tag *ForeignTag
+
+		tag, err = parseAPEv2(readerAt, end, flac.AudioOffset)
+
+		if err != nil {
+			return
+		}
+
+		if tag != nil {
+			tags = append([]ForeignTag{*tag}, tags...)
+		}
+	}
+
+	return
+}
+
+// id3v1String decodes an ISO-8859-1 ID3v1 field and trims its NUL and space padding.
+func id3v1String(field []byte) string {
+	if index := bytes.IndexByte(field, 0); index >= 0 {
+		field = field[:index]
+	}
+
+	var builder strings.Builder
+
+	for _, value := range field {
+		builder.WriteRune(rune(value))
+	}
+
+	return strings.TrimSpace(builder.String())
+}
+
+// parseID3v1 parses the 128 byte ID3v1 tag at offset, returning nil if there is none.
+func parseID3v1(reader io.ReaderAt, offset int64) (tag *ForeignTag, err error) {
+	data := make([]byte, 128)
+	_, err = reader.ReadAt(data, offset)
+
+	if err != nil || string(data[:3]) != "TAG" {
+		return
+	}
+
+	tags := &TagMap{
+		Target: TargetVorbis,
+	}
+	add := func(key string, value string) {
+		if value != "" {
+			tags.Fields = append(tags.Fields, TagField{
+				Key: key,
+				Values: []string{value},
+			})
+		}
+	}
+
+	add(TitleTag, id3v1String(data[3:33]))
+	add(ArtistTag, id3v1String(data[33:63]))
+	add(AlbumTag, id3v1String(data[63:93]))
+	add(DateTag, id3v1String(data[93:97]))
+
+	// ID3v1.1 stores the track number in the last byte of the comment when the byte before it is zero.
+	if data[125] == 0 && data[126] != 0 {
+		add(CommentTag, id3v1String(data[97:125]))
+		add(TrackNumberTag, strconv.Itoa(int(data[126])))
+	} else {
+		add(CommentTag, id3v1String(data[97:127]))
+	}
+
+	if int(data[127]) < len(id3v1Genres) {
+		add(GenreTag, id3v1Genres[data[127]])
+	}
+
+	tag = &ForeignTag{
+		Type: ID3v1,
+		Offset: offset,
+		Size: 128,
+		Tags: tags,
+	}
+
+	return
+}
+
+// parseAPEv2 parses an APEv2 tag ending at end, returning nil if there is none. The tag may not extend before start.
+func parseAPEv2(reader io.ReaderAt, end int64, start int64) (tag *ForeignTag, err error) {
+	footer := make([]byte, apeFooterSize)
+	_, err = reader.ReadAt(footer, end - apeFooterSize)
+
+	if err != nil || string(footer[:8]) != "APETAGEX" {
+		return
+	}
+
+	size := int64(binary.LittleEndian.Uint32(footer[12:]))
+	count := binary.LittleEndian.Uint32(footer[16:])
+	flags := binary.LittleEndian.Uint32(footer[20:])
+	offset := end - size
+
+	if flags & (1 << 31) != 0 {
+		offset -= apeFooterSize
+	}
+
+	if size < apeFooterSize || offset < start {
+		err = errors.New("APEv2 tag size exceeds the data after the audio frames")
+
+		return
+	}
+
+	items := make([]byte, size - apeFooterSize)
+	_, err = reader.ReadAt(items, end - size)
+
+	if err != nil {
+		return
+	}
+
+	tags := &TagMap{
+		Target: TargetVorbis,
+	}
+
+	for item := uint32(0); item < count; item++ {
+		if len(items) < 9 {
+			err = errors.New("APEv2 tag item truncated")
+
+			return
+		}
+
+		length := binary.LittleEndian.Uint32(items)
+		itemFlags := binary.LittleEndian.Uint32(items[4:])
+		keyEnd := bytes.IndexByte(items[8:], 0)
+
+		if keyEnd < 0 || uint64(8 + keyEnd + 1) + uint64(length) > uint64(len(items)) {
+			err = errors.New("APEv2 tag item truncated")
+
+			return
+		}
+
+		key := strings.ToUpper(string(items[8:8 + keyEnd]))
+		value := items[8 + keyEnd + 1:8 + keyEnd + 1 + int(length)]
+		items = items[8 + keyEnd + 1 + int(length):]
+
+		switch (itemFlags >> 1) & 3 {
+			case 0:
+				if name, ok := apeKeys[key]; ok {
+					key = name
+				}
+
+				tags.Fields = append(tags.Fields, TagField{
+					Key: key,
+					Values: strings.Split(string(value), "\x00"),
+				})
+
+			case 1:
+				pictureType, ok := apePictureTypes[key]
+				separator := bytes.IndexByte(value, 0)
+
+				if !ok || separator < 0 {
+					continue
+				}
+
+				tags.Artwork = append(tags.Artwork, TagArtwork{
+					Type: pictureType,
+					Description: string(value[:separator]),
+					Data: value[separator + 1:],
+				})
+		}
+	}
+
+	tag = &ForeignTag{
+		Type: APEv2,
+		Offset: offset,
+		Size: end - offset,
+		Tags: tags,
+	}
+
+	return
+}
+
+// MigrateForeignTags copies the fields and artwork of any APEv2 and ID3v1 tags after the audio frames into the
+// vorbis comments and pictures, then saves the file with options. Fields already present in the vorbis comments
+// are kept, and APEv2 fields take precedence over the truncated ID3v1 ones. With remove, the foreign tags are
+// left out of the saved file, which is then always rewritten as a whole through a temporary file; a save with
+// WithDryRun leaves them in place. It returns the tags that were found.
+func (flac *FLAC) MigrateForeignTags(remove bool, options ...SaveOption) (tags []ForeignTag, err error) {
+	tags, err = flac.ForeignTags()
+
+	if err != nil || len(tags) == 0 {
+		return
+	}
+
+	saveOptions := newSaveOptions(options)
+
+	// Migrate into a copy on a dry run, leaving the caller's tags and pictures as they were.
+	if saveOptions.report != nil {
+		flac = flac.Clone()
+	}
+
+	comments := flac.VorbisComment()
+
+	if comments == nil {
+		comments = NewVorbisComment("")
+		err = flac.AppendBlock(comments)
+
+		if err != nil {
+			return
+		}
+	}
+
+	present := comments.Map()
+
+	for _, tag := range tags {
+		migrated := &TagMap{
+			Target: TargetVorbis,
+			Artwork: tag.Tags.Artwork,
+		}
+
+		for _, field := range tag.Tags.Fields {
+			if _, ok := present[strings.ToUpper(field.Key)]; !ok && validTagKey(field.Key) {
+				migrated.Fields = append(migrated.Fields, field)
+				present[strings.ToUpper(field.Key)] = field.Values
+			}
+		}
+
+		// Artwork in an ID3v1 tag is impossible and APEv2 artwork is only taken when there is none already.
+		if len(flac.Pictures()) > 0 {
+			migrated.Artwork = nil
+		}
+
+		err = flac.ImportTagMap(migrated)
+
+		if err != nil {
+			return
+		}
+	}
+
+	if remove && saveOptions.report == nil {
+		for _, tag := range tags {
+			saveOptions.trailing += tag.Size
+		}
+	}
+
+	err = flac.save(saveOptions)
+
+	return
+}
@@ -0,0 +1,116 @@
+package flac
+
+import (
+	"os"
+	"bytes"
+	"encoding/binary"
+)
+
+// apeTag builds an APEv2 tag with a header holding the given text items.
+func apeTag(items ...string) []byte {
+	var body bytes.Buffer
+
+	for index := 0; index < len(items); index += 2 {
+		binary.Write(&body, binary.LittleEndian, uint32(len(items[index + 1])))
+		binary.Write(&body, binary.LittleEndian, uint32(0))
+		body.WriteString(items[index] + "\x00" + items[index + 1])
+	}
+
+	frame := func(flags uint32) []byte {
+		data := []byte("APETAGEX")
+		data = binary.LittleEndian.AppendUint32(data, 2000)
+		data = binary.LittleEndian.AppendUint32(data, uint32(body.Len() + apeFooterSize))
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(items) / 2))
+		data = binary.LittleEndian.AppendUint32(data, flags)
+
+		return append(data, make([]byte, 8)...)
+	}
+
+	tag := frame(1 << 31 | 1 << 29)
+	tag = append(tag, body.Bytes()...)
+
+	return append(tag, frame(1 << 31)...)
+}
+
+func (suite *FLACTestSuite) TestForeignTags() {
+	path, original := suite.copySample()
+	ape := apeTag("Title", "Song", "Year", "2007", "Artist", "A\x00B")
+	id3 := make([]byte, 128)
+
+	copy(id3, "TAG")
+	copy(id3[3:], "Truncated title")
+	copy(id3[33:], "Someone")
+	copy(id3[63:], "Caf\xe9 \xc6ther")
+	id3[126] = 7
+	id3[127] = 137
+
+	suite.assert.NoError(os.WriteFile(path, append(append(original, ape...), id3...), 0644))
+
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	tags, err := flac.ForeignTags()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(2, len(tags))
+	suite.assert.Equal(APEv2, tags[0].Type)
+	suite.assert.Equal(len(original), tags[0].Offset)
+	suite.assert.Equal(len(ape), tags[0].Size)
+	suite.assert.Equal([]TagField{{"TITLE", []string{"Song"}}, {"DATE", []string{"2007"}}, {"ARTIST", []string{"A", "B"}}}, tags[0].Tags.Fields)
+	suite.assert.Equal(ID3v1, tags[1].Type)
+	suite.assert.Contains(tags[1].Tags.Fields, TagField{"TRACKNUMBER", []string{"7"}})
+	suite.assert.Contains(tags[1].Tags.Fields, TagField{"GENRE", []string{"Heavy Metal"}})
+	suite.assert.Contains(tags[1].Tags.Fields, TagField{"ALBUM", []string{"Café Æther"}})
+
+	tagged := append(append(bytes.Clone(original), ape...), id3...)
+	report := &SaveReport{}
+	_, err = flac.MigrateForeignTags(true, WithDryRun(report))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("", flac.VorbisComment().Title())
+	suite.assert.Empty(flac.VorbisComment().GetTag(GenreTag))
+
+	data, err := os.ReadFile(path)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(tagged, data)
+
+	_, err = flac.MigrateForeignTags(true, WithBackup(".bak"))
+
+	suite.assert.NoError(err)
+
+	backup, err := os.ReadFile(path + ".bak")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(tagged, backup)
+
+	saved, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer saved.Close()
+
+	data, err = os.ReadFile(path)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(original[suite.flac.AudioOffset:], data[saved.AudioOffset:])
+
+	comments := saved.VorbisComment()
+
+	suite.assert.Equal("Song", comments.Title())
+	suite.assert.Equal([]string{"A", "B"}, comments.GetTag(ArtistTag))
+	suite.assert.Equal([]string{"fish"}, comments.GetTag("EXAMPLE"))
+	suite.assert.Equal("Café Æther", comments.Album())
+
+	number, _ := comments.TrackNumber()
+
+	suite.assert.Equal(7, number)
+
+	tags, err = saved.ForeignTags()
+
+	suite.assert.NoError(err)
+	suite.assert.Empty(tags)
+}
@@ -0,0 +1,70 @@
+package flac
+
+import (
+	"io"
+)
+
+// frameGapScanLimit bounds how far past the metadata the first frame header is searched for.
+const frameGapScanLimit = 1 << 20
+
+// FrameGap describes bytes found by DetectFrameGap between the last metadata block and the first audio frame.
+type FrameGap struct {
+	Offset int64 `json:"offset"`
+	Size int64 `json:"size"`
+}
+
+// isFrameSync reports whether two bytes start a frame header: a 14 bit sync code followed by a zero reserved bit.
+func isFrameSync(first byte, second byte) bool {
+	return first == 0xff && second & 0xfe == 0xf8
+}
+
+// DetectFrameGap looks for bytes between the last metadata block and the first audio frame of the file the
+// metadata was parsed from, reading up to 1MB past the metadata, and records what it finds in Gap. The first frame
+// is taken to be the first frame header with a valid CRC-8 that numbers itself as the first frame or sample, so
+// sync codes among the gap bytes are not mistaken for it. Parsing leaves Gap unset, as finding it means reading
+// audio.
+func (flac *FLAC) DetectFrameGap() (gap *FrameGap, err error) {
+	source, _, closer, err := flac.audioSource()
+
+	if err != nil {
+		return
+	}
+
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	data := make([]byte, frameGapScanLimit)
+	n, err := source.ReadAt(data, flac.AudioOffset)
+
+	if err == io.EOF {
+		err = nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	for index := 0; index + 1 < n; index++ {
+		if !isFrameSync(data[index], data[index + 1]) {
+			continue
+		}
+
+		if _, _, number, ok := parseFrameHeader(data[index:n]); !ok || number != 0 {
+			continue
+		}
+
+		if index > 0 {
+			gap = &FrameGap{
+				Offset: flac.AudioOffset,
+				Size: int64(index),
+			}
+		}
+
+		break
+	}
+
+	flac.Gap = gap
+
+	return
+}
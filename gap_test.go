@@ -0,0 +1,94 @@
+package flac
+
+import (
+	"os"
+	"bytes"
+	"path/filepath"
+)
+
+func (suite *FLACTestSuite) TestFrameGap() {
+	gap, err := suite.flac.DetectFrameGap()
+
+	suite.assert.NoError(err)
+	suite.assert.Nil(gap)
+	suite.assert.Nil(suite.flac.Gap)
+
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	offset := int(suite.flac.AudioOffset)
+
+	suite.assert.True(isFrameSync(data[offset], data[offset + 1]))
+
+	gapped := append(append(append([]byte{}, data[:offset]...), "ID3 \xff\xf8\x69\x08orphan"...), data[offset:]...)
+	path := filepath.Join(suite.T().TempDir(), "gap.flac")
+
+	suite.assert.NoError(os.WriteFile(path, gapped, 0644))
+
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	suite.assert.Nil(flac.Gap)
+
+	gap, err = flac.DetectFrameGap()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(&FrameGap{Offset: int64(offset), Size: 14}, gap)
+	suite.assert.Equal(gap, flac.Gap)
+}
+
+func (suite *FLACTestSuite) TestWithoutFrameGap() {
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	offset := int(suite.flac.AudioOffset)
+	gapped := append(append(append([]byte{}, data[:offset]...), "orphan"...), data[offset:]...)
+	dir := suite.T().TempDir()
+	path := filepath.Join(dir, "gap.flac")
+
+	suite.assert.NoError(os.WriteFile(path, gapped, 0644))
+
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	kept := filepath.Join(dir, "kept.flac")
+	dropped := filepath.Join(dir, "dropped.flac")
+
+	suite.assert.NoError(flac.SaveAs(kept))
+	suite.assert.NoError(flac.SaveAs(dropped, WithoutFrameGap()))
+
+	for path, expected := range map[string][]byte{kept: gapped, dropped: data} {
+		written, err := os.ReadFile(path)
+
+		suite.assert.NoError(err)
+		suite.assert.Equal(expected[offset:], written[offset:])
+	}
+
+	var buffer bytes.Buffer
+
+	_, err = flac.WriteTo(&buffer)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(gapped, buffer.Bytes())
+
+	report := &SaveReport{}
+
+	suite.assert.NoError(flac.Save(WithoutFrameGap(), WithDryRun(report)))
+	suite.assert.True(report.Rewrite)
+	suite.assert.Equal(int64(len(FLACMarker)) + report.MetadataLength + int64(len(data) - offset), report.Size)
+	suite.assert.NoError(flac.Save(WithoutFrameGap()))
+	suite.assert.Nil(flac.Gap)
+
+	written, err := os.ReadFile(path)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(data[offset:], written[flac.AudioOffset:])
+}
@@ -0,0 +1,8 @@
+module github.com/garfunkel/go-flac
+
+go 1.26.0
+
+require (
+	github.com/stretchr/testify v1.12.1
+	golang.org/x/text v0.42.0
+)
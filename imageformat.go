@@ -0,0 +1,219 @@
+package flac
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"image"
+	"net/http"
+	"encoding/binary"
+)
+
+// imageHeaderSize is how much of the picture data is examined to recognise WebP and AVIF images and read their
+// dimensions, enough to hold the metadata boxes at the start of an AVIF file.
+const imageHeaderSize = 64 * 1024
+
+// imageConfig is the size and colour depth of an image, as recorded in a picture block.
+type imageConfig struct {
+	width uint32
+	height uint32
+	depth uint32
+	colours uint32
+}
+
+// isWebP reports whether data starts with a WebP RIFF header and the type of its first chunk.
+func isWebP(data []byte) bool {
+	return len(data) >= 16 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+// isAVIF reports whether data starts with an ISO base media file type box naming an AVIF brand.
+func isAVIF(data []byte) bool {
+	if len(data) < 16 || string(data[4:8]) != "ftyp" {
+		return false
+	}
+
+	size := int(binary.BigEndian.Uint32(data))
+
+	if size < 16 || size > len(data) {
+		return false
+	}
+
+	for offset := 8; offset + 4 <= size; offset += 4 {
+		if brand := string(data[offset:offset + 4]); brand == "avif" || brand == "avis" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sniffMIMEType returns the MIME type of picture data, recognising WebP and AVIF images as well as the
+// types known to http.DetectContentType.
+func sniffMIMEType(data []byte) string {
+	switch {
+		case isWebP(data):
+			return "image/webp"
+
+		case isAVIF(data):
+			return "image/avif"
+	}
+
+	return http.DetectContentType(data)
+}
+
+// webPConfig reads the dimensions from the first chunk of a WebP image.
+func webPConfig(data []byte) (config imageConfig, err error) {
+	chunkType := string(data[12:16])
+	needed := map[string]int{"VP8 ": 10, "VP8L": 5, "VP8X": 10}[chunkType]
+
+	if len(data) < 20 + needed {
+		err = errors.New("WebP image header truncated")
+
+		return
+	}
+
+	config.depth = 24
+	chunk := data[20:]
+
+	switch chunkType {
+		case "VP8 ":
+			if chunk[3] != 0x9d || chunk[4] != 0x01 || chunk[5] != 0x2a {
+				err = errors.New("invalid WebP VP8 frame header")
+
+				return
+			}
+
+			config.width = uint32(binary.LittleEndian.Uint16(chunk[6:]) & 0x3fff)
+			config.height = uint32(binary.LittleEndian.Uint16(chunk[8:]) & 0x3fff)
+
+		case "VP8L":
+			if chunk[0] != 0x2f {
+				err = errors.New("invalid WebP VP8L signature")
+
+				return
+			}
+
+			bits := binary.LittleEndian.Uint32(chunk[1:])
+			config.width = bits & 0x3fff + 1
+			config.height = bits >> 14 & 0x3fff + 1
+
+			if bits >> 28 & 1 != 0 {
+				config.depth = 32
+			}
+
+		case "VP8X":
+			config.width = uint32(chunk[4]) | uint32(chunk[5]) << 8 | uint32(chunk[6]) << 16 + 1
+			config.height = uint32(chunk[7]) | uint32(chunk[8]) << 8 | uint32(chunk[9]) << 16 + 1
+
+			if chunk[0] & 0x10 != 0 {
+				config.depth = 32
+			}
+
+		default:
+			err = errors.New("unknown WebP chunk")
+	}
+
+	return
+}
+
+// avifConfig reads the dimensions from the image spatial extents property of an AVIF image, and the colour
+// depth from its pixel information property, assuming 8-bit YUV without alpha when that is missing.
+func avifConfig(data []byte) (config imageConfig, err error) {
+	ispe := bytes.Index(data, []byte("ispe"))
+
+	if ispe < 0 || ispe + 16 > len(data) {
+		err = errors.New("AVIF image has no spatial extents")
+
+		return
+	}
+
+	config.width = binary.BigEndian.Uint32(data[ispe + 8:])
+	config.height = binary.BigEndian.Uint32(data[ispe + 12:])
+	config.depth = 24
+
+	if pixi := bytes.Index(data, []byte("pixi")); pixi >= 0 && pixi + 9 <= len(data) {
+		channels := int(data[pixi + 8])
+
+		if pixi + 9 + channels <= len(data) {
+			config.depth = 0
+
+			for _, bits := range data[pixi + 9:pixi + 9 + channels] {
+				config.depth += uint32(bits)
+			}
+		}
+	}
+
+	return
+}
+
+// pngSignature starts every PNG image.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// pngDepth reads the colour depth from the header chunk of a PNG image, as the colour model image/png reports
+// overstates it for truecolour images without alpha and grey images with alpha. Paletted images are recorded with
+// the 24 bit depth of their palette entries.
+func pngDepth(data []byte) (depth uint32, ok bool) {
+	if len(data) < 26 || string(data[:8]) != pngSignature || string(data[12:16]) != "IHDR" {
+		return
+	}
+
+	bitDepth := uint32(data[24])
+	ok = true
+
+	switch data[25] {
+		case 0:
+			depth = bitDepth
+
+		case 2:
+			depth = bitDepth * 3
+
+		case 3:
+			depth = 24
+
+		case 4:
+			depth = bitDepth * 2
+
+		case 6:
+			depth = bitDepth * 4
+
+		default:
+			ok = false
+	}
+
+	return
+}
+
+// imageConfig reads the dimensions and colour depth of the picture, using the registered image decoders for
+// formats other than WebP and AVIF, which the standard library cannot decode.
+func (block *FLACMetadataBlockPicture) imageConfig() (config imageConfig, err error) {
+	reader := block.Open()
+
+	defer closeReader(reader)
+
+	buffered := bufio.NewReaderSize(reader, imageHeaderSize)
+	header, _ := buffered.Peek(imageHeaderSize)
+
+	switch {
+		case isWebP(header):
+			return webPConfig(header)
+
+		case isAVIF(header):
+			return avifConfig(header)
+	}
+
+	decoded, _, err := image.DecodeConfig(buffered)
+
+	if err != nil {
+		return
+	}
+
+	config.width = uint32(decoded.Width)
+	config.height = uint32(decoded.Height)
+	config.depth, config.colours = colourDepth(decoded.ColorModel)
+
+	if depth, ok := pngDepth(header); ok {
+		config.depth = depth
+	}
+
+	return
+}
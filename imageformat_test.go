@@ -0,0 +1,69 @@
+package flac
+
+import (
+	"encoding/binary"
+)
+
+// riff wraps a WebP chunk in a RIFF container.
+func riff(chunkType string, chunk []byte) []byte {
+	data := []byte("RIFF")
+	data = binary.LittleEndian.AppendUint32(data, uint32(12 + len(chunk)))
+	data = append(data, "WEBP" + chunkType...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(chunk)))
+
+	return append(data, chunk...)
+}
+
+// box builds an ISO base media box.
+func box(boxType string, payload []byte) []byte {
+	data := binary.BigEndian.AppendUint32(nil, uint32(8 + len(payload)))
+
+	return append(append(data, boxType...), payload...)
+}
+
+func (suite *FLACTestSuite) TestModernImageFormats() {
+	lossy := riff("VP8 ", []byte{0x30, 0x01, 0x00, 0x9d, 0x01, 0x2a, 0x80, 0x02, 0xe0, 0x01, 0, 0})
+	lossless := riff("VP8L", binary.LittleEndian.AppendUint32([]byte{0x2f}, 99 | 49 << 14 | 1 << 28))
+	extended := riff("VP8X", []byte{0x10, 0, 0, 0, 0x7f, 0x07, 0, 0x37, 0x04, 0})
+	avif := append(box("ftyp", []byte("avif\x00\x00\x00\x00mif1miaf")), box("meta", append(
+		box("ispe", []byte{0, 0, 0, 0, 0, 0, 0x01, 0x00, 0, 0, 0, 0xc0}),
+		box("pixi", []byte{0, 0, 0, 0, 3, 10, 10, 10})...,
+	))...)
+
+	images := []struct {
+		data []byte
+		mimeType string
+		config imageConfig
+	}{
+		{lossy, "image/webp", imageConfig{640, 480, 24, 0}},
+		{lossless, "image/webp", imageConfig{100, 50, 32, 0}},
+		{extended, "image/webp", imageConfig{1920, 1080, 32, 0}},
+		{avif, "image/avif", imageConfig{256, 192, 30, 0}},
+	}
+
+	for _, expected := range images {
+		suite.assert.Equal(expected.mimeType, sniffMIMEType(expected.data))
+
+		block, err := NewPicture(FrontCover, expected.mimeType, "", expected.data)
+
+		suite.assert.NoError(err)
+		suite.assert.Equal(expected.config, imageConfig{block.Width, block.Height, block.ColourDepth, block.NumColours})
+
+		flac := New(suite.flac.StreamInfo.clone(nil).(*FLACMetadataBlockStreamInfo))
+		flac.AppendBlock(block)
+
+		findings, err := flac.ValidatePictures()
+
+		suite.assert.NoError(err)
+		suite.assert.Empty(findings)
+	}
+
+	_, err := NewPicture(FrontCover, "image/avif", "", box("ftyp", []byte("avif\x00\x00\x00\x00")))
+
+	suite.assert.Error(err)
+
+	_, err = NewPicture(FrontCover, "image/webp", "", riff("VP8 ", make([]byte, 10)))
+
+	suite.assert.Error(err)
+	suite.assert.Equal("image/jpeg", sniffMIMEType(suite.flac.FrontCover().Picture))
+}
@@ -0,0 +1,90 @@
+package flac
+
+import (
+	"fmt"
+	"strings"
+	"encoding/hex"
+	"encoding/json"
+)
+
+var pictureTypeIdentifiers = []string{
+	"Other",
+	"FileIcon",
+	"OtherFileIcon",
+	"FrontCover",
+	"BackCover",
+	"LeafletPage",
+	"Media",
+	"LeadArtist",
+	"Artist",
+	"Conductor",
+	"Band",
+	"Composer",
+	"Lyricist",
+	"RecordingLocation",
+	"DuringRecording",
+	"DuringPerformance",
+	"ScreenCapture",
+	"Fish",
+	"Illustration",
+	"BandLogo",
+	"PublisherLogo",
+}
+
+// MarshalText writes the block type as its name in the specification, such as VORBIS_COMMENT.
+func (blockType BlockType) MarshalText() ([]byte, error) {
+	if name, ok := blockTypeNames[blockType]; ok {
+		return []byte(name), nil
+	}
+
+	return []byte(fmt.Sprintf("UNKNOWN_%d", blockType)), nil
+}
+
+// MarshalText writes the picture type as the name of its constant, such as FrontCover.
+func (pictureType PictureType) MarshalText() ([]byte, error) {
+	if int(pictureType) < len(pictureTypeIdentifiers) {
+		return []byte(pictureTypeIdentifiers[pictureType]), nil
+	}
+
+	return []byte(fmt.Sprintf("Unknown%d", pictureType)), nil
+}
+
+// MarshalJSON writes the block with its MD5 signature as a hex string.
+func (block *FLACMetadataBlockStreamInfo) MarshalJSON() ([]byte, error) {
+	type plain FLACMetadataBlockStreamInfo
+
+	return json.Marshal(struct {
+		*plain
+		UnencodedMD5 string `json:"md5"`
+	}{(*plain)(block), hex.EncodeToString(block.UnencodedMD5)})
+}
+
+// MarshalJSON writes the block with its picture MD5 as a hex string, leaving it out when the data was not loaded.
+func (block *FLACMetadataBlockPicture) MarshalJSON() ([]byte, error) {
+	type plain FLACMetadataBlockPicture
+
+	return json.Marshal(struct {
+		*plain
+		PictureMD5 string `json:"pictureMD5,omitempty"`
+	}{(*plain)(block), hex.EncodeToString(block.PictureMD5)})
+}
+
+// MarshalJSON writes the block with NUL padding trimmed from the media catalog number.
+func (block *FLACMetadataBlockCueSheet) MarshalJSON() ([]byte, error) {
+	type plain FLACMetadataBlockCueSheet
+
+	trimmed := plain(*block)
+	trimmed.MediaCatalogNumber = strings.TrimRight(block.MediaCatalogNumber, "\x00")
+
+	return json.Marshal(&trimmed)
+}
+
+// MarshalJSON writes the track with NUL padding trimmed from the ISRC.
+func (track CueSheetTrack) MarshalJSON() ([]byte, error) {
+	type plain CueSheetTrack
+
+	trimmed := plain(track)
+	trimmed.ISRC = strings.TrimRight(track.ISRC, "\x00")
+
+	return json.Marshal(trimmed)
+}
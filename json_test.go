@@ -0,0 +1,45 @@
+package flac
+
+import (
+	"encoding/json"
+)
+
+func (suite *FLACTestSuite) TestMarshalJSON() {
+	data, err := json.Marshal(suite.flac)
+
+	suite.assert.NoError(err)
+
+	var decoded struct {
+		Marker string `json:"marker"`
+		AudioOffset int64 `json:"audioOffset"`
+		StreamInfo map[string]interface{} `json:"streamInfo"`
+		Blocks []map[string]interface{} `json:"blocks"`
+	}
+
+	suite.assert.NoError(json.Unmarshal(data, &decoded))
+	suite.assert.Equal("fLaC", decoded.Marker)
+	suite.assert.Equal(suite.flac.AudioOffset, decoded.AudioOffset)
+	suite.assert.Equal("STREAMINFO", decoded.StreamInfo["type"])
+	suite.assert.Equal(88200, decoded.StreamInfo["sampleRate"])
+	suite.assert.Equal(32, len(decoded.StreamInfo["md5"].(string)))
+	suite.assert.Equal(len(suite.flac.MetadataBlocks), len(decoded.Blocks))
+	suite.assert.Equal("SEEKTABLE", decoded.Blocks[0]["type"])
+	suite.assert.Equal("VORBIS_COMMENT", decoded.Blocks[2]["type"])
+	suite.assert.Equal([]interface{}{map[string]interface{}{"key": "example", "value": "fish"}}, decoded.Blocks[2]["comments"])
+
+	picture := decoded.Blocks[3]
+
+	suite.assert.Equal("PICTURE", picture["type"])
+	suite.assert.Equal("FrontCover", picture["pictureType"])
+	suite.assert.Equal("c6f3cec420be726d74ca3ccfb7461f65", picture["pictureMD5"])
+	_, ok := picture["picture"]
+
+	suite.assert.False(ok)
+
+	cueSheet := decoded.Blocks[4]
+
+	suite.assert.Equal("", cueSheet["mediaCatalogNumber"])
+	suite.assert.Equal("", cueSheet["tracks"].([]interface{})[0].(map[string]interface{})["isrc"])
+	suite.assert.Equal(false, cueSheet["isCD"])
+	suite.assert.NotContains(string(data), `\u0000`)
+}
@@ -0,0 +1,72 @@
+package flac
+
+import (
+	"time"
+	"expvar"
+	"sync/atomic"
+)
+
+// Metrics receives counters and timings from parse operations so long-running scans can be monitored.
+// Streams parsed by ParseReader are reported with an empty path. Implementations must be safe for concurrent use.
+type Metrics interface {
+	FileParsed(path string, elapsed time.Duration)
+	BytesRead(n int64)
+	ParseError(path string, err error)
+}
+
+var defaultMetrics atomic.Pointer[Metrics]
+
+// SetMetrics installs the Metrics implementation used by parses not given WithMetrics. Passing nil disables
+// reporting. It is safe to call while other goroutines are parsing.
+func SetMetrics(m Metrics) {
+	if m == nil {
+		defaultMetrics.Store(nil)
+
+		return
+	}
+
+	defaultMetrics.Store(&m)
+}
+
+// ExpvarMetrics is a Metrics implementation publishing its counters through expvar.
+type ExpvarMetrics struct {
+	FilesParsed *expvar.Int
+	BytesTotal *expvar.Int
+	ParseErrors *expvar.Int
+	ParseNanoseconds *expvar.Int
+}
+
+// NewExpvarMetrics creates an ExpvarMetrics publishing its counters in an expvar map with the given name.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		FilesParsed: new(expvar.Int),
+		BytesTotal: new(expvar.Int),
+		ParseErrors: new(expvar.Int),
+		ParseNanoseconds: new(expvar.Int),
+	}
+
+	vars := expvar.NewMap(name)
+
+	vars.Set("files_parsed", m.FilesParsed)
+	vars.Set("bytes_read", m.BytesTotal)
+	vars.Set("parse_errors", m.ParseErrors)
+	vars.Set("parse_nanoseconds", m.ParseNanoseconds)
+
+	return m
+}
+
+// FileParsed implements Metrics.
+func (m *ExpvarMetrics) FileParsed(path string, elapsed time.Duration) {
+	m.FilesParsed.Add(1)
+	m.ParseNanoseconds.Add(int64(elapsed))
+}
+
+// BytesRead implements Metrics.
+func (m *ExpvarMetrics) BytesRead(n int64) {
+	m.BytesTotal.Add(n)
+}
+
+// ParseError implements Metrics.
+func (m *ExpvarMetrics) ParseError(path string, err error) {
+	m.ParseErrors.Add(1)
+}
@@ -0,0 +1,68 @@
+package flac
+
+import (
+	"os"
+	"time"
+	"sync"
+	"bytes"
+)
+
+type recordingMetrics struct {
+	sync.Mutex
+	files int
+	bytes int64
+	errors int
+}
+
+func (m *recordingMetrics) FileParsed(path string, elapsed time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	m.files++
+}
+
+func (m *recordingMetrics) BytesRead(n int64) {
+	m.Lock()
+	defer m.Unlock()
+	m.bytes += n
+}
+
+func (m *recordingMetrics) ParseError(path string, err error) {
+	m.Lock()
+	defer m.Unlock()
+	m.errors++
+}
+
+func (suite *FLACTestSuite) TestMetrics() {
+	m := &recordingMetrics{}
+
+	SetMetrics(m)
+	defer SetMetrics(nil)
+
+	_, err := Parse("sample.flac")
+
+	suite.assert.NoError(err)
+
+	_, err = Parse("flac_test.go")
+
+	suite.assert.Error(err)
+	suite.assert.Equal(1, m.files)
+	suite.assert.Equal(1, m.errors)
+	suite.assert.True(m.bytes > 1661438)
+
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	own := &recordingMetrics{}
+	flac, err := ParseReader(bytes.NewReader(data), WithMetrics(own))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(1, own.files)
+	suite.assert.Equal(flac.AudioOffset, own.bytes)
+	suite.assert.Equal(1, m.files)
+
+	_, err = ParseReader(bytes.NewReader(data))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(2, m.files)
+}
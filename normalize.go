@@ -0,0 +1,110 @@
+package flac
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizationForm is a Unicode normalization form tag values can be rewritten in.
+type NormalizationForm int
+
+// Enum indicating the supported Unicode normalization forms.
+const (
+	NFC NormalizationForm = iota // canonical composition
+	NFD // canonical decomposition
+	NFKC // compatibility composition
+	NFKD // compatibility decomposition
+)
+
+// normForm returns the norm.Form implementing the normalization form.
+func (form NormalizationForm) normForm() norm.Form {
+	switch form {
+		case NFD:
+			return norm.NFD
+
+		case NFKC:
+			return norm.NFKC
+
+		case NFKD:
+			return norm.NFKD
+	}
+
+	return norm.NFC
+}
+
+// NormalizeTags rewrites every comment value in the Unicode normalization form, usually NFC, and returns
+// how many values changed. Field names are ASCII and are left as they are.
+func (block *FLACMetadataBlockVorbisComment) NormalizeTags(form NormalizationForm) (changed int) {
+	normForm := form.normForm()
+
+	for index, comment := range block.Comments {
+		if normForm.IsNormalString(comment.Value) {
+			continue
+		}
+
+		block.Comments[index].Value = normForm.String(comment.Value)
+		changed++
+	}
+
+	return
+}
+
+// Normalization reports whether the comment values include composed text, which is in NFC and changes in NFD,
+// and decomposed text, which is not in NFC. Values that read the same in both forms, such as ASCII, are neither.
+func (block *FLACMetadataBlockVorbisComment) Normalization() (composed bool, decomposed bool) {
+	for _, comment := range block.Comments {
+		switch {
+			case !norm.NFC.IsNormalString(comment.Value):
+				decomposed = true
+
+			case !norm.NFD.IsNormalString(comment.Value):
+				composed = true
+		}
+	}
+
+	return
+}
+
+// NormalizationStats groups file paths by the normalization of their tag values, as reported by Normalization.
+// A file mixing both is in both groups, and a file with neither is in none.
+type NormalizationStats struct {
+	Composed []string
+	Decomposed []string
+}
+
+// Mixed reports whether the files use both composed and decomposed tag values, so that matching values
+// across them needs normalizing first.
+func (stats NormalizationStats) Mixed() bool {
+	return len(stats.Composed) > 0 && len(stats.Decomposed) > 0
+}
+
+// CollectNormalizationStats parses each path and groups it by the normalization of its tag values, reporting
+// per-file outcomes in result.
+func CollectNormalizationStats(paths []string) (stats NormalizationStats, result *BatchResult) {
+	result = &BatchResult{}
+
+	for _, path := range paths {
+		flac, err := Parse(path, WithSkipPictures())
+
+		result.add(path, err)
+
+		if err != nil {
+			continue
+		}
+
+		if comments := flac.VorbisComment(); comments != nil {
+			composed, decomposed := comments.Normalization()
+
+			if composed {
+				stats.Composed = append(stats.Composed, path)
+			}
+
+			if decomposed {
+				stats.Decomposed = append(stats.Decomposed, path)
+			}
+		}
+
+		flac.Close()
+	}
+
+	return
+}
@@ -0,0 +1,64 @@
+package flac
+
+func (suite *FLACTestSuite) TestNormalizeTags() {
+	comments := NewVorbisComment("go-flac")
+
+	comments.AddTag(TitleTag, "Cafe\u0301")
+	comments.AddTag(ArtistTag, "Bj\u00f6rk")
+	comments.AddTag(AlbumTag, "Plain")
+
+	composed, decomposed := comments.Normalization()
+
+	suite.assert.True(composed)
+	suite.assert.True(decomposed)
+	suite.assert.Equal(1, comments.NormalizeTags(NFC))
+	suite.assert.Equal("Caf\u00e9", comments.Title())
+
+	composed, decomposed = comments.Normalization()
+
+	suite.assert.True(composed)
+	suite.assert.False(decomposed)
+	suite.assert.Equal(2, comments.NormalizeTags(NFD))
+	suite.assert.Equal("Bjo\u0308rk", comments.Artist())
+	suite.assert.Equal(0, comments.NormalizeTags(NFD))
+
+	comments.SetTag(TitleTag, "\ufb01ne")
+
+	suite.assert.Equal(0, comments.NormalizeTags(NFD))
+	suite.assert.Equal(1, comments.NormalizeTags(NFKD))
+	suite.assert.Equal("fine", comments.Title())
+}
+
+func (suite *FLACTestSuite) TestTagNormalizationOptions() {
+	path, _ := suite.copySample()
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	flac.VorbisComment().SetTitle("Cafe\u0301")
+
+	suite.assert.NoError(flac.Save())
+
+	stats, result := CollectNormalizationStats([]string{path, "sample.flac"})
+
+	suite.assert.NoError(result.Err())
+	suite.assert.Equal([]string{path}, stats.Decomposed)
+	suite.assert.False(stats.Mixed())
+
+	normalized, err := Parse(path, WithTagNormalization(NFC))
+
+	suite.assert.NoError(err)
+
+	defer normalized.Close()
+
+	suite.assert.Equal("Caf\u00e9", normalized.VorbisComment().Title())
+
+	suite.assert.NoError(flac.Save(WithNormalizeTags(NFC)))
+
+	stats, _ = CollectNormalizationStats([]string{path})
+
+	suite.assert.Equal([]string{path}, stats.Composed)
+	suite.assert.Empty(stats.Decomposed)
+}
@@ -0,0 +1,319 @@
+package flac
+
+import (
+	"io"
+)
+
+// ParseOption configures how metadata is parsed.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	skipPictures bool
+	skipPadding bool
+	lazy bool
+	metadataOnly bool
+	rawData bool
+	lenient bool
+	blockFilter func(BlockType) bool
+	normalization *NormalizationForm
+	maxPictureSize uint32
+	maxBlockCount int
+	reporter Metrics
+}
+
+func newParseOptions(options []ParseOption) *parseOptions {
+	parsed := &parseOptions{}
+
+	for _, option := range options {
+		option(parsed)
+	}
+
+	return parsed
+}
+
+// skip reports whether blocks of the given type should be replaced by a FLACMetadataBlockSkipped stub.
+func (options *parseOptions) skip(blockType BlockType) bool {
+	if options == nil {
+		return false
+	}
+
+	switch {
+		case options.skipPictures && blockType == Picture:
+			return true
+
+		case options.skipPadding && blockType == Padding:
+			return true
+
+		case options.blockFilter != nil && !options.blockFilter(blockType):
+			return true
+	}
+
+	return false
+}
+
+// lazyPictures reports whether picture data should be left unread until requested.
+func (options *parseOptions) lazyPictures() bool {
+	return options != nil && options.lazy
+}
+
+// WithLazyPictures parses PICTURE block fields but leaves the image data unread, recording its position
+// in PictureOffset so it can be fetched later with Load or Open.
+func WithLazyPictures() ParseOption {
+	return func(options *parseOptions) {
+		options.lazy = true
+	}
+}
+
+// metrics returns the Metrics to report the parse to, falling back to the one installed with SetMetrics.
+func (options *parseOptions) metrics() Metrics {
+	if options != nil && options.reporter != nil {
+		return options.reporter
+	}
+
+	if m := defaultMetrics.Load(); m != nil {
+		return *m
+	}
+
+	return nil
+}
+
+// WithMetrics reports the parse to m instead of the Metrics installed with SetMetrics.
+func WithMetrics(m Metrics) ParseOption {
+	return func(options *parseOptions) {
+		options.reporter = m
+	}
+}
+
+// keepRawData reports whether each block's original data should be retained.
+func (options *parseOptions) keepRawData() bool {
+	return options != nil && options.rawData
+}
+
+// WithRawData retains the original data of every block in RawData so it can be reproduced exactly.
+// Skipped and lazily loaded blocks are still read in full to capture their data.
+func WithRawData() ParseOption {
+	return func(options *parseOptions) {
+		options.rawData = true
+	}
+}
+
+// isLenient reports whether recoverable problems should be collected as warnings rather than failing the parse.
+func (options *parseOptions) isLenient() bool {
+	return options != nil && options.lenient
+}
+
+// WithLenient continues past recoverable problems, such as vorbis comments without a '=' separator,
+// unexpected trailing bytes in a block or a seek table of odd size, and records them in FLAC.Warnings.
+// By default parsing fails on the first such problem.
+func WithLenient() ParseOption {
+	return func(options *parseOptions) {
+		options.lenient = true
+	}
+}
+
+// normalizeTags reports whether vorbis comment values should be normalized as they are parsed.
+func (options *parseOptions) normalizeTags() bool {
+	return options != nil && options.normalization != nil
+}
+
+// WithTagNormalization normalizes vorbis comment values to form as they are parsed, so tags read from files
+// written by different tools compare equal.
+func WithTagNormalization(form NormalizationForm) ParseOption {
+	return func(options *parseOptions) {
+		options.normalization = &form
+	}
+}
+
+// pictureTooLarge reports whether a picture block of length bytes exceeds the maximum picture size.
+func (options *parseOptions) pictureTooLarge(length uint32) bool {
+	return options != nil && options.maxPictureSize > 0 && length > options.maxPictureSize
+}
+
+// blockLimitReached reports whether count metadata blocks, not counting STREAMINFO, is the most to parse.
+func (options *parseOptions) blockLimitReached(count int) bool {
+	return options != nil && options.maxBlockCount > 0 && count >= options.maxBlockCount
+}
+
+// WithMaxPictureSize skips the contents of PICTURE blocks holding more than size bytes, recording a warning
+// in FLAC.Warnings, so a broken or malicious file cannot make the parser allocate for them. Skipped pictures
+// are still written back from the source file when saving.
+func WithMaxPictureSize(size uint32) ParseOption {
+	return func(options *parseOptions) {
+		options.maxPictureSize = size
+	}
+}
+
+// WithMaxBlockCount parses at most count metadata blocks after STREAMINFO. The headers of any further blocks
+// are read past without keeping them, and a warning is recorded in FLAC.Warnings. Metadata cut short this way
+// cannot be written, as the blocks left out would be lost.
+func WithMaxBlockCount(count int) ParseOption {
+	return func(options *parseOptions) {
+		options.maxBlockCount = count
+	}
+}
+
+// WithSkipPictures skips the contents of PICTURE blocks.
+func WithSkipPictures() ParseOption {
+	return func(options *parseOptions) {
+		options.skipPictures = true
+	}
+}
+
+// WithSkipPadding skips the contents of PADDING blocks.
+func WithSkipPadding() ParseOption {
+	return func(options *parseOptions) {
+		options.skipPadding = true
+	}
+}
+
+// WithBlockFilter parses only blocks for which filter returns true; the rest are skipped.
+// STREAMINFO is always parsed.
+func WithBlockFilter(filter func(BlockType) bool) ParseOption {
+	return func(options *parseOptions) {
+		options.blockFilter = filter
+	}
+}
+
+// WithBlockTypes parses only blocks of the given types; the rest are skipped. STREAMINFO is always parsed.
+func WithBlockTypes(types ...BlockType) ParseOption {
+	return WithBlockFilter(func(blockType BlockType) bool {
+		for _, wanted := range types {
+			if blockType == wanted {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// SaveOption configures how Save and SaveAs write a file.
+type SaveOption func(*saveOptions)
+
+type saveOptions struct {
+	preserveAttributes bool
+	targetPadding int64
+	dontUsePadding bool
+	inPlaceOnly bool
+	backupSuffix string
+	report *SaveReport
+	verifyAudio bool
+	sanitizeTags bool
+	normalization *NormalizationForm
+	trailing int64
+	withoutFrameGap bool
+}
+
+func newSaveOptions(options []SaveOption) *saveOptions {
+	parsed := &saveOptions{
+		targetPadding: -1,
+	}
+
+	for _, option := range options {
+		option(parsed)
+	}
+
+	return parsed
+}
+
+// WithPreserveAttributes keeps the mode, owner and modification time of the file being replaced, or of the
+// source file when writing a new one. Ownership is only preserved on Unix and needs suitable privileges.
+func WithPreserveAttributes() SaveOption {
+	return func(options *saveOptions) {
+		options.preserveAttributes = true
+	}
+}
+
+// WithTargetPadding merges the padding blocks into a single padding block of length bytes at the end of the
+// metadata whenever the whole file is written.
+func WithTargetPadding(length uint32) SaveOption {
+	return func(options *saveOptions) {
+		options.targetPadding = int64(length)
+	}
+}
+
+// WithoutPaddingReuse writes the padding blocks as they stand, as metaflac does with --dont-use-padding.
+// Save then only writes in place when the metadata keeps its size, and otherwise rewrites the file.
+func WithoutPaddingReuse() SaveOption {
+	return func(options *saveOptions) {
+		options.dontUsePadding = true
+	}
+}
+
+// WithInPlaceOnly makes Save return ErrInsufficientPadding rather than rewrite the file when the metadata does not fit.
+func WithInPlaceOnly() SaveOption {
+	return func(options *saveOptions) {
+		options.inPlaceOnly = true
+	}
+}
+
+// WithBackup copies the file being replaced to a sibling path with suffix appended, such as ".bak", before it
+// is modified. An existing backup is overwritten.
+func WithBackup(suffix string) SaveOption {
+	return func(options *saveOptions) {
+		options.backupSuffix = suffix
+	}
+}
+
+// WithDryRun makes Save leave the file untouched and instead fill report with what it would have done.
+func WithDryRun(report *SaveReport) SaveOption {
+	return func(options *saveOptions) {
+		options.report = report
+	}
+}
+
+// WithoutFrameGap leaves out any bytes between the last metadata block and the first audio frame, running
+// DetectFrameGap to find them. Save then rewrites the whole file when there is a gap. Without it a gap is
+// copied through unchanged.
+func WithoutFrameGap() SaveOption {
+	return func(options *saveOptions) {
+		options.withoutFrameGap = true
+	}
+}
+
+// WithVerifyAudio reads back the audio frames after the whole file is written and checks them against
+// those copied from the original before the file is replaced.
+func WithVerifyAudio() SaveOption {
+	return func(options *saveOptions) {
+		options.verifyAudio = true
+	}
+}
+
+// WithSanitizeTags runs SanitizeTags on the vorbis comments before they are written, rather than
+// refusing to write comments that fail ValidateTags.
+func WithSanitizeTags() SaveOption {
+	return func(options *saveOptions) {
+		options.sanitizeTags = true
+	}
+}
+
+// WithNormalizeTags normalizes vorbis comment values to form, usually NFC, before they are written.
+func WithNormalizeTags(form NormalizationForm) SaveOption {
+	return func(options *saveOptions) {
+		options.normalization = &form
+	}
+}
+
+// FLACMetadataBlockSkipped stands in for a block whose contents were skipped during parsing.
+// The embedded header records where the skipped data lies.
+type FLACMetadataBlockSkipped struct {
+	FLACMetadataBlock
+}
+
+func (block *FLACMetadataBlockSkipped) parse(reader io.Reader) (err error) {
+	length := int64(block.FLACMetadataBlock.DataLength)
+
+	if counter, ok := reader.(*countingReader); ok {
+		err = counter.skip(length)
+
+		return
+	}
+
+	_, err = io.CopyN(io.Discard, reader, length)
+
+	return
+}
+
+func (block *FLACMetadataBlockSkipped) isLast() bool {
+	return block.FLACMetadataBlock.Last
+}
@@ -0,0 +1,167 @@
+package flac
+
+import (
+	"io"
+	"os"
+	"bytes"
+	"path/filepath"
+)
+
+func (suite *FLACTestSuite) TestParseOptions() {
+	flac, err := Parse("sample.flac", WithSkipPictures(), WithSkipPadding())
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(len(suite.flac.MetadataBlocks), len(flac.MetadataBlocks))
+	suite.assert.Nil(flac.FrontCover())
+	suite.assert.Nil(flac.Gap)
+
+	skipped := 0
+
+	for _, iBlock := range flac.MetadataBlocks {
+		block, ok := iBlock.(*FLACMetadataBlockSkipped)
+
+		if !ok {
+			continue
+		}
+
+		skipped++
+
+		suite.assert.True(block.FLACMetadataBlock.Type == Picture || block.FLACMetadataBlock.Type == Padding)
+		suite.assert.True(block.Offset > 0)
+
+		if block.FLACMetadataBlock.Type == Picture {
+			suite.assert.Equal(1661438, block.FLACMetadataBlock.DataLength)
+		}
+	}
+
+	suite.assert.Equal(2, skipped)
+
+	flac, err = Parse("sample.flac", WithBlockTypes(VorbisComment))
+
+	suite.assert.NoError(err)
+	suite.assert.NotNil(flac.StreamInfo)
+	suite.assert.NotNil(flac.VorbisComment())
+
+	for _, iBlock := range flac.MetadataBlocks {
+		if iBlock.header().Type != VorbisComment {
+			suite.assert.IsType(&FLACMetadataBlockSkipped{}, iBlock)
+		}
+	}
+
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	flac, err = ParseReader(struct{ io.Reader }{bytes.NewReader(data)}, WithSkipPictures())
+
+	suite.assert.NoError(err)
+	suite.assert.Nil(flac.FrontCover())
+}
+
+func (suite *FLACTestSuite) TestParseRawData() {
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	flac, err := Parse("sample.flac", WithRawData(), WithLazyPictures())
+
+	suite.assert.NoError(err)
+
+	blocks := append([]IFLACMetadataBlock{flac.StreamInfo}, flac.MetadataBlocks...)
+
+	for _, iBlock := range blocks {
+		header := iBlock.header()
+
+		suite.assert.Equal(data[header.Offset:header.Offset + int64(header.DataLength)], header.RawData)
+	}
+
+	cover := flac.FrontCover()
+
+	suite.assert.False(cover.Loaded())
+	suite.assert.Equal(suite.flac.FrontCover().Picture, data[cover.PictureOffset:cover.PictureOffset + int64(cover.PictureLength)])
+	suite.assert.Nil(suite.flac.StreamInfo.FLACMetadataBlock.RawData)
+}
+
+func (suite *FLACTestSuite) TestParseLenient() {
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	malformed := bytes.Clone(data)
+	malformed[bytes.Index(data, []byte("example=fish")) + 7] = '_'
+
+	_, err = ParseReader(bytes.NewReader(malformed))
+
+	suite.assert.ErrorIs(err, ErrMalformedVorbisComment)
+
+	flac, err := ParseReader(bytes.NewReader(malformed), WithLenient())
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(1, len(flac.Warnings))
+	suite.assert.Equal(VorbisComment, flac.Warnings[0].Type)
+	suite.assert.Equal(0, len(flac.VorbisComment().Comments))
+
+	// Grow the seek table by two bytes, leaving a partial seek point.
+	oddSeekTable := append(bytes.Clone(data[:4 + 38 + 18]), 0, 0)
+	oddSeekTable = append(oddSeekTable, data[4 + 38 + 18:]...)
+	oddSeekTable[4 + 34 + 7] = 20
+
+	_, err = ParseReader(bytes.NewReader(oddSeekTable))
+
+	suite.assert.Error(err)
+
+	flac, err = ParseReader(bytes.NewReader(oddSeekTable), WithLenient())
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(1, len(flac.Warnings))
+	suite.assert.Equal(SeekTable, flac.Warnings[0].Type)
+	suite.assert.Equal(int64(4 + 38), flac.Warnings[0].Offset)
+	suite.assert.Equal(1, len(flac.SeekTable().SeekPoints))
+	suite.assert.Equal("ATCH", flac.Applications()[0].AppID)
+	suite.assert.Equal(0, len(suite.flac.Warnings))
+}
+
+func (suite *FLACTestSuite) TestParseLimits() {
+	path, _ := suite.copySample()
+	flac, err := Parse(path, WithMaxPictureSize(1024))
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	suite.assert.IsType(&FLACMetadataBlockSkipped{}, flac.MetadataBlocks[3])
+	suite.assert.Nil(flac.FrontCover())
+	suite.assert.Equal(1, len(flac.Warnings))
+	suite.assert.Equal(Picture, flac.Warnings[0].Type)
+	suite.assert.Equal(suite.flac.FrontCover().HeaderOffset, flac.Warnings[0].Offset)
+	suite.assert.Contains(flac.Warnings[0].Message, "exceeds the maximum picture size")
+
+	flac.VorbisComment().SetTitle("Song")
+
+	suite.assert.NoError(flac.Save())
+
+	saved, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer saved.Close()
+
+	suite.assert.Equal(suite.flac.FrontCover().Picture, saved.FrontCover().Picture)
+
+	limited, err := Parse("sample.flac", WithMaxBlockCount(2))
+
+	suite.assert.NoError(err)
+
+	defer limited.Close()
+
+	suite.assert.Equal(2, len(limited.MetadataBlocks))
+	suite.assert.Equal(suite.flac.AudioOffset, limited.AudioOffset)
+	suite.assert.Equal("4 metadata blocks beyond the maximum block count were left out", limited.Warnings[0].Message)
+	suite.assert.Equal(VorbisComment, limited.Warnings[0].Type)
+	suite.assert.Equal(suite.flac.VorbisComment().HeaderOffset, limited.Warnings[0].Offset)
+
+	_, err = limited.WriteMetadata(io.Discard)
+
+	suite.assert.ErrorIs(err, ErrIncompleteMetadata)
+	suite.assert.ErrorIs(limited.SaveAs(filepath.Join(suite.T().TempDir(), "out.flac")), ErrIncompleteMetadata)
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package flac
+
+import (
+	"io/fs"
+)
+
+// preserveOwner is a no-op on platforms without Unix ownership.
+func preserveOwner(path string, info fs.FileInfo) error {
+	return nil
+}
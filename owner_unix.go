@@ -0,0 +1,20 @@
+//go:build unix
+
+package flac
+
+import (
+	"os"
+	"io/fs"
+	"syscall"
+)
+
+// preserveOwner gives the file at path the owner and group recorded in info.
+func preserveOwner(path string, info fs.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+
+	if !ok || (int(stat.Uid) == os.Getuid() && int(stat.Gid) == os.Getgid()) {
+		return nil
+	}
+
+	return os.Chown(path, int(stat.Uid), int(stat.Gid))
+}
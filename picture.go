@@ -0,0 +1,595 @@
+package flac
+
+import (
+	"io"
+	"os"
+	"fmt"
+	"bytes"
+	"errors"
+	"image"
+	"mime"
+	"slices"
+	"strings"
+	"path/filepath"
+	"image/color"
+	_ "image/gif"
+	_ "image/png"
+	_ "image/jpeg"
+)
+
+// MaxImagePixels is the largest picture, in pixels, that Image will decode.
+var MaxImagePixels = 64 * 1024 * 1024
+
+// Image decodes the picture data using the registered image decoders.
+func (block *FLACMetadataBlockPicture) Image() (img image.Image, err error) {
+	reader := block.Open()
+	config, _, err := image.DecodeConfig(reader)
+
+	closeReader(reader)
+
+	if err != nil {
+		return
+	}
+
+	if config.Width * config.Height > MaxImagePixels {
+		err = errors.New("picture dimensions exceed MaxImagePixels")
+
+		return
+	}
+
+	reader = block.Open()
+	img, _, err = image.Decode(reader)
+
+	closeReader(reader)
+
+	return
+}
+
+// Loaded reports whether the picture data is held in memory.
+func (block *FLACMetadataBlockPicture) Loaded() bool {
+	return block.Picture != nil || block.PictureLength == 0
+}
+
+// Load reads picture data left unread by WithLazyPictures from r, which must address the same stream
+// the metadata was parsed from, and fills in Picture and PictureMD5.
+func (block *FLACMetadataBlockPicture) Load(r io.ReaderAt) (err error) {
+	if block.Loaded() {
+		return
+	}
+
+	picture := make([]byte, block.PictureLength)
+	_, err = r.ReadAt(picture, block.PictureOffset)
+
+	if err != nil {
+		return
+	}
+
+	block.Picture = picture
+
+	if !block.IsURL() {
+		block.PictureMD5 = pictureMD5(picture)
+	}
+
+	return
+}
+
+// Open returns a reader over the picture data. Unloaded data is streamed from the file the metadata
+// was parsed from without being kept in memory. If the FLAC has been closed the file is reopened, and
+// closed again once the data has been read or when the reader is closed through io.Closer.
+func (block *FLACMetadataBlockPicture) Open() io.Reader {
+	if block.Loaded() {
+		return bytes.NewReader(block.Picture)
+	}
+
+	return &pictureReader{
+		block: block,
+	}
+}
+
+// Copy writes the picture data to w, streaming data left unread by WithLazyPictures from the source file
+// in chunks rather than loading it, and returns the number of bytes written.
+func (block *FLACMetadataBlockPicture) Copy(w io.Writer) (n int64, err error) {
+	reader := block.Open()
+	n, err = io.Copy(w, reader)
+
+	closeReader(reader)
+
+	return
+}
+
+// pictureReader streams unloaded picture data, opening the source file on first use and closing it
+// once the data is exhausted.
+type pictureReader struct {
+	block *FLACMetadataBlockPicture
+	file io.Closer
+	reader io.Reader
+	err error
+}
+
+func (reader *pictureReader) Read(data []byte) (n int, err error) {
+	if reader.err != nil {
+		return 0, reader.err
+	}
+
+	if reader.reader == nil {
+		reader.err = reader.open()
+
+		if reader.err != nil {
+			return 0, reader.err
+		}
+	}
+
+	n, err = reader.reader.Read(data)
+
+	if err != nil {
+		reader.err = err
+
+		if reader.file != nil {
+			reader.file.Close()
+		}
+	}
+
+	return
+}
+
+// Close releases the source file if reading stopped before the end of the data.
+func (reader *pictureReader) Close() (err error) {
+	if reader.err == nil && reader.file != nil {
+		err = reader.file.Close()
+	}
+
+	reader.err = io.ErrClosedPipe
+
+	return
+}
+
+// closeReader closes readers returned by Open that hold a file open.
+func closeReader(reader io.Reader) {
+	if closer, ok := reader.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+func (reader *pictureReader) open() (err error) {
+	flac := reader.block.FLACMetadataBlock.FLAC
+
+	if flac == nil || flac.path == "" {
+		err = errors.New("picture data was not loaded and its source is unknown")
+
+		return
+	}
+
+	if readerAt, ok := flac.file.(io.ReaderAt); ok {
+		reader.reader = io.NewSectionReader(readerAt, reader.block.PictureOffset, int64(reader.block.PictureLength))
+
+		return
+	}
+
+	file, err := flac.open()
+
+	if err != nil {
+		return
+	}
+
+	readerAt, ok := file.(io.ReaderAt)
+
+	if !ok {
+		file.Close()
+		err = errors.New("picture source does not support random access")
+
+		return
+	}
+
+	reader.file = file
+	reader.reader = io.NewSectionReader(readerAt, reader.block.PictureOffset, int64(reader.block.PictureLength))
+
+	return
+}
+
+// FrontCover returns the first picture block with the FrontCover type, or nil if there is none.
+func (flac *FLAC) FrontCover() *FLACMetadataBlockPicture {
+	for _, block := range flac.Pictures() {
+		if block.Type == FrontCover {
+			return block
+		}
+	}
+
+	return nil
+}
+
+// RemovePictures removes every picture block of the given type and returns how many were removed.
+func (flac *FLAC) RemovePictures(pictureType PictureType) (removed int) {
+	count := len(flac.MetadataBlocks)
+	flac.MetadataBlocks = slices.DeleteFunc(flac.MetadataBlocks, func(iBlock IFLACMetadataBlock) bool {
+		block, ok := iBlock.(*FLACMetadataBlockPicture)
+
+		return ok && block.Type == pictureType
+	})
+	removed = count - len(flac.MetadataBlocks)
+
+	flac.updateLastFlags()
+
+	return
+}
+
+// SetFrontCover replaces the front cover with img, keeping the position and description of the first
+// existing front cover and removing any others, or appends a new front cover if there is none. An empty
+// mimeType is sniffed from the data. The image is passed through the ImageTransformer installed with
+// SetImageTransformer. Save reuses padding for the new picture where it fits.
+func (flac *FLAC) SetFrontCover(img []byte, mimeType string) (err error) {
+	if mimeType == "" {
+		mimeType = sniffMIMEType(img)
+	}
+
+	description := ""
+	position := slices.IndexFunc(flac.MetadataBlocks, func(iBlock IFLACMetadataBlock) bool {
+		block, ok := iBlock.(*FLACMetadataBlockPicture)
+
+		return ok && block.Type == FrontCover
+	})
+
+	if position >= 0 {
+		description = flac.MetadataBlocks[position].(*FLACMetadataBlockPicture).Description
+	}
+
+	block, err := flac.embedPicture(FrontCover, mimeType, description, img)
+
+	if err != nil {
+		return
+	}
+
+	if position < 0 {
+		return flac.AppendBlock(block)
+	}
+
+	block.FLAC = flac
+	flac.MetadataBlocks[position] = block
+	flac.MetadataBlocks = slices.DeleteFunc(flac.MetadataBlocks, func(iBlock IFLACMetadataBlock) bool {
+		picture, ok := iBlock.(*FLACMetadataBlockPicture)
+
+		return ok && picture.Type == FrontCover && picture != block
+	})
+
+	flac.updateLastFlags()
+
+	return
+}
+
+// FrontCoverImage decodes the front cover picture.
+func (flac *FLAC) FrontCoverImage() (img image.Image, err error) {
+	block := flac.FrontCover()
+
+	if block == nil {
+		err = errors.New("no front cover picture")
+
+		return
+	}
+
+	img, err = block.Image()
+
+	return
+}
+
+// PictureMismatch describes a PICTURE block field that disagrees with the embedded image.
+type PictureMismatch struct {
+	Field string
+	Declared uint32
+	Actual uint32
+}
+
+// colourDepth returns the bits per pixel and palette size FLAC records for an image colour model.
+func colourDepth(model color.Model) (depth uint32, colours uint32) {
+	if palette, ok := model.(color.Palette); ok {
+		return 24, uint32(len(palette))
+	}
+
+	switch model {
+		case color.GrayModel:
+			return 8, 0
+
+		case color.Gray16Model:
+			return 16, 0
+
+		case color.YCbCrModel:
+			return 24, 0
+
+		case color.RGBA64Model, color.NRGBA64Model:
+			return 64, 0
+	}
+
+	return 32, 0
+}
+
+// VerifyDimensions reads the image header and compares it to the declared Width, Height, ColourDepth and NumColours.
+func (block *FLACMetadataBlockPicture) VerifyDimensions() (mismatches []PictureMismatch, err error) {
+	config, err := block.imageConfig()
+
+	if err != nil {
+		return
+	}
+
+	fields := []PictureMismatch{
+		{"Width", block.Width, config.width},
+		{"Height", block.Height, config.height},
+		{"ColourDepth", block.ColourDepth, config.depth},
+		{"NumColours", block.NumColours, config.colours},
+	}
+
+	for _, field := range fields {
+		if field.Declared != field.Actual {
+			mismatches = append(mismatches, field)
+		}
+	}
+
+	return
+}
+
+// FixDimensions sets Width, Height, ColourDepth and NumColours from the embedded image. WebP and AVIF images
+// are read without a registered decoder.
+func (block *FLACMetadataBlockPicture) FixDimensions() (err error) {
+	config, err := block.imageConfig()
+
+	if err != nil {
+		return
+	}
+
+	block.Width = config.width
+	block.Height = config.height
+	block.ColourDepth = config.depth
+	block.NumColours = config.colours
+
+	return
+}
+
+// FixPictureDimensions corrects the Width, Height, ColourDepth and NumColours of every picture that disagrees with
+// its image, as FixDimensions does, and returns how many were changed. Pictures that link to a URL are left alone.
+// It stops at the first picture whose image cannot be read.
+func (flac *FLAC) FixPictureDimensions() (fixed int, err error) {
+	for _, block := range flac.Pictures() {
+		if block.IsURL() {
+			continue
+		}
+
+		var mismatches []PictureMismatch
+
+		mismatches, err = block.VerifyDimensions()
+
+		if err != nil {
+			return
+		}
+
+		if len(mismatches) > 0 {
+			err = block.FixDimensions()
+
+			if err != nil {
+				return
+			}
+
+			fixed++
+		}
+	}
+
+	return
+}
+
+// AddPicture appends a picture block holding data, after passing it through the ImageTransformer installed
+// with SetImageTransformer. An empty mimeType is sniffed from the data, and the dimensions and colour depth are
+// read from the image, which must be WebP, AVIF or in a format with a registered decoder.
+func (flac *FLAC) AddPicture(pictureType PictureType, mimeType string, description string, data []byte) (block *FLACMetadataBlockPicture, err error) {
+	if mimeType == "" {
+		mimeType = sniffMIMEType(data)
+	}
+
+	block, err = flac.embedPicture(pictureType, mimeType, description, data)
+
+	if err != nil {
+		return
+	}
+
+	err = flac.AppendBlock(block)
+
+	if err != nil {
+		block = nil
+	}
+
+	return
+}
+
+// AddPictureFromFile appends a picture block holding the image at path as AddPicture does, with the MIME type
+// sniffed from the data.
+func (flac *FLAC) AddPictureFromFile(path string, pictureType PictureType, description string) (block *FLACMetadataBlockPicture, err error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return
+	}
+
+	mimeType := sniffMIMEType(data)
+
+	if !strings.HasPrefix(mimeType, "image/") {
+		err = fmt.Errorf("%s is not an image: detected %s", path, mimeType)
+
+		return
+	}
+
+	return flac.AddPicture(pictureType, mimeType, description, data)
+}
+
+// pictureExtensions holds the file extensions preferred for common image MIME types.
+var pictureExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/jpg": ".jpg",
+	"image/png": ".png",
+	"image/gif": ".gif",
+	"image/bmp": ".bmp",
+	"image/webp": ".webp",
+	"image/tiff": ".tif",
+	"image/avif": ".avif",
+}
+
+// Extension returns a file extension for the picture's MIME type, such as ".jpg", falling back to the
+// extensions known to the mime package and then to ".bin".
+func (block *FLACMetadataBlockPicture) Extension() string {
+	mimeType := strings.ToLower(strings.TrimSpace(block.MIMEType))
+
+	if extension, ok := pictureExtensions[mimeType]; ok {
+		return extension
+	}
+
+	if extensions, _ := mime.ExtensionsByType(mimeType); len(extensions) > 0 {
+		return extensions[0]
+	}
+
+	return ".bin"
+}
+
+// WriteFile writes the picture data to path, streaming data left unread by WithLazyPictures from the source
+// file rather than loading it. A partly written file is removed if copying fails.
+func (block *FLACMetadataBlockPicture) WriteFile(path string) (err error) {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return
+	}
+
+	_, err = block.Copy(file)
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(path)
+	}
+
+	return
+}
+
+// ExportPictures writes each picture to dir, named by its position among the pictures and its type with an
+// extension from its MIME type, such as "00-FrontCover.jpg", and returns the paths written. Pictures that
+// hold a URL, with the MIME type "-->", are skipped.
+func (flac *FLAC) ExportPictures(dir string) (paths []string, err error) {
+	for index, block := range flac.Pictures() {
+		if block.IsURL() {
+			continue
+		}
+
+		name, _ := block.Type.MarshalText()
+		path := filepath.Join(dir, fmt.Sprintf("%02d-%s%s", index, name, block.Extension()))
+		err = block.WriteFile(path)
+
+		if err != nil {
+			return
+		}
+
+		paths = append(paths, path)
+	}
+
+	return
+}
+
+// PictureFinding is a problem ValidatePictures found with a picture block. Block numbers are 0 for STREAMINFO
+// and count up through MetadataBlocks, and Field names the picture field at fault.
+type PictureFinding struct {
+	Block int
+	Field string
+	Message string
+}
+
+func (finding PictureFinding) String() string {
+	return fmt.Sprintf("block %d: %s: %s", finding.Block, finding.Field, finding.Message)
+}
+
+// canonicalMIMETypes maps MIME types in common use to those content sniffing reports.
+var canonicalMIMETypes = map[string]string{
+	"image/jpg": "image/jpeg",
+	"image/pjpeg": "image/jpeg",
+	"image/x-png": "image/png",
+	"image/x-ms-bmp": "image/bmp",
+}
+
+// validate checks the picture against its data, recording findings under blockNumber.
+func (block *FLACMetadataBlockPicture) validate(blockNumber int) (findings []PictureFinding, err error) {
+	report := func(field string, format string, args ...interface{}) {
+		findings = append(findings, PictureFinding{blockNumber, field, fmt.Sprintf(format, args...)})
+	}
+
+	if block.IsURL() {
+		url, urlErr := block.URL()
+
+		if urlErr != nil {
+			return nil, urlErr
+		}
+
+		report("MIMEType", "picture is a link to %q rather than embedded image data", url)
+
+		return
+	}
+
+	header := make([]byte, 512)
+	reader := block.Open()
+	n, err := io.ReadFull(reader, header)
+
+	closeReader(reader)
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	detected := sniffMIMEType(header[:n])
+	declared := strings.ToLower(strings.TrimSpace(block.MIMEType))
+
+	if canonical, ok := canonicalMIMETypes[declared]; ok {
+		declared = canonical
+	}
+
+	switch {
+		case !strings.HasPrefix(detected, "image/"):
+			report("MIMEType", "data is not a recognised image, declared as %s", block.MIMEType)
+
+		case detected != declared:
+			report("MIMEType", "declared as %s but data is %s", block.MIMEType, detected)
+	}
+
+	mismatches, decodeErr := block.VerifyDimensions()
+
+	if decodeErr != nil {
+		report("Picture", "image cannot be decoded: %v", decodeErr)
+
+		return
+	}
+
+	for _, mismatch := range mismatches {
+		report(mismatch.Field, "declared as %d but image has %d", mismatch.Declared, mismatch.Actual)
+	}
+
+	return
+}
+
+// ValidatePictures checks every picture block's declared MIME type, dimensions and colour depth against its
+// image data, and flags pictures that link to a URL with the MIME type "-->". Images that cannot be decoded are
+// reported as findings; err is only set if the data cannot be read.
+func (flac *FLAC) ValidatePictures() (findings []PictureFinding, err error) {
+	for index, iBlock := range flac.MetadataBlocks {
+		block, ok := iBlock.(*FLACMetadataBlockPicture)
+
+		if !ok {
+			continue
+		}
+
+		var blockFindings []PictureFinding
+
+		blockFindings, err = block.validate(index + 1)
+		findings = append(findings, blockFindings...)
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,325 @@
+package flac
+
+import (
+	"io"
+	"os"
+	"bytes"
+	"image"
+	"image/png"
+	"image/color"
+	"encoding/hex"
+	"path/filepath"
+)
+
+func (suite *FLACTestSuite) TestFrontCoverImage() {
+	img, err := suite.flac.FrontCoverImage()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(2448, img.Bounds().Dx())
+	suite.assert.Equal(3264, img.Bounds().Dy())
+
+	limit := MaxImagePixels
+	MaxImagePixels = 1000
+
+	defer func() {
+		MaxImagePixels = limit
+	}()
+
+	_, err = suite.flac.FrontCoverImage()
+
+	suite.assert.Error(err)
+}
+
+func (suite *FLACTestSuite) TestVerifyDimensions() {
+	cover := suite.flac.FrontCover()
+	mismatches, err := cover.VerifyDimensions()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(0, len(mismatches))
+
+	cover.Width = 100
+	cover.NumColours = 3
+	mismatches, err = cover.VerifyDimensions()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal([]PictureMismatch{{"Width", 100, 2448}, {"NumColours", 3, 0}}, mismatches)
+	suite.assert.NoError(cover.FixDimensions())
+	suite.assert.Equal(2448, cover.Width)
+	suite.assert.Equal(0, cover.NumColours)
+}
+
+func (suite *FLACTestSuite) TestPictureColourDepth() {
+	bounds := image.Rect(0, 0, 2, 2)
+	translucent := image.NewNRGBA(bounds)
+	translucent.Set(0, 0, color.NRGBA{255, 0, 0, 128})
+	opaque := image.NewRGBA(bounds)
+
+	for index := range opaque.Pix {
+		opaque.Pix[index] = 255
+	}
+
+	images := []struct {
+		img image.Image
+		depth uint32
+		colours uint32
+	}{
+		{image.NewGray(bounds), 8, 0},
+		{image.NewGray16(bounds), 16, 0},
+		{opaque, 24, 0},
+		{translucent, 32, 0},
+		{image.NewPaletted(bounds, color.Palette{color.Black, color.White, color.Gray{128}}), 24, 3},
+	}
+	flac := suite.flac.Clone()
+
+	for _, expected := range images {
+		var data bytes.Buffer
+
+		suite.assert.NoError(png.Encode(&data, expected.img))
+
+		block, err := NewPicture(Other, "image/png", "", data.Bytes())
+
+		suite.assert.NoError(err)
+		suite.assert.Equal(expected.depth, block.ColourDepth)
+		suite.assert.Equal(expected.colours, block.NumColours)
+
+		block.ColourDepth = 0
+		block.NumColours = 0
+
+		suite.assert.NoError(flac.AppendBlock(block))
+	}
+
+	suite.assert.NoError(flac.AppendBlock(newPicture(Artist, URLMIMEType, "", []byte("http://example.com/a.jpg"))))
+
+	fixed, err := flac.FixPictureDimensions()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(len(images), fixed)
+
+	pictures := flac.Pictures()
+
+	suite.assert.Equal(3, pictures[len(pictures) - 2].NumColours)
+
+	fixed, err = flac.FixPictureDimensions()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(0, fixed)
+
+	suite.assert.NoError(flac.AppendBlock(newPicture(Other, "image/png", "", []byte("garbage"))))
+
+	_, err = flac.FixPictureDimensions()
+
+	suite.assert.Error(err)
+}
+
+func (suite *FLACTestSuite) TestLazyPictures() {
+	flac, err := Parse("sample.flac", WithLazyPictures())
+
+	suite.assert.NoError(err)
+
+	cover := flac.FrontCover()
+
+	suite.assert.False(cover.Loaded())
+	suite.assert.Nil(cover.Picture)
+	suite.assert.Equal(1661438 - 42, cover.PictureLength)
+	suite.assert.Equal(2448, cover.Width)
+
+	data, err := io.ReadAll(cover.Open())
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(suite.flac.FrontCover().Picture, data)
+	suite.assert.False(cover.Loaded())
+
+	mismatches, err := cover.VerifyDimensions()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(0, len(mismatches))
+
+	handle, err := os.Open("sample.flac")
+
+	suite.assert.NoError(err)
+
+	defer handle.Close()
+
+	suite.assert.NoError(cover.Load(handle))
+	suite.assert.True(cover.Loaded())
+	suite.assert.Equal("c6f3cec420be726d74ca3ccfb7461f65", hex.EncodeToString(cover.PictureMD5))
+}
+
+func (suite *FLACTestSuite) TestAddPictureFromFile() {
+	var data bytes.Buffer
+
+	suite.assert.NoError(png.Encode(&data, image.NewGray(image.Rect(0, 0, 12, 8))))
+
+	dir := suite.T().TempDir()
+	path := filepath.Join(dir, "cover.png")
+
+	suite.assert.NoError(os.WriteFile(path, data.Bytes(), 0644))
+
+	flac := suite.flac.Clone()
+	count := len(flac.MetadataBlocks)
+	block, err := flac.AddPictureFromFile(path, BackCover, "back")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(count + 1, len(flac.MetadataBlocks))
+	suite.assert.Equal(block, flac.MetadataBlocks[count])
+	suite.assert.Equal(BackCover, block.Type)
+	suite.assert.Equal("image/png", block.MIMEType)
+	suite.assert.Equal("back", block.Description)
+	suite.assert.Equal(12, block.Width)
+	suite.assert.Equal(8, block.Height)
+	suite.assert.Equal(8, block.ColourDepth)
+	suite.assert.Equal(data.Len(), block.PictureLength)
+	suite.assert.Equal(pictureMD5(data.Bytes()), block.PictureMD5)
+
+	_, err = flac.WriteMetadata(io.Discard)
+
+	suite.assert.NoError(err)
+
+	text := filepath.Join(dir, "notes.txt")
+
+	suite.assert.NoError(os.WriteFile(text, []byte("not an image"), 0644))
+
+	_, err = flac.AddPictureFromFile(text, FrontCover, "")
+
+	suite.assert.Error(err)
+
+	_, err = flac.AddPictureFromFile(filepath.Join(dir, "missing.png"), FrontCover, "")
+
+	suite.assert.Error(err)
+	suite.assert.Equal(count + 1, len(flac.MetadataBlocks))
+}
+
+func (suite *FLACTestSuite) TestExportPictures() {
+	flac, err := Parse("sample.flac", WithLazyPictures())
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	flac.AppendBlock(&FLACMetadataBlockPicture{
+		FLACMetadataBlock: FLACMetadataBlock{
+			Type: Picture,
+		},
+		Type: Artist,
+		MIMEType: "-->",
+		Picture: []byte("http://example.com/artist.jpg"),
+	})
+
+	dir := suite.T().TempDir()
+	paths, err := flac.ExportPictures(dir)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal([]string{filepath.Join(dir, "00-FrontCover.jpg")}, paths)
+	suite.assert.False(flac.FrontCover().Loaded())
+
+	data, err := os.ReadFile(paths[0])
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(suite.flac.FrontCover().Picture, data)
+	suite.assert.Equal(".png", (&FLACMetadataBlockPicture{MIMEType: "IMAGE/PNG"}).Extension())
+	suite.assert.Equal(".bin", (&FLACMetadataBlockPicture{MIMEType: "image/x-unheard-of"}).Extension())
+	suite.assert.Error(flac.FrontCover().WriteFile(filepath.Join(dir, "missing", "cover.jpg")))
+}
+
+func (suite *FLACTestSuite) TestSetFrontCover() {
+	var data bytes.Buffer
+
+	suite.assert.NoError(png.Encode(&data, image.NewGray(image.Rect(0, 0, 4, 4))))
+
+	flac := suite.flac.Clone()
+	original := flac.FrontCover()
+	original.Description = "cover"
+
+	suite.assert.NoError(flac.AppendBlock(original.clone(flac)))
+	suite.assert.NoError(flac.SetFrontCover(data.Bytes(), ""))
+
+	cover := flac.FrontCover()
+
+	suite.assert.Equal(cover, flac.MetadataBlocks[3])
+	suite.assert.Equal("image/png", cover.MIMEType)
+	suite.assert.Equal("cover", cover.Description)
+	suite.assert.Equal(4, cover.Width)
+	suite.assert.Equal(1, len(flac.Pictures()))
+	suite.assert.True(flac.MetadataBlocks[len(flac.MetadataBlocks) - 1].header().Last)
+
+	suite.assert.Equal(1, flac.RemovePictures(FrontCover))
+	suite.assert.Equal(0, flac.RemovePictures(FrontCover))
+	suite.assert.Nil(flac.FrontCover())
+
+	suite.assert.NoError(flac.SetFrontCover(data.Bytes(), "image/png"))
+	suite.assert.Equal(flac.FrontCover(), flac.MetadataBlocks[len(flac.MetadataBlocks) - 1])
+	suite.assert.Error(flac.SetFrontCover([]byte("not an image"), ""))
+
+	path, _ := suite.copySample()
+	saved, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer saved.Close()
+
+	suite.assert.NoError(saved.SetFrontCover(data.Bytes(), "image/png"))
+	suite.assert.NoError(saved.Save(WithInPlaceOnly()))
+}
+
+func (suite *FLACTestSuite) TestValidatePictures() {
+	findings, err := suite.flac.ValidatePictures()
+
+	suite.assert.NoError(err)
+	suite.assert.Empty(findings)
+
+	var data bytes.Buffer
+
+	suite.assert.NoError(png.Encode(&data, image.NewGray(image.Rect(0, 0, 4, 4))))
+
+	flac := suite.flac.Clone()
+	cover := flac.FrontCover()
+	cover.MIMEType = "image/jpg"
+	cover.Width = 100
+
+	lying := newPicture(BackCover, "image/jpeg", "", data.Bytes())
+
+	flac.AppendBlock(lying)
+	flac.AppendBlock(newPicture(Artist, "-->", "", []byte("http://example.com/a.jpg")))
+	flac.AppendBlock(newPicture(Media, "image/png", "", []byte("garbage")))
+
+	findings, err = flac.ValidatePictures()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal([]PictureFinding{
+		{4, "Width", "declared as 100 but image has 2448"},
+		{7, "MIMEType", "declared as image/jpeg but data is image/png"},
+		{7, "Width", "declared as 0 but image has 4"},
+		{7, "Height", "declared as 0 but image has 4"},
+		{7, "ColourDepth", "declared as 0 but image has 8"},
+		{8, "MIMEType", "picture is a link to \"http://example.com/a.jpg\" rather than embedded image data"},
+		{9, "MIMEType", "data is not a recognised image, declared as image/png"},
+		{9, "Picture", "image cannot be decoded: image: unknown format"},
+	}, findings)
+	suite.assert.Equal("block 4: Width: declared as 100 but image has 2448", findings[0].String())
+}
+
+func (suite *FLACTestSuite) TestCopyPicture() {
+	flac, err := Parse("sample.flac", WithLazyPictures())
+
+	suite.assert.NoError(err)
+
+	cover := flac.FrontCover()
+
+	suite.assert.NoError(flac.Close())
+
+	var data bytes.Buffer
+
+	n, err := cover.Copy(&data)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(cover.PictureLength, n)
+	suite.assert.False(cover.Loaded())
+	suite.assert.Equal(suite.flac.FrontCover().Picture, data.Bytes())
+
+	data.Reset()
+
+	n, err = suite.flac.FrontCover().Copy(&data)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(cover.PictureLength, n)
+}
@@ -0,0 +1,153 @@
+package flac
+
+import (
+	"os"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePictureSpec creates a picture block from a specification in the form metaflac --import-picture-from
+// accepts: [TYPE]|[MIME-TYPE]|[DESCRIPTION]|[WIDTHxHEIGHTxDEPTH[/COLORS]]|FILE, or just FILE. An empty TYPE is
+// a front cover, an empty MIME-TYPE is sniffed from the file, and empty dimensions are read from the image.
+// With the MIME type "-->", FILE is a URL stored as the picture data instead of being read.
+func ParsePictureSpec(spec string) (block *FLACMetadataBlockPicture, err error) {
+	fields := strings.SplitN(spec, "|", 5)
+
+	if len(fields) == 1 {
+		fields = []string{"", "", "", "", spec}
+	}
+
+	if len(fields) != 5 {
+		err = fmt.Errorf("picture specification %q does not have 5 fields", spec)
+
+		return
+	}
+
+	pictureType := FrontCover
+
+	if fields[0] != "" {
+		var number uint64
+
+		number, err = strconv.ParseUint(fields[0], 10, 32)
+
+		if err != nil || number > uint64(PublisherLogo) {
+			err = fmt.Errorf("invalid picture type %q", fields[0])
+
+			return
+		}
+
+		pictureType = PictureType(number)
+	}
+
+	mimeType := fields[1]
+	description := fields[2]
+	var data []byte
+
+	if mimeType == URLMIMEType {
+		data = []byte(fields[4])
+	} else {
+		data, err = os.ReadFile(fields[4])
+
+		if err != nil {
+			return
+		}
+
+		if mimeType == "" {
+			mimeType = sniffMIMEType(data)
+		}
+	}
+
+	block = newPicture(pictureType, mimeType, description, data)
+
+	switch {
+		case fields[3] != "":
+			err = block.parseDimensions(fields[3])
+
+		case !block.IsURL():
+			err = block.FixDimensions()
+	}
+
+	if err != nil {
+		block = nil
+	}
+
+	return
+}
+
+// parseDimensions sets the dimensions from a WIDTHxHEIGHTxDEPTH[/COLORS] specification.
+func (block *FLACMetadataBlockPicture) parseDimensions(dimensions string) (err error) {
+	sizes, colours, hasColours := strings.Cut(dimensions, "/")
+	fields := strings.Split(sizes, "x")
+
+	if len(fields) != 3 {
+		err = fmt.Errorf("invalid picture dimensions %q", dimensions)
+
+		return
+	}
+
+	values := make([]uint32, 4)
+
+	if hasColours {
+		fields = append(fields, colours)
+	}
+
+	for index, field := range fields {
+		var value uint64
+
+		value, err = strconv.ParseUint(field, 10, 32)
+
+		if err != nil {
+			err = fmt.Errorf("invalid picture dimensions %q", dimensions)
+
+			return
+		}
+
+		values[index] = uint32(value)
+	}
+
+	block.Width, block.Height, block.ColourDepth, block.NumColours = values[0], values[1], values[2], values[3]
+
+	return
+}
+
+// ImportPicture appends the picture described by a metaflac picture specification, as ParsePictureSpec reads it.
+// Images are passed through the ImageTransformer installed with SetImageTransformer, and dimensions given in
+// the specification are read from the image again if it is transformed.
+func (flac *FLAC) ImportPicture(spec string) (block *FLACMetadataBlockPicture, err error) {
+	block, err = ParsePictureSpec(spec)
+
+	if err != nil {
+		return
+	}
+
+	if flac.imageTransformer != nil && !block.IsURL() {
+		var data []byte
+		var mimeType string
+
+		data, mimeType, err = flac.imageTransformer.Transform(block.Picture, block.MIMEType)
+
+		if err != nil {
+			block = nil
+
+			return
+		}
+
+		if !bytes.Equal(data, block.Picture) || mimeType != block.MIMEType {
+			block, err = NewPicture(block.Type, mimeType, block.Description, data)
+
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	err = flac.AppendBlock(block)
+
+	if err != nil {
+		block = nil
+	}
+
+	return
+}
@@ -0,0 +1,59 @@
+package flac
+
+import (
+	"os"
+	"bytes"
+	"image"
+	"image/png"
+	"path/filepath"
+)
+
+func (suite *FLACTestSuite) TestParsePictureSpec() {
+	var data bytes.Buffer
+
+	suite.assert.NoError(png.Encode(&data, image.NewGray(image.Rect(0, 0, 6, 5))))
+
+	dir := suite.T().TempDir()
+	path := filepath.Join(dir, "cover.png")
+
+	suite.assert.NoError(os.WriteFile(path, data.Bytes(), 0644))
+
+	block, err := ParsePictureSpec(path)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(FrontCover, block.Type)
+	suite.assert.Equal("image/png", block.MIMEType)
+	suite.assert.Equal(6, block.Width)
+	suite.assert.Equal(5, block.Height)
+	suite.assert.Equal(data.Bytes(), block.Picture)
+
+	piped := filepath.Join(dir, "a|b.png")
+
+	suite.assert.NoError(os.WriteFile(piped, data.Bytes(), 0644))
+
+	block, err = ParsePictureSpec("4|image/x-custom|Back side|300x200x24/16|" + piped)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(BackCover, block.Type)
+	suite.assert.Equal("image/x-custom", block.MIMEType)
+	suite.assert.Equal("Back side", block.Description)
+	suite.assert.Equal([]uint32{300, 200, 24, 16}, []uint32{block.Width, block.Height, block.ColourDepth, block.NumColours})
+
+	block, err = ParsePictureSpec("|-->||32x32x24|http://example.com/icon.png")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("http://example.com/icon.png", string(block.Picture))
+	suite.assert.Equal(32, block.Width)
+
+	for _, spec := range []string{"21||||" + path, "x||||" + path, "|||12x12|" + path, "3|||1x2x3/x|" + path, "3|", "||||missing.png"} {
+		_, err = ParsePictureSpec(spec)
+
+		suite.assert.Error(err, spec)
+	}
+
+	flac := suite.flac.Clone()
+	block, err = flac.ImportPicture("3|image/png|cover||" + path)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(block, flac.MetadataBlocks[len(flac.MetadataBlocks) - 1])
+}
@@ -0,0 +1,142 @@
+package flac
+
+import (
+	"io"
+	"fmt"
+	"errors"
+	"net/http"
+)
+
+// URLMIMEType is the MIME type of a picture block whose data is a URL linking to the image rather than the image.
+const URLMIMEType = "-->"
+
+// IsURL reports whether the picture links to its image by URL rather than embedding it. Such pictures have no
+// MD5 signature and are not checked as images.
+func (block *FLACMetadataBlockPicture) IsURL() bool {
+	return block.MIMEType == URLMIMEType
+}
+
+// URL returns the URL a picture links to, or "" if it embeds its image.
+func (block *FLACMetadataBlockPicture) URL() (url string, err error) {
+	if !block.IsURL() {
+		return
+	}
+
+	var data []byte
+
+	data, err = io.ReadAll(block.Open())
+	url = string(data)
+
+	return
+}
+
+// PictureFetcher retrieves the image a picture links to by URL. An empty mimeType is sniffed from the data.
+type PictureFetcher interface {
+	Fetch(url string) (data []byte, mimeType string, err error)
+}
+
+// HTTPPictureFetcher fetches linked images with Client, or http.DefaultClient if it is nil, refusing
+// images larger than MaxSize bytes when MaxSize is not zero.
+type HTTPPictureFetcher struct {
+	Client *http.Client
+	MaxSize int64
+}
+
+func (fetcher HTTPPictureFetcher) Fetch(url string) (data []byte, mimeType string, err error) {
+	client := fetcher.Client
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Get(url)
+
+	if err != nil {
+		return
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("fetching %s: %s", url, response.Status)
+
+		return
+	}
+
+	reader := io.Reader(response.Body)
+
+	if fetcher.MaxSize > 0 {
+		reader = io.LimitReader(response.Body, fetcher.MaxSize + 1)
+	}
+
+	data, err = io.ReadAll(reader)
+
+	if err == nil && fetcher.MaxSize > 0 && int64(len(data)) > fetcher.MaxSize {
+		err = fmt.Errorf("fetching %s: image is larger than %d bytes", url, fetcher.MaxSize)
+	}
+
+	if err != nil {
+		data = nil
+
+		return
+	}
+
+	mimeType = response.Header.Get("Content-Type")
+
+	return
+}
+
+// Resolve fetches the image a picture links to and returns a picture block embedding it, with the same type
+// and description and with the dimensions read from the image.
+func (block *FLACMetadataBlockPicture) Resolve(fetcher PictureFetcher) (resolved *FLACMetadataBlockPicture, err error) {
+	url, err := block.URL()
+
+	if err != nil {
+		return
+	}
+
+	if url == "" {
+		err = errors.New("picture does not link to a URL")
+
+		return
+	}
+
+	data, mimeType, err := fetcher.Fetch(url)
+
+	if err != nil {
+		return
+	}
+
+	if mimeType == "" {
+		mimeType = sniffMIMEType(data)
+	}
+
+	return NewPicture(block.Type, mimeType, block.Description, data)
+}
+
+// ResolvePictureURLs replaces every picture that links to a URL with one embedding the fetched image and
+// returns how many were replaced. It stops at the first picture that cannot be resolved.
+func (flac *FLAC) ResolvePictureURLs(fetcher PictureFetcher) (resolved int, err error) {
+	for index, iBlock := range flac.MetadataBlocks {
+		block, ok := iBlock.(*FLACMetadataBlockPicture)
+
+		if !ok || !block.IsURL() {
+			continue
+		}
+
+		var picture *FLACMetadataBlockPicture
+
+		picture, err = block.Resolve(fetcher)
+
+		if err != nil {
+			return
+		}
+
+		picture.FLAC = flac
+		picture.Last = block.Last
+		flac.MetadataBlocks[index] = picture
+		resolved++
+	}
+
+	return
+}
@@ -0,0 +1,73 @@
+package flac
+
+import (
+	"net/http"
+	"net/http/httptest"
+)
+
+func (suite *FLACTestSuite) TestPictureURL() {
+	image := suite.flac.Pictures()[0].Picture
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cover" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Write(image)
+	}))
+
+	defer server.Close()
+
+	path, _ := suite.copySample()
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	link := newPicture(BackCover, URLMIMEType, "back", []byte(server.URL + "/cover"))
+
+	suite.assert.True(link.IsURL())
+	suite.assert.Empty(link.PictureMD5)
+	suite.assert.NoError(flac.AppendBlock(link))
+
+	url, err := link.URL()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(server.URL + "/cover", url)
+
+	url, err = flac.Pictures()[0].URL()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("", url)
+
+	_, _, err = HTTPPictureFetcher{MaxSize: 1024}.Fetch(server.URL + "/cover")
+
+	suite.assert.Error(err)
+
+	_, _, err = HTTPPictureFetcher{}.Fetch(server.URL + "/missing")
+
+	suite.assert.Error(err)
+
+	resolved, err := flac.ResolvePictureURLs(HTTPPictureFetcher{})
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(1, resolved)
+
+	pictures := flac.Pictures()
+	picture := pictures[len(pictures) - 1]
+
+	suite.assert.False(picture.IsURL())
+	suite.assert.Equal(BackCover, picture.Type)
+	suite.assert.Equal("back", picture.Description)
+	suite.assert.Equal("image/jpeg", picture.MIMEType)
+	suite.assert.Equal(uint32(2448), picture.Width)
+	suite.assert.Equal(pictures[0].PictureMD5, picture.PictureMD5)
+	suite.assert.NoError(flac.Save())
+
+	resolved, err = flac.ResolvePictureURLs(HTTPPictureFetcher{})
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(0, resolved)
+}
@@ -0,0 +1,206 @@
+package flac
+
+import (
+	"io"
+	"fmt"
+	"time"
+	"strings"
+	"io/fs"
+	"path/filepath"
+)
+
+// PlaylistEntry is a single playlist item. Entries derived from cue sheets play a section of the file
+// between Start and End; End is zero when the entry plays to the end of the file.
+type PlaylistEntry struct {
+	Path string
+	Artist string
+	Title string
+	Track int
+	Duration time.Duration
+	Start time.Duration
+	End time.Duration
+}
+
+// Playlist is an ordered list of entries that can be written as .m3u8 or .pls.
+type Playlist struct {
+	Entries []PlaylistEntry
+}
+
+func samplesToDuration(samples uint64, sampleRate uint32) time.Duration {
+	streamInfo := FLACMetadataBlockStreamInfo{
+		SampleRate: sampleRate,
+		NumSamples: samples,
+	}
+
+	return streamInfo.Duration()
+}
+
+// playlistEntries returns the entries for a parsed file, splitting it into virtual tracks
+// when useCueSheets is set and the file carries a cue sheet.
+func (flac *FLAC) playlistEntries(path string, useCueSheets bool) (entries []PlaylistEntry) {
+	info := flac.TrackInfo()
+	title := info.Title
+
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	entry := PlaylistEntry{
+		Path: path,
+		Artist: info.Artist,
+		Title: title,
+		Track: info.TrackNumber,
+		Duration: info.Duration,
+	}
+
+	cueSheet := flac.CueSheet()
+
+	if !useCueSheets || cueSheet == nil || len(cueSheet.CueSheetTracks) < 2 {
+		return []PlaylistEntry{entry}
+	}
+
+	if info.Album != "" {
+		title = info.Album
+	}
+
+	tracks := cueSheet.CueSheetTracks
+	starts := make([]uint64, len(tracks))
+
+	for index, track := range tracks {
+		starts[index] = track.Offset
+
+		for _, trackIndex := range track.CueSheetTrackIndices {
+			if trackIndex.IndexNumber == 1 {
+				starts[index] = track.Offset + trackIndex.Offset
+			}
+		}
+	}
+
+	for index, track := range tracks[:len(tracks) - 1] {
+		if !track.IsAudio {
+			continue
+		}
+
+		start := samplesToDuration(starts[index], info.SampleRate)
+		end := samplesToDuration(starts[index + 1], info.SampleRate)
+
+		entries = append(entries, PlaylistEntry{
+			Path: path,
+			Artist: info.Artist,
+			Title: fmt.Sprintf("%s - Track %02d", title, track.Track),
+			Track: int(track.Track),
+			Duration: end - start,
+			Start: start,
+			End: end,
+		})
+	}
+
+	return
+}
+
+// ScanPlaylist builds a playlist from every .flac file below root, with paths relative to root.
+// Files with cue sheets are split into one entry per track when useCueSheets is set.
+func ScanPlaylist(root string, useCueSheets bool) (playlist *Playlist, result *BatchResult, err error) {
+	playlist = &Playlist{}
+	result = &BatchResult{}
+
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".flac") {
+			return nil
+		}
+
+		flac, parseErr := Parse(path)
+
+		result.add(path, parseErr)
+
+		if parseErr != nil {
+			return nil
+		}
+
+		defer flac.Close()
+
+		relative, relErr := filepath.Rel(root, path)
+
+		if relErr != nil {
+			return relErr
+		}
+
+		playlist.Entries = append(playlist.Entries, flac.playlistEntries(filepath.ToSlash(relative), useCueSheets)...)
+
+		return nil
+	})
+
+	return
+}
+
+func (entry PlaylistEntry) displayTitle() string {
+	if entry.Artist == "" {
+		return entry.Title
+	}
+
+	return entry.Artist + " - " + entry.Title
+}
+
+func formatSeconds(duration time.Duration) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.3f", duration.Seconds()), "0"), ".")
+}
+
+// WriteM3U8 writes the playlist in extended M3U format. Cue sheet entries carry
+// EXTVLCOPT start-time and stop-time lines for players that support them.
+func (playlist *Playlist) WriteM3U8(w io.Writer) (err error) {
+	_, err = fmt.Fprintln(w, "#EXTM3U")
+
+	if err != nil {
+		return
+	}
+
+	for _, entry := range playlist.Entries {
+		_, err = fmt.Fprintf(w, "#EXTINF:%d,%s\n", int64(entry.Duration.Seconds()), entry.displayTitle())
+
+		if err != nil {
+			return
+		}
+
+		if entry.End != 0 {
+			_, err = fmt.Fprintf(w, "#EXTVLCOPT:start-time=%s\n#EXTVLCOPT:stop-time=%s\n", formatSeconds(entry.Start), formatSeconds(entry.End))
+
+			if err != nil {
+				return
+			}
+		}
+
+		_, err = fmt.Fprintln(w, entry.Path)
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WritePLS writes the playlist in PLS version 2 format, which has no notion of offsets.
+func (playlist *Playlist) WritePLS(w io.Writer) (err error) {
+	_, err = fmt.Fprintln(w, "[playlist]")
+
+	if err != nil {
+		return
+	}
+
+	for index, entry := range playlist.Entries {
+		number := index + 1
+		_, err = fmt.Fprintf(w, "File%d=%s\nTitle%d=%s\nLength%d=%d\n", number, entry.Path, number, entry.displayTitle(), number, int64(entry.Duration.Seconds()))
+
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "NumberOfEntries=%d\nVersion=2\n", len(playlist.Entries))
+
+	return
+}
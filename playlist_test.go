@@ -0,0 +1,49 @@
+package flac
+
+import (
+	"os"
+	"bytes"
+	"path/filepath"
+)
+
+func (suite *FLACTestSuite) TestScanPlaylist() {
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	root := suite.T().TempDir()
+
+	suite.assert.NoError(os.MkdirAll(filepath.Join(root, "album"), 0755))
+	suite.assert.NoError(os.WriteFile(filepath.Join(root, "album", "sample.flac"), data, 0644))
+	suite.assert.NoError(os.WriteFile(filepath.Join(root, "album", "broken.flac"), []byte("junk"), 0644))
+	suite.assert.NoError(os.WriteFile(filepath.Join(root, "album", "notes.txt"), []byte("junk"), 0644))
+
+	playlist, result, err := ScanPlaylist(root, false)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(1, len(result.Failed()))
+	suite.assert.Equal(1, len(playlist.Entries))
+	suite.assert.Equal("album/sample.flac", playlist.Entries[0].Path)
+	suite.assert.Equal("sample", playlist.Entries[0].Title)
+
+	playlist, _, err = ScanPlaylist(root, true)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(3, len(playlist.Entries))
+	suite.assert.Equal(2, playlist.Entries[1].Track)
+	suite.assert.Equal(samplesToDuration(3528, 88200), playlist.Entries[1].Start)
+	suite.assert.Equal(samplesToDuration(4704, 88200), playlist.Entries[1].End)
+
+	var m3u bytes.Buffer
+
+	suite.assert.NoError(playlist.WriteM3U8(&m3u))
+	suite.assert.Equal("#EXTM3U\n" +
+		"#EXTINF:0,sample - Track 01\n#EXTVLCOPT:start-time=0\n#EXTVLCOPT:stop-time=0.04\nalbum/sample.flac\n" +
+		"#EXTINF:0,sample - Track 02\n#EXTVLCOPT:start-time=0.04\n#EXTVLCOPT:stop-time=0.053\nalbum/sample.flac\n" +
+		"#EXTINF:8,sample - Track 03\n#EXTVLCOPT:start-time=0.053\n#EXTVLCOPT:stop-time=8.994\nalbum/sample.flac\n", m3u.String())
+
+	var pls bytes.Buffer
+
+	suite.assert.NoError(playlist.WritePLS(&pls))
+	suite.assert.Contains(pls.String(), "File3=album/sample.flac\nTitle3=sample - Track 03\nLength3=8\nNumberOfEntries=3\nVersion=2\n")
+}
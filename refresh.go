@@ -0,0 +1,146 @@
+package flac
+
+import (
+	"io"
+	"os"
+	"io/fs"
+	"time"
+	"bytes"
+	"reflect"
+	"crypto/md5"
+)
+
+// quickHashLength is the number of leading bytes hashed to detect edits that keep size and mtime.
+const quickHashLength = 64 * 1024
+
+// fileState returns the size, modification time and, when hash is set and the file supports random access,
+// a hash of the start of an open file.
+func fileState(file fs.File, hash bool) (size int64, modTime time.Time, quickHash []byte, err error) {
+	info, err := file.Stat()
+
+	if err != nil {
+		return
+	}
+
+	size = info.Size()
+	modTime = info.ModTime()
+	readerAt, ok := file.(io.ReaderAt)
+
+	if !hash || !ok {
+		return
+	}
+
+	hasher := md5.New()
+	_, err = io.Copy(hasher, io.NewSectionReader(readerAt, 0, quickHashLength))
+
+	if err != nil {
+		return
+	}
+
+	quickHash = hasher.Sum(nil)
+
+	return
+}
+
+// open opens the file the FLAC was parsed from.
+func (flac *FLAC) open() (fs.File, error) {
+	if flac.fsys != nil {
+		return flac.fsys.Open(flac.path)
+	}
+
+	return os.Open(flac.path)
+}
+
+// Changed reports whether the file the FLAC was parsed from has been modified since.
+func (flac *FLAC) Changed() (changed bool, err error) {
+	file, err := flac.open()
+
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	size, modTime, quickHash, err := fileState(file, flac.quickHash != nil)
+
+	if err != nil {
+		return
+	}
+
+	changed = size != flac.size || !modTime.Equal(flac.modTime) || !bytes.Equal(quickHash, flac.quickHash)
+
+	return
+}
+
+// Refresh re-parses the metadata if the underlying file has changed since it was parsed.
+// Blocks whose contents are unchanged keep their identity, so references held by callers remain valid.
+func (flac *FLAC) Refresh() (changed bool, err error) {
+	changed, err = flac.Changed()
+
+	if err != nil || !changed {
+		return
+	}
+
+	file, err := flac.open()
+
+	if err != nil {
+		return
+	}
+
+	fresh, err := parseFile(file, flac.path, flac.fsys, flac.options)
+
+	if err != nil {
+		return
+	}
+
+	candidates := append([]IFLACMetadataBlock{flac.StreamInfo}, flac.MetadataBlocks...)
+
+	// reuse returns an existing block equal to the fresh one, updated to its new position, or the fresh block.
+	reuse := func(block IFLACMetadataBlock) IFLACMetadataBlock {
+		header := block.header()
+		headerOffset, offset := header.HeaderOffset, header.Offset
+		header.FLAC = flac
+
+		for index, old := range candidates {
+			if old == nil || reflect.TypeOf(old) != reflect.TypeOf(block) {
+				continue
+			}
+
+			header.HeaderOffset, header.Offset = old.header().HeaderOffset, old.header().Offset
+
+			if reflect.DeepEqual(block, old) {
+				old.header().HeaderOffset, old.header().Offset = headerOffset, offset
+				candidates[index] = nil
+
+				return old
+			}
+		}
+
+		header.HeaderOffset, header.Offset = headerOffset, offset
+
+		return block
+	}
+
+	streamInfo := reuse(fresh.StreamInfo).(*FLACMetadataBlockStreamInfo)
+	blocks := make([]IFLACMetadataBlock, len(fresh.MetadataBlocks))
+
+	for index, block := range fresh.MetadataBlocks {
+		blocks[index] = reuse(block)
+	}
+
+	flac.Close()
+
+	flac.file = fresh.file
+	flac.Marker = fresh.Marker
+	flac.StreamInfo = streamInfo
+	flac.MetadataBlocks = blocks
+	flac.AudioOffset = fresh.AudioOffset
+	flac.Gap = fresh.Gap
+	flac.Warnings = fresh.Warnings
+	flac.blocksLeftOut = fresh.blocksLeftOut
+	flac.size = fresh.size
+	flac.modTime = fresh.modTime
+	flac.quickHash = fresh.quickHash
+
+	return
+}
@@ -0,0 +1,93 @@
+package flac
+
+import (
+	"os"
+	"bytes"
+	"path/filepath"
+)
+
+func (suite *FLACTestSuite) TestRefresh() {
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	path := filepath.Join(suite.T().TempDir(), "refresh.flac")
+
+	suite.assert.NoError(os.WriteFile(path, data, 0644))
+
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	changed, err := flac.Refresh()
+
+	suite.assert.NoError(err)
+	suite.assert.False(changed)
+
+	streamInfo := flac.StreamInfo
+	picture := flac.FrontCover()
+	var application *FLACMetadataBlockApplication
+
+	for _, iBlock := range flac.MetadataBlocks {
+		if block, ok := iBlock.(*FLACMetadataBlockApplication); ok {
+			application = block
+		}
+	}
+
+	// Rewrite the application payload in place, keeping the file size.
+	index := bytes.Index(data, []byte("ATCHC@K3"))
+
+	suite.assert.True(index > 0)
+	copy(data[index:], "ATCHD@K3")
+	suite.assert.NoError(os.WriteFile(path, data, 0644))
+
+	changed, err = flac.Refresh()
+
+	suite.assert.NoError(err)
+	suite.assert.True(changed)
+	suite.assert.True(streamInfo == flac.StreamInfo)
+	suite.assert.True(picture == flac.FrontCover())
+
+	for _, iBlock := range flac.MetadataBlocks {
+		if block, ok := iBlock.(*FLACMetadataBlockApplication); ok {
+			suite.assert.False(block == application)
+			suite.assert.Equal("D@K3", string(block.AppData))
+			suite.assert.Equal(flac, block.FLACMetadataBlock.FLAC)
+		}
+	}
+
+	// Grow the metadata from another handle so the audio frames move.
+	other, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer other.Close()
+
+	other.VorbisComment().AddTag(LyricsTag, string(bytes.Repeat([]byte("la "), 8000)))
+
+	suite.assert.NoError(other.Save())
+
+	changed, err = flac.Refresh()
+
+	suite.assert.NoError(err)
+	suite.assert.True(changed)
+	suite.assert.Equal(other.AudioOffset, flac.AudioOffset)
+	suite.assert.True(flac.AudioOffset > suite.flac.AudioOffset)
+
+	copied := filepath.Join(suite.T().TempDir(), "copy.flac")
+
+	suite.assert.NoError(flac.SaveAs(copied))
+
+	saved, err := Parse(copied)
+
+	suite.assert.NoError(err)
+
+	defer saved.Close()
+
+	written, err := os.ReadFile(copied)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(data[suite.flac.AudioOffset:], written[saved.AudioOffset:])
+}
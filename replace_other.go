@@ -0,0 +1,12 @@
+//go:build !windows
+
+package flac
+
+import (
+	"os"
+)
+
+// replaceFile renames source over target, which replaces target atomically even while it is open.
+func replaceFile(source string, target string) error {
+	return os.Rename(source, target)
+}
@@ -0,0 +1,85 @@
+//go:build windows
+
+package flac
+
+import (
+	"os"
+	"time"
+	"slices"
+	"errors"
+	"unsafe"
+	"syscall"
+)
+
+// replaceAttempts is how many times replaceFile tries before giving up, waiting replaceDelay after the first
+// failure and twice as long after each one following it.
+const replaceAttempts = 8
+const replaceDelay = 10 * time.Millisecond
+
+// replaceFileIgnoreMergeErrors is REPLACEFILE_IGNORE_MERGE_ERRORS, which keeps ReplaceFile from failing when
+// attributes or ACLs of the replaced file cannot be carried over.
+const replaceFileIgnoreMergeErrors = 0x2
+
+const errorFileNotFound = syscall.Errno(2)
+
+// retryErrors are the errors seen while another process, such as a virus scanner, search indexer or media
+// player, briefly holds the file open. They are ERROR_ACCESS_DENIED, ERROR_SHARING_VIOLATION,
+// ERROR_LOCK_VIOLATION, ERROR_UNABLE_TO_REMOVE_REPLACED and ERROR_UNABLE_TO_MOVE_REPLACEMENT, after all of
+// which both files keep their original names.
+var retryErrors = []syscall.Errno{5, 32, 33, 1175, 1176}
+
+var procReplaceFileW = syscall.NewLazyDLL("kernel32.dll").NewProc("ReplaceFileW")
+
+// replaceFile moves source over target. An existing target is replaced with ReplaceFile, which unlike a rename
+// keeps its creation time, ACLs and other attributes, and a missing one is renamed into place. Failures caused by
+// other processes holding either file open are retried with backoff.
+func replaceFile(source string, target string) (err error) {
+	delay := replaceDelay
+
+	for attempt := 1; ; attempt++ {
+		err = replace(source, target)
+
+		var errno syscall.Errno
+
+		if err == nil || attempt == replaceAttempts || !errors.As(err, &errno) || !slices.Contains(retryErrors, errno) {
+			return
+		}
+
+		time.Sleep(delay)
+
+		delay *= 2
+	}
+}
+
+// replace makes one attempt at moving source over target.
+func replace(source string, target string) (err error) {
+	sourcePointer, err := syscall.UTF16PtrFromString(source)
+
+	if err != nil {
+		return
+	}
+
+	targetPointer, err := syscall.UTF16PtrFromString(target)
+
+	if err != nil {
+		return
+	}
+
+	result, _, callErr := procReplaceFileW.Call(uintptr(unsafe.Pointer(targetPointer)), uintptr(unsafe.Pointer(sourcePointer)),
+		0, replaceFileIgnoreMergeErrors, 0, 0)
+
+	switch {
+		case result != 0:
+			return nil
+
+		case callErr == errorFileNotFound:
+			return os.Rename(source, target)
+	}
+
+	return &os.LinkError{
+		Op: "replace",
+		Old: source,
+		New: target,
+		Err: callErr,
+	}
+}
@@ -0,0 +1,123 @@
+package flac
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"encoding/csv"
+)
+
+// ReportColumn is a technical detail WriteReport can list after the tag fields.
+type ReportColumn uint
+
+// Enum indicating the technical columns of a report.
+const (
+	DurationColumn ReportColumn = iota
+	SampleRateColumn
+	BitsPerSampleColumn
+	ChannelsColumn
+	NumSamplesColumn
+)
+
+var reportColumnNames = map[ReportColumn]string{
+	DurationColumn: "duration",
+	SampleRateColumn: "sample_rate",
+	BitsPerSampleColumn: "bits_per_sample",
+	ChannelsColumn: "channels",
+	NumSamplesColumn: "samples",
+}
+
+func (column ReportColumn) String() string {
+	if name, ok := reportColumnNames[column]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// value formats the column for the stream info, with durations in seconds to the millisecond.
+func (column ReportColumn) value(streamInfo *FLACMetadataBlockStreamInfo) string {
+	if streamInfo == nil {
+		return ""
+	}
+
+	switch column {
+		case DurationColumn:
+			return strconv.FormatFloat(streamInfo.Duration().Seconds(), 'f', 3, 64)
+
+		case SampleRateColumn:
+			return strconv.FormatUint(uint64(streamInfo.SampleRate), 10)
+
+		case BitsPerSampleColumn:
+			return strconv.FormatUint(uint64(streamInfo.BitsPerSample), 10)
+
+		case ChannelsColumn:
+			return strconv.FormatUint(uint64(streamInfo.Channels), 10)
+
+		case NumSamplesColumn:
+			return strconv.FormatUint(streamInfo.NumSamples, 10)
+	}
+
+	return ""
+}
+
+// WriteReport writes a row for each path to w, separated by comma: ',' for CSV or '\t' for TSV. Rows hold the
+// path, the values of the tag fields in tags, joined with "; " where there are several, and then the columns,
+// after a header row naming them. Files that cannot be parsed are left out and reported in result; err is only
+// set if writing fails.
+func WriteReport(w io.Writer, comma rune, paths []string, tags []string, columns ...ReportColumn) (result *BatchResult, err error) {
+	result = &BatchResult{}
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	header := append([]string{"path"}, tags...)
+
+	for _, column := range columns {
+		header = append(header, column.String())
+	}
+
+	err = writer.Write(header)
+
+	if err != nil {
+		return
+	}
+
+	for _, path := range paths {
+		flac, parseErr := Parse(path, WithSkipPictures())
+
+		result.add(path, parseErr)
+
+		if parseErr != nil {
+			continue
+		}
+
+		row := []string{path}
+		comments := flac.VorbisComment()
+
+		for _, key := range tags {
+			value := ""
+
+			if comments != nil {
+				value = strings.Join(comments.GetTag(key), "; ")
+			}
+
+			row = append(row, value)
+		}
+
+		for _, column := range columns {
+			row = append(row, column.value(flac.StreamInfo))
+		}
+
+		flac.Close()
+
+		err = writer.Write(row)
+
+		if err != nil {
+			return
+		}
+	}
+
+	writer.Flush()
+	err = writer.Error()
+
+	return
+}
@@ -0,0 +1,28 @@
+package flac
+
+import (
+	"strings"
+)
+
+func (suite *FLACTestSuite) TestWriteReport() {
+	var output strings.Builder
+
+	result, err := WriteReport(&output, '\t', []string{"sample.flac", "missing.flac"}, []string{"EXAMPLE", TitleTag}, DurationColumn, SampleRateColumn, BitsPerSampleColumn)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal([]string{"missing.flac"}, result.Failed())
+
+	info := suite.flac.StreamInfo
+	expected := "path\tEXAMPLE\tTITLE\tduration\tsample_rate\tbits_per_sample\n" +
+		"sample.flac\tfish\t\t" + DurationColumn.value(info) + "\t" + SampleRateColumn.value(info) + "\t" + BitsPerSampleColumn.value(info) + "\n"
+
+	suite.assert.Equal(expected, output.String())
+	suite.assert.Equal("88200", SampleRateColumn.value(info))
+
+	output.Reset()
+
+	_, err = WriteReport(&output, ',', []string{"sample.flac"}, []string{"Example, Title"})
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("path,\"Example, Title\"\nsample.flac,\n", output.String())
+}
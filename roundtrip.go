@@ -0,0 +1,82 @@
+package flac
+
+import (
+	"io"
+	"os"
+	"slices"
+)
+
+// ByteRange is a span of a file, Length bytes from Offset.
+type ByteRange struct {
+	Offset int64
+	Length int64
+}
+
+// Roundtrip parses the metadata of the file at path, encodes it again and compares the result with the metadata
+// bytes in the file. identical is true when go-flac would write the metadata back unchanged; otherwise diff holds
+// the ranges of the file that would differ, the last one covering any difference in length.
+func Roundtrip(path string, options ...ParseOption) (identical bool, diff []ByteRange, err error) {
+	flac, audioOffset, err := ParseMetadata(path, options...)
+
+	if err != nil {
+		return
+	}
+
+	handle, err := os.Open(path)
+
+	if err != nil {
+		return
+	}
+
+	defer handle.Close()
+
+	original := make([]byte, audioOffset)
+
+	_, err = io.ReadFull(handle, original)
+
+	if err != nil {
+		return
+	}
+
+	data, _, err := encodeMetadata(slices.Collect(flac.Blocks()))
+
+	if err != nil {
+		return
+	}
+
+	encoded := append([]byte(flac.Marker), data...)
+	diff = diffBytes(original, encoded)
+	identical = len(diff) == 0
+
+	return
+}
+
+// diffBytes returns the ranges where a and b differ, coalescing adjacent bytes. Bytes past the end of the
+// shorter slice form the last range.
+func diffBytes(a []byte, b []byte) (diff []ByteRange) {
+	length := min(len(a), len(b))
+
+	for index := 0; index < length; index++ {
+		if a[index] == b[index] {
+			continue
+		}
+
+		if last := len(diff) - 1; last >= 0 && diff[last].Offset + diff[last].Length == int64(index) {
+			diff[last].Length++
+		} else {
+			diff = append(diff, ByteRange{
+				Offset: int64(index),
+				Length: 1,
+			})
+		}
+	}
+
+	if len(a) != len(b) {
+		diff = append(diff, ByteRange{
+			Offset: int64(length),
+			Length: int64(max(len(a), len(b)) - length),
+		})
+	}
+
+	return
+}
@@ -0,0 +1,38 @@
+package flac
+
+import (
+	"os"
+	"slices"
+)
+
+func (suite *FLACTestSuite) TestRoundtrip() {
+	identical, diff, err := Roundtrip("sample.flac")
+
+	suite.assert.NoError(err)
+	suite.assert.True(identical)
+	suite.assert.Empty(diff)
+
+	// Two stray bytes after the stream info are dropped when the metadata is written again.
+	path, data := suite.copySample()
+	data = slices.Insert(data, 8 + 34, 0xAB, 0xCD)
+	data[7] = 36
+
+	suite.assert.NoError(os.WriteFile(path, data, 0644))
+
+	_, _, err = Roundtrip(path)
+
+	suite.assert.Error(err)
+
+	identical, diff, err = Roundtrip(path, WithLenient())
+
+	suite.assert.NoError(err)
+	suite.assert.False(identical)
+	suite.assert.Equal(ByteRange{Offset: 7, Length: 1}, diff[0])
+	suite.assert.Equal(int64(2), diff[len(diff) - 1].Length)
+}
+
+func (suite *FLACTestSuite) TestDiffBytes() {
+	suite.assert.Empty(diffBytes([]byte("abc"), []byte("abc")))
+	suite.assert.Equal([]ByteRange{{1, 2}, {4, 1}}, diffBytes([]byte("abcde"), []byte("aXYdZ")))
+	suite.assert.Equal([]ByteRange{{1, 1}, {3, 2}}, diffBytes([]byte("abc"), []byte("aXcde")))
+}
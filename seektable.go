@@ -0,0 +1,437 @@
+package flac
+
+import (
+	"io"
+	"fmt"
+	"cmp"
+	"sort"
+	"slices"
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"math/bits"
+)
+
+// SeekPlaceholder is the sample number of a placeholder seek point, which reserves room in a seek table
+// without pointing into the audio.
+const SeekPlaceholder = 0xffffffffffffffff
+
+// maxFrameHeaderSize is the longest a frame header can be, including its CRC-8.
+const maxFrameHeaderSize = 16
+
+// frameScanChunk is how much audio is read at a time while looking for frame headers.
+const frameScanChunk = 1 << 20
+
+// frameInfo locates an audio frame: its first sample, its offset from the first frame header and its length in samples.
+type frameInfo struct {
+	sample uint64
+	offset uint64
+	size uint16
+}
+
+// crc8 computes the CRC-8 that protects a frame header, with polynomial x^8 + x^2 + x + 1.
+func crc8(data []byte) (crc byte) {
+	for _, value := range data {
+		crc ^= value
+
+		for bit := 0; bit < 8; bit++ {
+			if crc & 0x80 != 0 {
+				crc = crc << 1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return
+}
+
+// parseFrameHeader decodes the frame header at the start of data, returning its block size, whether the stream
+// uses variable block sizes and the coded number, which is a sample number for variable block sizes and a frame
+// number otherwise. ok is false unless data holds a complete header with reserved values unused and a valid CRC-8.
+func parseFrameHeader(data []byte) (blockSize uint32, variable bool, number uint64, ok bool) {
+	if len(data) < 6 || !isFrameSync(data[0], data[1]) {
+		return
+	}
+
+	variable = data[1] & 1 != 0
+	blockCode := data[2] >> 4
+	rateCode := data[2] & 0x0f
+
+	if blockCode == 0 || rateCode == 15 || data[3] >> 4 > 10 || data[3] >> 1 & 7 == 3 || data[3] & 1 != 0 {
+		return
+	}
+
+	// The number is coded like UTF-8, extended to 7 bytes for 36 bit sample numbers.
+	length := bits.LeadingZeros8(^data[4])
+
+	switch {
+		case length == 0:
+			length = 1
+			number = uint64(data[4])
+
+		case length == 1 || length == 8 || !variable && length > 6 || 4 + length > len(data):
+			return
+
+		default:
+			number = uint64(data[4] & (0x7f >> length))
+
+			for _, value := range data[5:4 + length] {
+				if value & 0xc0 != 0x80 {
+					return
+				}
+
+				number = number << 6 | uint64(value & 0x3f)
+			}
+	}
+
+	position := 4 + length
+
+	switch {
+		case blockCode == 1:
+			blockSize = 192
+
+		case blockCode <= 5:
+			blockSize = 576 << (blockCode - 2)
+
+		case blockCode == 6 && position < len(data):
+			blockSize = uint32(data[position]) + 1
+			position++
+
+		case blockCode == 7 && position + 1 < len(data):
+			blockSize = uint32(data[position]) << 8 | uint32(data[position + 1]) + 1
+			position += 2
+
+		case blockCode >= 8:
+			blockSize = 256 << (blockCode - 8)
+	}
+
+	switch rateCode {
+		case 12:
+			position++
+
+		case 13, 14:
+			position += 2
+	}
+
+	ok = blockSize > 0 && blockSize <= 0xffff && position < len(data) && crc8(data[:position]) == data[position]
+
+	return
+}
+
+// scanFrames reads the audio of the file the metadata was parsed from and locates its frames by their headers.
+// A candidate header is only accepted if its coded number continues the frames found before it, and, when the
+// stream info records a minimum frame size, if it lies at least that far past the previous frame.
+func (flac *FLAC) scanFrames() (frames []frameInfo, err error) {
+	if flac.path == "" {
+		err = errors.New("FLAC was not parsed from a file")
+
+		return
+	}
+
+	file, err := flac.open()
+
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	readerAt, ok := file.(io.ReaderAt)
+
+	if !ok {
+		err = errors.New("file does not support random access")
+
+		return
+	}
+
+	var sample uint64
+	var first, previous int64
+	numSamples := flac.StreamInfo.NumSamples
+	minFrameSize := int64(flac.StreamInfo.MinFrameSize)
+	data := make([]byte, frameScanChunk + maxFrameHeaderSize)
+
+	for offset := flac.AudioOffset; numSamples == 0 || sample < numSamples; offset += frameScanChunk {
+		n, readErr := readerAt.ReadAt(data, offset)
+
+		if readErr != nil && readErr != io.EOF {
+			err = readErr
+
+			return
+		}
+
+		limit := min(n, frameScanChunk)
+
+		if readErr == io.EOF {
+			limit = n
+		}
+
+		for index := 0; index < limit && (numSamples == 0 || sample < numSamples); index++ {
+			found := bytes.IndexByte(data[index:limit], 0xff)
+
+			if found < 0 {
+				break
+			}
+
+			index += found
+			position := offset + int64(index)
+			blockSize, variable, number, ok := parseFrameHeader(data[index:n])
+			expected := uint64(len(frames))
+
+			if variable {
+				expected = sample
+			}
+
+			if !ok || number != expected || len(frames) > 0 && position - previous < minFrameSize {
+				continue
+			}
+
+			if len(frames) == 0 {
+				first = position
+			}
+
+			frames = append(frames, frameInfo{sample, uint64(position - first), uint16(blockSize)})
+			sample += uint64(blockSize)
+			previous = position
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	if len(frames) == 0 {
+		err = errors.New("no audio frames found")
+	}
+
+	return
+}
+
+// seekTargets returns the sample numbers asked for by a seek point specification, as GenerateSeekTable reads it,
+// and the number of placeholder points.
+func seekTargets(spec string, sampleRate uint32, numSamples uint64) (targets []uint64, placeholders int, err error) {
+	for _, item := range strings.Split(spec, ";") {
+		item = strings.TrimSpace(item)
+
+		if (strings.HasSuffix(item, "x") || strings.HasSuffix(item, "s")) && numSamples == 0 {
+			err = fmt.Errorf("seek points %q need the total number of samples, which is unknown", item)
+
+			return
+		}
+
+		switch {
+			case item == "":
+				continue
+
+			case item == "X":
+				placeholders++
+
+			case strings.HasSuffix(item, "x"):
+				count, parseErr := strconv.ParseUint(strings.TrimSuffix(item, "x"), 10, 64)
+
+				if parseErr != nil || count == 0 {
+					err = fmt.Errorf("invalid seek point count %q", item)
+
+					return
+				}
+
+				count = min(count, numSamples)
+
+				for point := uint64(0); point < count; point++ {
+					hi, lo := bits.Mul64(point, numSamples)
+					sample, _ := bits.Div64(hi, lo, count)
+					targets = append(targets, sample)
+				}
+
+			case strings.HasSuffix(item, "s"):
+				seconds, parseErr := strconv.ParseFloat(strings.TrimSuffix(item, "s"), 64)
+				step := uint64(seconds * float64(sampleRate))
+
+				if parseErr != nil || !(seconds > 0) || step == 0 {
+					err = fmt.Errorf("invalid seek point interval %q", item)
+
+					return
+				}
+
+				for sample := uint64(0); sample < numSamples; sample += step {
+					targets = append(targets, sample)
+				}
+
+			default:
+				sample, parseErr := strconv.ParseUint(item, 10, 64)
+
+				if parseErr != nil {
+					err = fmt.Errorf("invalid seek point %q", item)
+
+					return
+				}
+
+				if numSamples == 0 || sample < numSamples {
+					targets = append(targets, sample)
+				}
+		}
+	}
+
+	return
+}
+
+// GenerateSeekTable builds a seek table from a metaflac style specification of points separated by semicolons:
+// a sample number such as "4096", "#x" for # evenly spaced points such as "100x", "#s" for a point every #
+// seconds such as "10s" or "0.5s", and "X" for a placeholder. Each point is resolved to the frame holding its
+// sample, which means scanning the audio for frame headers. Points beyond the end of the stream are dropped,
+// points falling in the same frame are merged and placeholders are put last. The block is not added to the file.
+func (flac *FLAC) GenerateSeekTable(spec string) (block *FLACMetadataBlockSeekTable, err error) {
+	targets, placeholders, err := seekTargets(spec, flac.StreamInfo.SampleRate, flac.StreamInfo.NumSamples)
+
+	if err != nil {
+		return
+	}
+
+	var frames []frameInfo
+
+	if len(targets) > 0 {
+		frames, err = flac.scanFrames()
+
+		if err != nil {
+			return
+		}
+	}
+
+	block = &FLACMetadataBlockSeekTable{
+		FLACMetadataBlock: FLACMetadataBlock{
+			Type: SeekTable,
+		},
+		SeekPoints: []SeekPoint{},
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i] < targets[j]
+	})
+
+	for _, target := range targets {
+		index := sort.Search(len(frames), func(index int) bool {
+			return frames[index].sample > target
+		}) - 1
+
+		if index < 0 || target >= frames[index].sample + uint64(frames[index].size) {
+			continue
+		}
+
+		frame := frames[index]
+
+		if points := block.SeekPoints; len(points) > 0 && points[len(points) - 1].Sample == frame.sample {
+			continue
+		}
+
+		block.SeekPoints = append(block.SeekPoints, SeekPoint{frame.sample, frame.offset, frame.size})
+	}
+
+	for placeholder := 0; placeholder < placeholders; placeholder++ {
+		block.SeekPoints = append(block.SeekPoints, SeekPoint{Sample: SeekPlaceholder})
+	}
+
+	block.DataLength = uint32(18 * len(block.SeekPoints))
+
+	return
+}
+
+// Lookup returns the last seek point at or before sample, from which a player decodes forward to reach it, and
+// false if every point is after it or the table holds only placeholders. The points must be in ascending order
+// with placeholders last, as the format requires.
+func (block *FLACMetadataBlockSeekTable) Lookup(sample uint64) (point SeekPoint, ok bool) {
+	index := sort.Search(len(block.SeekPoints), func(index int) bool {
+		found := block.SeekPoints[index].Sample
+
+		return found > sample || found == SeekPlaceholder
+	}) - 1
+
+	if index < 0 {
+		return
+	}
+
+	return block.SeekPoints[index], true
+}
+
+// SeekPointViolation describes a seek point that breaks the ordering rules of the specification or lies past the
+// end of the stream. Index is the position of the point in SeekPoints.
+type SeekPointViolation struct {
+	Index int
+	Message string
+}
+
+func (violation SeekPointViolation) String() string {
+	return fmt.Sprintf("seek point %d: %s", violation.Index, violation.Message)
+}
+
+// streamSamples returns the total samples recorded in the stream info of the FLAC the block belongs to,
+// or zero if it is unknown.
+func (block *FLACMetadataBlockSeekTable) streamSamples() uint64 {
+	if block.FLAC == nil || block.FLAC.StreamInfo == nil {
+		return 0
+	}
+
+	return block.FLAC.StreamInfo.NumSamples
+}
+
+// Validate checks that the points are in ascending sample order without duplicates, that placeholders come last,
+// that byte offsets rise with the samples, and, when the total samples of the stream is known, that no point is at
+// or past the end.
+func (block *FLACMetadataBlockSeekTable) Validate() (violations []SeekPointViolation) {
+	numSamples := block.streamSamples()
+
+	for index, point := range block.SeekPoints {
+		if point.Sample == SeekPlaceholder {
+			continue
+		}
+
+		if numSamples > 0 && point.Sample >= numSamples {
+			violations = append(violations, SeekPointViolation{index, fmt.Sprintf("sample %d is past the end of the stream at %d", point.Sample, numSamples)})
+		}
+
+		if index == 0 {
+			continue
+		}
+
+		previous := block.SeekPoints[index - 1]
+
+		switch {
+			case previous.Sample == SeekPlaceholder:
+				violations = append(violations, SeekPointViolation{index, "follows a placeholder point"})
+
+			case point.Sample == previous.Sample:
+				violations = append(violations, SeekPointViolation{index, fmt.Sprintf("duplicates sample %d", point.Sample)})
+
+			case point.Sample < previous.Sample:
+				violations = append(violations, SeekPointViolation{index, "is not in ascending sample order"})
+
+			case point.ByteOffset <= previous.ByteOffset:
+				violations = append(violations, SeekPointViolation{index, fmt.Sprintf("byte offset %d does not follow %d of the previous point", point.ByteOffset, previous.ByteOffset)})
+		}
+	}
+
+	return
+}
+
+// Normalize sorts the points by sample with placeholders last, keeps only the first point for each sample and,
+// when the total samples of the stream is known, removes points at or past the end. It returns the number of
+// points removed.
+func (block *FLACMetadataBlockSeekTable) Normalize() (removed int) {
+	numSamples := block.streamSamples()
+	count := len(block.SeekPoints)
+	points := slices.DeleteFunc(block.SeekPoints, func(point SeekPoint) bool {
+		return point.Sample != SeekPlaceholder && numSamples > 0 && point.Sample >= numSamples
+	})
+
+	slices.SortStableFunc(points, func(a SeekPoint, b SeekPoint) int {
+		return cmp.Compare(a.Sample, b.Sample)
+	})
+
+	block.SeekPoints = slices.CompactFunc(points, func(a SeekPoint, b SeekPoint) bool {
+		return a.Sample == b.Sample && a.Sample != SeekPlaceholder
+	})
+	block.DataLength = uint32(18 * len(block.SeekPoints))
+	removed = count - len(block.SeekPoints)
+
+	return
+}
@@ -0,0 +1,121 @@
+package flac
+
+import (
+	"os"
+)
+
+func (suite *FLACTestSuite) TestGenerateSeekTable() {
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	block, err := suite.flac.GenerateSeekTable("4x; 793286; 99999999; X; 0")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(SeekTable, block.Type)
+	suite.assert.Equal(6 * 18, block.DataLength)
+
+	samples := []uint64{0, 196608, 393216, 593920, 790528, SeekPlaceholder}
+
+	for index, point := range block.SeekPoints {
+		suite.assert.Equal(samples[index], point.Sample)
+
+		if point.Sample == SeekPlaceholder {
+			continue
+		}
+
+		header := data[suite.flac.AudioOffset + int64(point.ByteOffset):]
+		_, _, number, ok := parseFrameHeader(header)
+
+		suite.assert.True(ok)
+		suite.assert.Equal(point.Sample / 4096, number)
+	}
+
+	suite.assert.Equal(SeekPoint{0, 0, 4096}, block.SeekPoints[0])
+	suite.assert.Equal(2759, block.SeekPoints[4].NumSamples)
+	suite.assert.True(block.SeekPoints[1].ByteOffset < block.SeekPoints[2].ByteOffset)
+
+	block, err = suite.flac.GenerateSeekTable("1s")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(9, len(block.SeekPoints))
+	suite.assert.Equal(86016, block.SeekPoints[1].Sample)
+
+	block, err = suite.flac.GenerateSeekTable("X;X")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal([]SeekPoint{{Sample: SeekPlaceholder}, {Sample: SeekPlaceholder}}, block.SeekPoints)
+
+	for _, spec := range []string{"ten", "0x", "-1s", "0.5x"} {
+		_, err = suite.flac.GenerateSeekTable(spec)
+
+		suite.assert.Error(err, spec)
+	}
+}
+
+func (suite *FLACTestSuite) TestSeekTableLookup() {
+	block := &FLACMetadataBlockSeekTable{
+		SeekPoints: []SeekPoint{{4096, 100, 4096}, {8192, 200, 4096}, {40960, 900, 4096}, {Sample: SeekPlaceholder}},
+	}
+
+	_, ok := block.Lookup(4095)
+
+	suite.assert.False(ok)
+
+	for sample, expected := range map[uint64]uint64{4096: 4096, 8191: 4096, 8192: 8192, 40959: 8192, 1 << 40: 40960, SeekPlaceholder: 40960} {
+		point, ok := block.Lookup(sample)
+
+		suite.assert.True(ok)
+		suite.assert.Equal(expected, point.Sample)
+	}
+
+	point, ok := suite.flac.SeekTable().Lookup(500000)
+
+	suite.assert.True(ok)
+	suite.assert.Equal(SeekPoint{0, 0, 4096}, point)
+
+	_, ok = (&FLACMetadataBlockSeekTable{SeekPoints: []SeekPoint{{Sample: SeekPlaceholder}}}).Lookup(0)
+
+	suite.assert.False(ok)
+
+	_, ok = (&FLACMetadataBlockSeekTable{}).Lookup(0)
+
+	suite.assert.False(ok)
+}
+
+func (suite *FLACTestSuite) TestSeekTableNormalize() {
+	flac := suite.flac.Clone()
+	block := flac.SeekTable()
+
+	suite.assert.Empty(block.Validate())
+
+	block.SeekPoints = []SeekPoint{
+		{8192, 200, 4096},
+		{Sample: SeekPlaceholder},
+		{0, 0, 4096},
+		{8192, 300, 4096},
+		{4096, 400, 4096},
+		{900000, 5000, 4096},
+		{Sample: SeekPlaceholder},
+	}
+
+	violations := block.Validate()
+
+	suite.assert.Equal([]SeekPointViolation{
+		{2, "follows a placeholder point"},
+		{4, "is not in ascending sample order"},
+		{5, "sample 900000 is past the end of the stream at 793287"},
+	}, violations)
+	suite.assert.Equal("seek point 2: follows a placeholder point", violations[0].String())
+
+	suite.assert.Equal(2, block.Normalize())
+	suite.assert.Equal([]SeekPoint{{0, 0, 4096}, {4096, 400, 4096}, {8192, 200, 4096}, {Sample: SeekPlaceholder}, {Sample: SeekPlaceholder}}, block.SeekPoints)
+	suite.assert.Equal(5 * 18, block.DataLength)
+	suite.assert.Equal([]SeekPointViolation{{2, "byte offset 200 does not follow 400 of the previous point"}}, block.Validate())
+	suite.assert.Equal(0, block.Normalize())
+
+	orphan := &FLACMetadataBlockSeekTable{SeekPoints: []SeekPoint{{900000, 0, 4096}}}
+
+	suite.assert.Empty(orphan.Validate())
+	suite.assert.Equal(0, orphan.Normalize())
+}
@@ -0,0 +1,139 @@
+package flac
+
+import (
+	"os"
+	"fmt"
+	"time"
+	"errors"
+)
+
+// EditSession stages edits to several files, such as the tracks of an album, and saves them together. Commit backs
+// up every file before writing any of them, and if one fails to save restores those already written, so the files
+// end up either all saved or all as they were.
+type EditSession struct {
+	files []*FLAC
+}
+
+// NewEditSession returns an empty edit session.
+func NewEditSession() *EditSession {
+	return &EditSession{}
+}
+
+// Open parses the file at path and adds it to the session, returning its metadata for editing.
+func (session *EditSession) Open(path string, options ...ParseOption) (flac *FLAC, err error) {
+	flac, err = Parse(path, options...)
+
+	if err != nil {
+		return
+	}
+
+	session.files = append(session.files, flac)
+
+	return
+}
+
+// Add adds metadata parsed from a file on disk to the session. The session closes it along with its own files.
+func (session *EditSession) Add(flac *FLAC) (err error) {
+	if flac.fsys != nil || flac.path == "" {
+		err = errors.New("FLAC was not parsed from a file that can be written")
+
+		return
+	}
+
+	session.files = append(session.files, flac)
+
+	return
+}
+
+// Commit saves every file in the session with options, in the order they were added. If a file cannot be saved,
+// it and the files written before it are restored from backups taken before the first write. The metadata of the
+// files that were written is re-read from disk, so their edits must be made again; the other files keep theirs.
+// The error is a *FileError for the file that failed, or a *BatchError also holding any failures to restore.
+// Backups are removed once the session is committed or rolled back, except those that could not be restored.
+func (session *EditSession) Commit(options ...SaveOption) (err error) {
+	if newSaveOptions(options).report != nil {
+		err = errors.New("edit sessions cannot be dry run")
+
+		return
+	}
+
+	suffix := fmt.Sprintf(".%d.bak", time.Now().UnixNano())
+	backedUp := 0
+	kept := map[*FLAC]bool{}
+
+	defer func() {
+		for _, flac := range session.files[:backedUp] {
+			if !kept[flac] {
+				os.Remove(flac.path + suffix)
+			}
+		}
+	}()
+
+	for _, flac := range session.files {
+		err = backup(flac.path, suffix)
+
+		if err != nil {
+			return &FileError{flac.path, err}
+		}
+
+		backedUp++
+	}
+
+	for index, flac := range session.files {
+		err = flac.Save(options...)
+
+		if err == nil {
+			continue
+		}
+
+		errs := []error{&FileError{flac.path, err}}
+
+		for _, written := range session.files[:index + 1] {
+			restoreErr := written.restore(written.path + suffix, written != flac)
+
+			if restoreErr != nil {
+				kept[written] = true
+				errs = append(errs, &FileError{written.path, fmt.Errorf("restoring from backup %s: %w", written.path + suffix, restoreErr)})
+			}
+		}
+
+		if len(errs) == 1 {
+			return errs[0]
+		}
+
+		return &BatchError{errs}
+	}
+
+	return
+}
+
+// restore replaces the file the metadata was parsed from with the backup at path and refreshes the metadata.
+// With saved set the metadata is re-read even if the file looks unchanged, as it describes the edits that were saved.
+func (flac *FLAC) restore(path string, saved bool) (err error) {
+	flac.Close()
+
+	err = replaceFile(path, flac.path)
+
+	if err != nil {
+		return
+	}
+
+	if saved {
+		flac.size = -1
+	}
+
+	_, err = flac.Refresh()
+
+	return
+}
+
+// Close closes every file in the session.
+func (session *EditSession) Close() (err error) {
+	for _, flac := range session.files {
+		if closeErr := flac.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return
+}
@@ -0,0 +1,110 @@
+package flac
+
+import (
+	"os"
+	"errors"
+	"bytes"
+	"path/filepath"
+)
+
+func (suite *FLACTestSuite) sessionFiles(count int) (paths []string, original []byte) {
+	original, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	dir := suite.T().TempDir()
+
+	for index := 0; index < count; index++ {
+		path := filepath.Join(dir, string(rune('a' + index)) + ".flac")
+
+		suite.assert.NoError(os.WriteFile(path, original, 0644))
+
+		paths = append(paths, path)
+	}
+
+	return
+}
+
+func (suite *FLACTestSuite) TestEditSessionCommit() {
+	paths, original := suite.sessionFiles(2)
+	session := NewEditSession()
+
+	defer session.Close()
+
+	for _, path := range paths {
+		flac, err := session.Open(path)
+
+		suite.assert.NoError(err)
+		suite.assert.NoError(flac.VorbisComment().SetTag(TitleTag, "Committed"))
+	}
+
+	suite.assert.NoError(session.Commit())
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+
+		suite.assert.NoError(err)
+		suite.assert.False(bytes.Equal(original, data))
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(paths[0]))
+
+	suite.assert.NoError(err)
+	suite.assert.Len(entries, 2)
+}
+
+func (suite *FLACTestSuite) TestEditSessionRollback() {
+	paths, original := suite.sessionFiles(3)
+	session := NewEditSession()
+
+	defer session.Close()
+
+	var files []*FLAC
+
+	for _, path := range paths {
+		flac, err := session.Open(path)
+
+		suite.assert.NoError(err)
+		suite.assert.NoError(flac.VorbisComment().SetTag(TitleTag, "Rolled back"))
+
+		files = append(files, flac)
+	}
+
+	// The last file no longer fits in its padding, so saving it in place fails after the others are written.
+	suite.assert.NoError(files[2].VorbisComment().SetTag("LYRICS", string(bytes.Repeat([]byte("la "), 4000))))
+
+	err := session.Commit(WithInPlaceOnly())
+
+	var fileErr *FileError
+
+	suite.assert.ErrorIs(err, ErrInsufficientPadding)
+	suite.assert.True(errors.As(err, &fileErr))
+	suite.assert.Equal(paths[2], fileErr.Path)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+
+		suite.assert.NoError(err)
+		suite.assert.True(bytes.Equal(original, data))
+	}
+
+	// The saved files are re-read from disk, while the file that failed keeps its edits.
+	for _, flac := range files[:2] {
+		changed, err := flac.Changed()
+
+		suite.assert.NoError(err)
+		suite.assert.False(changed)
+		suite.assert.Equal(suite.flac.VorbisComment().GetTag(TitleTag), flac.VorbisComment().GetTag(TitleTag))
+	}
+
+	suite.assert.Equal([]string{"Rolled back"}, files[2].VorbisComment().GetTag(TitleTag))
+
+	entries, err := os.ReadDir(filepath.Dir(paths[0]))
+
+	suite.assert.NoError(err)
+	suite.assert.Len(entries, 3)
+
+	// Once the edits fit, the session commits.
+	suite.assert.NoError(files[2].VorbisComment().SetTag("LYRICS"))
+	suite.assert.NoError(session.Commit(WithInPlaceOnly()))
+}
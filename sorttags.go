@@ -0,0 +1,127 @@
+package flac
+
+import (
+	"strings"
+)
+
+// TitleSort returns the first TITLESORT value.
+func (block *FLACMetadataBlockVorbisComment) TitleSort() string {
+	return block.firstComment(TitleSortTag)
+}
+
+// SetTitleSort replaces the TITLESORT values, removing them if titleSort is empty.
+func (block *FLACMetadataBlockVorbisComment) SetTitleSort(titleSort string) {
+	block.setTag(TitleSortTag, titleSort)
+}
+
+// ArtistSort returns the first ARTISTSORT value.
+func (block *FLACMetadataBlockVorbisComment) ArtistSort() string {
+	return block.firstComment(ArtistSortTag)
+}
+
+// SetArtistSort replaces the ARTISTSORT values, removing them if artistSort is empty.
+func (block *FLACMetadataBlockVorbisComment) SetArtistSort(artistSort string) {
+	block.setTag(ArtistSortTag, artistSort)
+}
+
+// AlbumSort returns the first ALBUMSORT value.
+func (block *FLACMetadataBlockVorbisComment) AlbumSort() string {
+	return block.firstComment(AlbumSortTag)
+}
+
+// SetAlbumSort replaces the ALBUMSORT values, removing them if albumSort is empty.
+func (block *FLACMetadataBlockVorbisComment) SetAlbumSort(albumSort string) {
+	block.setTag(AlbumSortTag, albumSort)
+}
+
+// AlbumArtistSort returns the first ALBUMARTISTSORT value.
+func (block *FLACMetadataBlockVorbisComment) AlbumArtistSort() string {
+	return block.firstComment(AlbumArtistSortTag)
+}
+
+// SetAlbumArtistSort replaces the ALBUMARTISTSORT values, removing them if albumArtistSort is empty.
+func (block *FLACMetadataBlockVorbisComment) SetAlbumArtistSort(albumArtistSort string) {
+	block.setTag(AlbumArtistSortTag, albumArtistSort)
+}
+
+// ComposerSort returns the first COMPOSERSORT value.
+func (block *FLACMetadataBlockVorbisComment) ComposerSort() string {
+	return block.firstComment(ComposerSortTag)
+}
+
+// SetComposerSort replaces the COMPOSERSORT values, removing them if composerSort is empty.
+func (block *FLACMetadataBlockVorbisComment) SetComposerSort(composerSort string) {
+	block.setTag(ComposerSortTag, composerSort)
+}
+
+// SortNamer derives the form a name is sorted by. Person reports whether the name is a person's rather
+// than a title or group. Implementations carry the conventions of a locale.
+type SortNamer interface {
+	SortName(name string, person bool) string
+}
+
+// ArticleSortNamer moves a leading article to the end, as in "Beatles, The", and writes people's names
+// as "Last, First". Articles are matched case-insensitively and include any trailing space or apostrophe,
+// such as "The " or "L'".
+type ArticleSortNamer struct {
+	Articles []string
+}
+
+// EnglishSortNamer sorts names by English conventions.
+var EnglishSortNamer SortNamer = ArticleSortNamer{[]string{"The ", "A ", "An "}}
+
+func (namer ArticleSortNamer) SortName(name string, person bool) string {
+	name = strings.TrimSpace(name)
+
+	for _, article := range namer.Articles {
+		if len(name) > len(article) && strings.EqualFold(name[:len(article)], article) {
+			return name[len(article):] + ", " + strings.TrimSpace(name[:len(article)])
+		}
+	}
+
+	if index := strings.LastIndexByte(name, ' '); person && index > 0 && !strings.Contains(name, ",") {
+		return name[index + 1:] + ", " + name[:index]
+	}
+
+	return name
+}
+
+// GenerateSortTags fills in missing TITLESORT, ARTISTSORT, ALBUMSORT, ALBUMARTISTSORT and COMPOSERSORT fields
+// from the fields they sort, using namer, and returns the number of fields written. Composers are treated as
+// people, and artists too when people is set. Sort fields that would match the field they sort are left out.
+func (block *FLACMetadataBlockVorbisComment) GenerateSortTags(namer SortNamer, people bool) (written int) {
+	sources := []struct {
+		key string
+		sortKey string
+		person bool
+	}{
+		{TitleTag, TitleSortTag, false},
+		{ArtistTag, ArtistSortTag, people},
+		{AlbumTag, AlbumSortTag, false},
+		{AlbumArtistTag, AlbumArtistSortTag, people},
+		{ComposerTag, ComposerSortTag, true},
+	}
+
+	for _, source := range sources {
+		values := block.GetTag(source.key)
+
+		if len(values) == 0 || len(block.GetTag(source.sortKey)) > 0 {
+			continue
+		}
+
+		sortNames := make([]string, len(values))
+		differs := false
+
+		for index, value := range values {
+			sortNames[index] = namer.SortName(value, source.person)
+			differs = differs || sortNames[index] != value
+		}
+
+		if differs {
+			block.SetTag(source.sortKey, sortNames...)
+			written++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,36 @@
+package flac
+
+func (suite *FLACTestSuite) TestSortName() {
+	suite.assert.Equal("Beatles, The", EnglishSortNamer.SortName("The Beatles", false))
+	suite.assert.Equal("Beatles, the", EnglishSortNamer.SortName("the Beatles", true))
+	suite.assert.Equal("Theory", EnglishSortNamer.SortName("Theory", false))
+	suite.assert.Equal("Smith, John Paul", EnglishSortNamer.SortName("John Paul Smith", true))
+	suite.assert.Equal("John Paul Smith", EnglishSortNamer.SortName("John Paul Smith", false))
+	suite.assert.Equal("Smith, John", EnglishSortNamer.SortName("Smith, John", true))
+	suite.assert.Equal("Madonna", EnglishSortNamer.SortName("Madonna", true))
+
+	french := ArticleSortNamer{[]string{"Les ", "Le ", "La ", "L'"}}
+
+	suite.assert.Equal("Rita Mitsouko, Les", french.SortName("Les Rita Mitsouko", false))
+	suite.assert.Equal("Orchestre, L'", french.SortName("L'Orchestre", false))
+}
+
+func (suite *FLACTestSuite) TestGenerateSortTags() {
+	comments := NewVorbisComment("go-flac")
+
+	comments.SetTitle("A Day in the Life")
+	comments.SetArtist("The Beatles")
+	comments.SetAlbum("Sgt. Pepper")
+	comments.SetComposer("John Lennon")
+	comments.AddTag(ComposerTag, "Paul McCartney")
+	comments.SetAlbumArtistSort("Custom")
+	comments.SetAlbumArtist("The Beatles")
+
+	suite.assert.Equal(3, comments.GenerateSortTags(EnglishSortNamer, false))
+	suite.assert.Equal("Day in the Life, A", comments.TitleSort())
+	suite.assert.Equal("Beatles, The", comments.ArtistSort())
+	suite.assert.Equal("", comments.AlbumSort())
+	suite.assert.Equal("Custom", comments.AlbumArtistSort())
+	suite.assert.Equal([]string{"Lennon, John", "McCartney, Paul"}, comments.GetTag(ComposerSortTag))
+	suite.assert.Equal(0, comments.GenerateSortTags(EnglishSortNamer, true))
+}
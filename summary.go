@@ -0,0 +1,85 @@
+package flac
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// Summary gathers the commonly needed information about a FLAC file in a single structure.
+type Summary struct {
+	TrackInfo
+	HasArtwork bool
+	HasFrontCover bool
+	FrontCoverWidth uint32
+	FrontCoverHeight uint32
+	HasCueSheet bool
+	HasSeekTable bool
+	Vendor string
+	Warnings []string
+}
+
+// ExtractAll parses the metadata of the file at path, without reading picture data, and returns a Summary of it.
+// The MD5 signatures of its Artwork are therefore empty.
+func ExtractAll(path string) (summary *Summary, err error) {
+	flac, _, err := ParseMetadata(path, WithLazyPictures())
+
+	if err != nil {
+		return
+	}
+
+	summary = flac.Summary()
+
+	return
+}
+
+// Summary builds a Summary from the parsed metadata. Its warnings start with those recorded while parsing.
+func (flac *FLAC) Summary() *Summary {
+	summary := &Summary{
+		TrackInfo: *flac.TrackInfo(),
+	}
+
+	for _, warning := range flac.Warnings {
+		summary.Warnings = append(summary.Warnings, warning.Message)
+	}
+
+	summary.HasArtwork = len(summary.Artwork) > 0
+
+	if cover := flac.FrontCover(); cover != nil {
+		summary.HasFrontCover = true
+		summary.FrontCoverWidth = cover.Width
+		summary.FrontCoverHeight = cover.Height
+	}
+
+	summary.HasCueSheet = flac.CueSheet() != nil
+	summary.HasSeekTable = flac.SeekTable() != nil
+
+	if flac.StreamInfo != nil {
+		if flac.StreamInfo.NumSamples == 0 {
+			summary.Warnings = append(summary.Warnings, "total number of samples is unknown")
+		}
+
+		if bytes.Equal(flac.StreamInfo.UnencodedMD5, make([]byte, 16)) {
+			summary.Warnings = append(summary.Warnings, "audio MD5 signature is unset")
+		}
+	}
+
+	comments := flac.VorbisComment()
+
+	if comments == nil {
+		summary.Warnings = append(summary.Warnings, "no vorbis comment block")
+	} else {
+		summary.Vendor = comments.VendorString
+
+		for _, comment := range comments.Comments {
+			if !utf8.ValidString(comment.Value) {
+				summary.Warnings = append(summary.Warnings, "comment " + comment.Key + " is not valid UTF-8")
+			}
+		}
+	}
+
+	if summary.HasArtwork && !summary.HasFrontCover {
+		summary.Warnings = append(summary.Warnings, "pictures present but no front cover")
+	}
+
+	return summary
+}
@@ -0,0 +1,27 @@
+package flac
+
+func (suite *FLACTestSuite) TestExtractAll() {
+	summary, err := ExtractAll("sample.flac")
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(88200, summary.SampleRate)
+	suite.assert.True(summary.HasArtwork)
+	suite.assert.True(summary.HasFrontCover)
+	suite.assert.Equal(2448, summary.FrontCoverWidth)
+	suite.assert.Equal(3264, summary.FrontCoverHeight)
+	suite.assert.True(summary.HasCueSheet)
+	suite.assert.True(summary.HasSeekTable)
+	suite.assert.Equal("reference libFLAC 1.1.4 20070213", summary.Vendor)
+	suite.assert.Equal(0, len(summary.Warnings))
+	suite.assert.Equal(int(suite.flac.FrontCover().PictureLength), summary.Artwork[0].Size)
+
+	flac := suite.flac.Clone()
+
+	flac.Warnings = append(flac.Warnings, Warning{Type: Picture, Message: "picture skipped"})
+
+	suite.assert.Equal([]string{"picture skipped"}, flac.Summary().Warnings)
+
+	_, err = ExtractAll("missing.flac")
+
+	suite.assert.Error(err)
+}
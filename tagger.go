@@ -0,0 +1,230 @@
+package flac
+
+import (
+	"os"
+	"fmt"
+	"bytes"
+	"strings"
+)
+
+// Tagger reads and writes the tags and front cover of a FLAC file without exposing metadata blocks or padding.
+// Fields are vorbis comment names such as TitleTag, matched case-insensitively. FLAC returns the underlying
+// metadata for anything Tagger does not cover.
+type Tagger struct {
+	flac *FLAC
+}
+
+// Open parses the file at path and returns a Tagger for it. Picture data is only read when it is needed.
+// The file is held open until Close is called.
+func Open(path string) (tagger *Tagger, err error) {
+	flac, err := Parse(path, WithLazyPictures())
+
+	if err != nil {
+		return
+	}
+
+	tagger = &Tagger{
+		flac: flac,
+	}
+
+	return
+}
+
+// FLAC returns the parsed metadata the Tagger edits.
+func (tagger *Tagger) FLAC() *FLAC {
+	return tagger.flac
+}
+
+// comments returns the vorbis comment block, appending an empty one if the file has none.
+func (tagger *Tagger) comments() (comments *FLACMetadataBlockVorbisComment, err error) {
+	comments = tagger.flac.VorbisComment()
+
+	if comments != nil {
+		return
+	}
+
+	comments = NewVorbisComment("")
+	err = tagger.flac.AppendBlock(comments)
+
+	return
+}
+
+// Get returns the first value of field, or an empty string if it is not set.
+func (tagger *Tagger) Get(field string) string {
+	values := tagger.GetAll(field)
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// GetAll returns every value of field in order.
+func (tagger *Tagger) GetAll(field string) []string {
+	comments := tagger.flac.VorbisComment()
+
+	if comments == nil {
+		return nil
+	}
+
+	return comments.GetTag(field)
+}
+
+// Set replaces the values of field. Setting no values deletes the field.
+func (tagger *Tagger) Set(field string, values ...string) (err error) {
+	comments, err := tagger.comments()
+
+	if err != nil {
+		return
+	}
+
+	return comments.SetTag(field, values...)
+}
+
+// Add appends value to the values of field.
+func (tagger *Tagger) Add(field string, value string) (err error) {
+	comments, err := tagger.comments()
+
+	if err != nil {
+		return
+	}
+
+	return comments.AddTag(field, value)
+}
+
+// Delete removes every value of field.
+func (tagger *Tagger) Delete(field string) {
+	if comments := tagger.flac.VorbisComment(); comments != nil {
+		comments.RemoveTag(field)
+	}
+}
+
+// Title returns the first TITLE value.
+func (tagger *Tagger) Title() string {
+	return tagger.Get(TitleTag)
+}
+
+// SetTitle replaces the TITLE values, removing the field if title is empty.
+func (tagger *Tagger) SetTitle(title string) error {
+	return tagger.setField(TitleTag, title)
+}
+
+// Artist returns the first ARTIST value.
+func (tagger *Tagger) Artist() string {
+	return tagger.Get(ArtistTag)
+}
+
+// SetArtist replaces the ARTIST values, removing the field if artist is empty.
+func (tagger *Tagger) SetArtist(artist string) error {
+	return tagger.setField(ArtistTag, artist)
+}
+
+// Album returns the first ALBUM value.
+func (tagger *Tagger) Album() string {
+	return tagger.Get(AlbumTag)
+}
+
+// SetAlbum replaces the ALBUM values, removing the field if album is empty.
+func (tagger *Tagger) SetAlbum(album string) error {
+	return tagger.setField(AlbumTag, album)
+}
+
+// Genre returns the first GENRE value.
+func (tagger *Tagger) Genre() string {
+	return tagger.Get(GenreTag)
+}
+
+// SetGenre replaces the GENRE values, removing the field if genre is empty.
+func (tagger *Tagger) SetGenre(genre string) error {
+	return tagger.setField(GenreTag, genre)
+}
+
+// TrackNumber returns the track number and total, which are zero when unknown.
+func (tagger *Tagger) TrackNumber() (number int, total int) {
+	comments := tagger.flac.VorbisComment()
+
+	if comments == nil {
+		return
+	}
+
+	return comments.TrackNumber()
+}
+
+// SetTrackNumber sets the track number and total without padding. A zero number or total removes the field.
+func (tagger *Tagger) SetTrackNumber(number int, total int) (err error) {
+	comments, err := tagger.comments()
+
+	if err != nil {
+		return
+	}
+
+	comments.SetTrackNumber(number, total, DefaultNumberFormat)
+
+	return
+}
+
+// setField replaces the values of field with value, or removes the field when value is empty.
+func (tagger *Tagger) setField(field string, value string) error {
+	if value == "" {
+		tagger.Delete(field)
+
+		return nil
+	}
+
+	return tagger.Set(field, value)
+}
+
+// Cover returns the front cover image data and its MIME type, or nil data if there is no front cover.
+func (tagger *Tagger) Cover() (data []byte, mimeType string, err error) {
+	block := tagger.flac.FrontCover()
+
+	if block == nil {
+		return
+	}
+
+	var buffer bytes.Buffer
+
+	_, err = block.Copy(&buffer)
+
+	if err != nil {
+		return
+	}
+
+	return buffer.Bytes(), block.MIMEType, nil
+}
+
+// SetCover replaces the front cover with the image file at path.
+func (tagger *Tagger) SetCover(path string) (err error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return
+	}
+
+	mimeType := sniffMIMEType(data)
+
+	if !strings.HasPrefix(mimeType, "image/") {
+		err = fmt.Errorf("%s is not an image: detected %s", path, mimeType)
+
+		return
+	}
+
+	return tagger.flac.SetFrontCover(data, mimeType)
+}
+
+// RemoveCover removes the front cover.
+func (tagger *Tagger) RemoveCover() {
+	tagger.flac.RemovePictures(FrontCover)
+}
+
+// Save writes the changes back to the file, reusing padding where it fits and otherwise rewriting the file
+// with fresh padding.
+func (tagger *Tagger) Save() error {
+	return tagger.flac.Save()
+}
+
+// Close releases the file held open since Open.
+func (tagger *Tagger) Close() error {
+	return tagger.flac.Close()
+}
@@ -0,0 +1,53 @@
+package flac
+
+import (
+	"os"
+	"bytes"
+	"image"
+	"image/png"
+	"path/filepath"
+)
+
+func (suite *FLACTestSuite) TestTagger() {
+	path, _ := suite.copySample()
+	tagger, err := Open(path)
+
+	suite.assert.NoError(err)
+
+	var cover bytes.Buffer
+
+	suite.assert.NoError(png.Encode(&cover, image.NewGray(image.Rect(0, 0, 4, 4))))
+
+	coverPath := filepath.Join(suite.T().TempDir(), "cover.png")
+	textPath := filepath.Join(suite.T().TempDir(), "cover.txt")
+
+	suite.assert.NoError(os.WriteFile(coverPath, cover.Bytes(), 0644))
+	suite.assert.NoError(os.WriteFile(textPath, []byte("not an image"), 0644))
+
+	suite.assert.NoError(tagger.SetTitle("New Title"))
+	suite.assert.NoError(tagger.SetGenre(""))
+	suite.assert.NoError(tagger.Set("PERFORMER", "One", "Two"))
+	suite.assert.NoError(tagger.SetTrackNumber(3, 12))
+	suite.assert.Error(tagger.SetCover(textPath))
+	suite.assert.NoError(tagger.SetCover(coverPath))
+	suite.assert.NoError(tagger.Save())
+	suite.assert.NoError(tagger.Close())
+
+	tagger, err = Open(path)
+
+	suite.assert.NoError(err)
+
+	defer tagger.Close()
+
+	number, total := tagger.TrackNumber()
+	data, mimeType, err := tagger.Cover()
+
+	suite.assert.Equal("New Title", tagger.Title())
+	suite.assert.Equal("", tagger.Genre())
+	suite.assert.Equal([]string{"One", "Two"}, tagger.GetAll("performer"))
+	suite.assert.Equal(3, number)
+	suite.assert.Equal(12, total)
+	suite.assert.NoError(err)
+	suite.assert.Equal(cover.Bytes(), data)
+	suite.assert.Equal("image/png", mimeType)
+}
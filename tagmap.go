@@ -0,0 +1,374 @@
+package flac
+
+import (
+	"maps"
+	"bytes"
+	"sort"
+	"slices"
+	"errors"
+	"strings"
+)
+
+// TagTarget identifies the tagging format a TagMap is produced for.
+type TagTarget uint
+
+// Enum indicating the supported tag map targets.
+const (
+	TargetID3v24 TagTarget = iota
+	TargetVorbis
+	TargetMP4
+	TargetID3v23
+)
+
+// TagField is a single tag of a TagMap, keyed by the target format's field identifier.
+type TagField struct {
+	Key string
+	Values []string
+}
+
+// TagArtwork is an embedded picture carried over into a TagMap.
+type TagArtwork struct {
+	Type PictureType
+	MIMEType string
+	Description string
+	Data []byte
+}
+
+// TagMap is a ready-to-apply set of tags for another container format.
+type TagMap struct {
+	Target TagTarget
+	Fields []TagField
+	Artwork []TagArtwork
+}
+
+var id3v24Frames = map[string]string{
+	"TITLE": "TIT2",
+	"ARTIST": "TPE1",
+	"ALBUM": "TALB",
+	"ALBUMARTIST": "TPE2",
+	"COMPOSER": "TCOM",
+	"CONDUCTOR": "TPE3",
+	"GENRE": "TCON",
+	"DATE": "TDRC",
+	"TRACKNUMBER": "TRCK",
+	"DISCNUMBER": "TPOS",
+	"COMMENT": "COMM",
+	"LYRICS": "USLT",
+	"ISRC": "TSRC",
+	"COPYRIGHT": "TCOP",
+	"PUBLISHER": "TPUB",
+	"BPM": "TBPM",
+	"ENCODED-BY": "TENC",
+}
+
+var mp4Atoms = map[string]string{
+	"TITLE": "\xa9nam",
+	"ARTIST": "\xa9ART",
+	"ALBUM": "\xa9alb",
+	"ALBUMARTIST": "aART",
+	"COMPOSER": "\xa9wrt",
+	"GENRE": "\xa9gen",
+	"DATE": "\xa9day",
+	"TRACKNUMBER": "trkn",
+	"DISCNUMBER": "disk",
+	"COMMENT": "\xa9cmt",
+	"LYRICS": "\xa9lyr",
+	"COPYRIGHT": "cprt",
+	"BPM": "tmpo",
+	"ENCODED-BY": "\xa9too",
+	"GROUPING": "\xa9grp",
+	"COMPILATION": "cpil",
+	"TITLESORT": "sonm",
+	"ARTISTSORT": "soar",
+	"ALBUMSORT": "soal",
+	"ALBUMARTISTSORT": "soaa",
+	"COMPOSERSORT": "soco",
+	"MUSICBRAINZ_TRACKID": "----:com.apple.iTunes:MusicBrainz Track Id",
+	"MUSICBRAINZ_RELEASETRACKID": "----:com.apple.iTunes:MusicBrainz Release Track Id",
+	"MUSICBRAINZ_ALBUMID": "----:com.apple.iTunes:MusicBrainz Album Id",
+	"MUSICBRAINZ_ARTISTID": "----:com.apple.iTunes:MusicBrainz Artist Id",
+	"MUSICBRAINZ_ALBUMARTISTID": "----:com.apple.iTunes:MusicBrainz Album Artist Id",
+	"MUSICBRAINZ_RELEASEGROUPID": "----:com.apple.iTunes:MusicBrainz Release Group Id",
+	"MUSICBRAINZ_WORKID": "----:com.apple.iTunes:MusicBrainz Work Id",
+	"RELEASESTATUS": "----:com.apple.iTunes:MusicBrainz Album Status",
+	"RELEASETYPE": "----:com.apple.iTunes:MusicBrainz Album Type",
+	"RELEASECOUNTRY": "----:com.apple.iTunes:MusicBrainz Album Release Country",
+	"ACOUSTID_ID": "----:com.apple.iTunes:Acoustid Id",
+	"BARCODE": "----:com.apple.iTunes:BARCODE",
+	"CATALOGNUMBER": "----:com.apple.iTunes:CATALOGNUMBER",
+}
+
+// totalKeys maps the number fields to the comments holding their totals.
+var totalKeys = map[string][]string{
+	"TRACKNUMBER": {"TRACKTOTAL", "TOTALTRACKS"},
+	"DISCNUMBER": {"DISCTOTAL", "TOTALDISCS"},
+}
+
+// tagFields returns a copy of the default field table for the target, or nil for an unknown target.
+func tagFields(target TagTarget) (fields map[string]string) {
+	switch target {
+		case TargetID3v24:
+			fields = maps.Clone(id3v24Frames)
+
+		case TargetID3v23:
+			fields = maps.Clone(id3v24Frames)
+			fields["DATE"] = "TYER"
+
+		case TargetMP4:
+			fields = maps.Clone(mp4Atoms)
+
+		case TargetVorbis:
+			fields = make(map[string]string)
+	}
+
+	return
+}
+
+// freeformPrefix returns the prefix of the target's user-defined fields, which carry comments without a dedicated field.
+func freeformPrefix(target TagTarget) string {
+	switch target {
+		case TargetID3v24, TargetID3v23:
+			return "TXXX:"
+
+		case TargetMP4:
+			return "----:com.apple.iTunes:"
+	}
+
+	return ""
+}
+
+// TagMapper converts between vorbis comments and the fields of another tagging format. Fields maps upper-cased
+// vorbis comment names to the target's field identifiers and may be edited to map nonstandard fields; names
+// without an entry are carried in the target's user-defined fields, such as TXXX frames.
+type TagMapper struct {
+	Target TagTarget
+	Fields map[string]string
+}
+
+// NewTagMapper returns a mapper for the target using the default field table.
+func NewTagMapper(target TagTarget) (mapper *TagMapper, err error) {
+	fields := tagFields(target)
+
+	if fields == nil {
+		err = errors.New("unknown tag map target")
+
+		return
+	}
+
+	mapper = &TagMapper{
+		Target: target,
+		Fields: fields,
+	}
+
+	return
+}
+
+func (mapper *TagMapper) key(name string) string {
+	if field, ok := mapper.Fields[name]; ok {
+		return field
+	}
+
+	return freeformPrefix(mapper.Target) + name
+}
+
+// name returns the vorbis comment name for a field identifier of the target, the first in sorted order
+// if several map to it.
+func (mapper *TagMapper) name(key string) string {
+	var names []string
+
+	for name, field := range mapper.Fields {
+		if field == key {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) > 0 {
+		return slices.Min(names)
+	}
+
+	if prefix := freeformPrefix(mapper.Target); prefix != "" && strings.HasPrefix(key, prefix) {
+		return strings.ToUpper(strings.TrimPrefix(key, prefix))
+	}
+
+	return strings.ToUpper(key)
+}
+
+// ExportTagMap converts the Vorbis comments and pictures into a tag map for the target format.
+func (flac *FLAC) ExportTagMap(target TagTarget) (tagMap *TagMap, err error) {
+	mapper, err := NewTagMapper(target)
+
+	if err != nil {
+		return
+	}
+
+	return mapper.Export(flac)
+}
+
+// ImportTagMap applies a tag map produced for another format, such as the frames read from an ID3v2 tag,
+// to the vorbis comments and pictures using the default field table for its target.
+func (flac *FLAC) ImportTagMap(tagMap *TagMap) (err error) {
+	mapper, err := NewTagMapper(tagMap.Target)
+
+	if err != nil {
+		return
+	}
+
+	return mapper.Import(flac, tagMap)
+}
+
+// Export converts the vorbis comments and pictures of flac into a tag map. Track and disc totals are folded
+// into "3/12" style numbers for targets other than vorbis comments. Picture data left unread by
+// WithLazyPictures is read from the file.
+func (mapper *TagMapper) Export(flac *FLAC) (tagMap *TagMap, err error) {
+	target := mapper.Target
+	tagMap = &TagMap{
+		Target: target,
+	}
+
+	if comments := flac.VorbisComment(); comments != nil {
+		values := comments.Map()
+		keys := make([]string, 0, len(values))
+
+		for key := range values {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fieldValues := values[key]
+
+			if target != TargetVorbis {
+				if key == "TRACKTOTAL" || key == "TOTALTRACKS" || key == "DISCTOTAL" || key == "TOTALDISCS" {
+					continue
+				}
+
+				if totals, ok := totalKeys[key]; ok && len(fieldValues) > 0 && !strings.Contains(fieldValues[0], "/") {
+					for _, totalKey := range totals {
+						if total, ok := values[totalKey]; ok && len(total) > 0 {
+							fieldValues = append([]string{fieldValues[0] + "/" + total[0]}, fieldValues[1:]...)
+
+							break
+						}
+					}
+				}
+			}
+
+			tagKey := mapper.key(key)
+
+			if tagKey == "TYER" {
+				fieldValues = slices.Clone(fieldValues)
+
+				for index, value := range fieldValues {
+					if len(value) > 4 {
+						fieldValues[index] = value[:4]
+					}
+				}
+			}
+
+			tagMap.Fields = append(tagMap.Fields, TagField{
+				Key: tagKey,
+				Values: fieldValues,
+			})
+		}
+	}
+
+	for _, block := range flac.Pictures() {
+		// MP4 cover art atoms can only hold JPEG and PNG images.
+		if target == TargetMP4 && block.MIMEType != "image/jpeg" && block.MIMEType != "image/png" {
+			continue
+		}
+
+		data := block.Picture
+
+		if !block.Loaded() {
+			var buffer bytes.Buffer
+
+			_, err = block.Copy(&buffer)
+
+			if err != nil {
+				tagMap = nil
+
+				return
+			}
+
+			data = buffer.Bytes()
+		}
+
+		tagMap.Artwork = append(tagMap.Artwork, TagArtwork{
+			Type: block.Type,
+			MIMEType: block.MIMEType,
+			Description: block.Description,
+			Data: data,
+		})
+	}
+
+	return
+}
+
+// Import applies the fields and artwork of tagMap to flac. Each field replaces the vorbis comment it maps to,
+// "3/12" style track and disc numbers are split into their number and total, and artwork is added as picture
+// blocks. A vorbis comment block is added if there is none.
+func (mapper *TagMapper) Import(flac *FLAC, tagMap *TagMap) (err error) {
+	comments := flac.VorbisComment()
+
+	if comments == nil {
+		comments = NewVorbisComment("")
+		err = flac.AppendBlock(comments)
+
+		if err != nil {
+			return
+		}
+	}
+
+	for _, field := range tagMap.Fields {
+		name := mapper.name(field.Key)
+		values := field.Values
+
+		if totals, ok := totalKeys[name]; ok && mapper.Target != TargetVorbis && len(values) > 0 {
+			number, total, found := strings.Cut(values[0], "/")
+
+			if found {
+				values = append([]string{strings.TrimSpace(number)}, values[1:]...)
+				err = comments.SetTag(totals[0], strings.TrimSpace(total))
+
+				if err != nil {
+					return
+				}
+			}
+		}
+
+		err = comments.SetTag(name, values...)
+
+		if err != nil {
+			return
+		}
+	}
+
+	for _, artwork := range tagMap.Artwork {
+		var picture *FLACMetadataBlockPicture
+
+		// MP4 cover art carries neither a picture type nor, reliably, a MIME type.
+		if mapper.Target == TargetMP4 && artwork.Type == Other {
+			artwork.Type = FrontCover
+		}
+
+		if artwork.MIMEType == "" {
+			artwork.MIMEType = sniffMIMEType(artwork.Data)
+		}
+
+		picture, err = NewPicture(artwork.Type, artwork.MIMEType, artwork.Description, artwork.Data)
+
+		if err != nil {
+			return
+		}
+
+		err = flac.AppendBlock(picture)
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
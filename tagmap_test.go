@@ -0,0 +1,134 @@
+package flac
+
+import (
+	"time"
+)
+
+func (suite *FLACTestSuite) TestExportTagMap() {
+	comments := suite.flac.VorbisComment()
+	comments.AddTag("title", "Song")
+	comments.AddTag("TRACKNUMBER", "3")
+	comments.AddTag("TRACKTOTAL", "12")
+
+	tagMap, err := suite.flac.ExportTagMap(TargetID3v24)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(TargetID3v24, tagMap.Target)
+	suite.assert.Equal([]TagField{
+		{Key: "TXXX:EXAMPLE", Values: []string{"fish"}},
+		{Key: "TIT2", Values: []string{"Song"}},
+		{Key: "TRCK", Values: []string{"3/12"}},
+	}, tagMap.Fields)
+	suite.assert.Equal(1, len(tagMap.Artwork))
+	suite.assert.Equal(FrontCover, tagMap.Artwork[0].Type)
+
+	tagMap, err = suite.flac.ExportTagMap(TargetMP4)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("----:com.apple.iTunes:EXAMPLE", tagMap.Fields[0].Key)
+	suite.assert.Equal("\xa9nam", tagMap.Fields[1].Key)
+
+	tagMap, err = suite.flac.ExportTagMap(TargetVorbis)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(4, len(tagMap.Fields))
+
+	_, err = suite.flac.ExportTagMap(TagTarget(42))
+
+	suite.assert.Error(err)
+
+	lazy, err := Parse("sample.flac", WithLazyPictures())
+
+	suite.assert.NoError(err)
+
+	defer lazy.Close()
+
+	tagMap, err = lazy.ExportTagMap(TargetID3v24)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(suite.flac.FrontCover().Picture, tagMap.Artwork[0].Data)
+	suite.assert.False(lazy.FrontCover().Loaded())
+}
+
+func (suite *FLACTestSuite) TestImportTagMap() {
+	flac := suite.flac.Clone()
+
+	flac.StripMetadata()
+
+	tagMap := &TagMap{
+		Target: TargetID3v23,
+		Fields: []TagField{
+			{Key: "TIT2", Values: []string{"Song"}},
+			{Key: "TRCK", Values: []string{"3/12"}},
+			{Key: "TYER", Values: []string{"2007"}},
+			{Key: "TXXX:Mood", Values: []string{"Calm"}},
+			{Key: "TXXX:MYFIELD", Values: []string{"custom"}},
+		},
+		Artwork: []TagArtwork{
+			{Type: FrontCover, MIMEType: "image/jpeg", Data: suite.flac.FrontCover().Picture},
+		},
+	}
+
+	mapper, err := NewTagMapper(TargetID3v23)
+
+	suite.assert.NoError(err)
+
+	mapper.Fields["ENCODER"] = "TSSE"
+	tagMap.Fields = append(tagMap.Fields, TagField{Key: "TSSE", Values: []string{"LAME"}})
+
+	suite.assert.NoError(mapper.Import(flac, tagMap))
+
+	comments := flac.VorbisComment()
+
+	suite.assert.Equal("Song", comments.Title())
+	suite.assert.Equal(PartialDate{Year: 2007}, comments.Date())
+	suite.assert.Equal([]string{"Calm"}, comments.GetTag("MOOD"))
+	suite.assert.Equal([]string{"LAME"}, comments.GetTag("ENCODER"))
+
+	number, total := comments.TrackNumber()
+
+	suite.assert.Equal(3, number)
+	suite.assert.Equal(12, total)
+	suite.assert.Equal(2448, flac.FrontCover().Width)
+
+	comments.SetDate(PartialDate{2007, time.February, 13})
+
+	exported, err := mapper.Export(flac)
+
+	suite.assert.NoError(err)
+
+	suite.assert.Contains(exported.Fields, TagField{Key: "TYER", Values: []string{"2007"}})
+	suite.assert.Contains(exported.Fields, TagField{Key: "TRCK", Values: []string{"3/12"}})
+	suite.assert.Contains(exported.Fields, TagField{Key: "TSSE", Values: []string{"LAME"}})
+	suite.assert.Contains(exported.Fields, TagField{Key: "TXXX:MYFIELD", Values: []string{"custom"}})
+
+	_, err = NewTagMapper(TagTarget(42))
+
+	suite.assert.Error(err)
+	suite.assert.Error(flac.ImportTagMap(&TagMap{Target: TagTarget(42)}))
+}
+
+func (suite *FLACTestSuite) TestMP4TagMap() {
+	comments := suite.flac.VorbisComment()
+
+	comments.AddTag("MUSICBRAINZ_TRACKID", "0a1b2c")
+	comments.AddTag("ARTISTSORT", "Someone, The")
+
+	tagMap, err := suite.flac.ExportTagMap(TargetMP4)
+
+	suite.assert.NoError(err)
+	suite.assert.Contains(tagMap.Fields, TagField{Key: "----:com.apple.iTunes:MusicBrainz Track Id", Values: []string{"0a1b2c"}})
+	suite.assert.Contains(tagMap.Fields, TagField{Key: "soar", Values: []string{"Someone, The"}})
+	suite.assert.Equal(1, len(tagMap.Artwork))
+
+	flac := suite.flac.Clone()
+
+	flac.StripMetadata()
+	tagMap.Artwork[0].Type = Other
+	tagMap.Artwork[0].MIMEType = ""
+
+	suite.assert.NoError(flac.ImportTagMap(tagMap))
+	suite.assert.Equal([]string{"0a1b2c"}, flac.VorbisComment().GetTag("MUSICBRAINZ_TRACKID"))
+	suite.assert.Equal([]string{"fish"}, flac.VorbisComment().GetTag("EXAMPLE"))
+	suite.assert.Equal("image/jpeg", flac.FrontCover().MIMEType)
+}
@@ -0,0 +1,292 @@
+package flac
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Vorbis comment field names from the Xiph recommendations, along with those in common use by taggers.
+const (
+	TitleTag = "TITLE"
+	VersionTag = "VERSION"
+	AlbumTag = "ALBUM"
+	TrackNumberTag = "TRACKNUMBER"
+	TrackTotalTag = "TRACKTOTAL"
+	TotalTracksTag = "TOTALTRACKS"
+	DiscNumberTag = "DISCNUMBER"
+	DiscTotalTag = "DISCTOTAL"
+	TotalDiscsTag = "TOTALDISCS"
+	ArtistTag = "ARTIST"
+	AlbumArtistTag = "ALBUMARTIST"
+	PerformerTag = "PERFORMER"
+	ComposerTag = "COMPOSER"
+	ConductorTag = "CONDUCTOR"
+	CopyrightTag = "COPYRIGHT"
+	LicenseTag = "LICENSE"
+	OrganizationTag = "ORGANIZATION"
+	DescriptionTag = "DESCRIPTION"
+	CommentTag = "COMMENT"
+	GenreTag = "GENRE"
+	DateTag = "DATE"
+	YearTag = "YEAR"
+	OriginalDateTag = "ORIGINALDATE"
+	OriginalYearTag = "ORIGINALYEAR"
+	LocationTag = "LOCATION"
+	ContactTag = "CONTACT"
+	ISRCTag = "ISRC"
+	LyricsTag = "LYRICS"
+	EncodedByTag = "ENCODED-BY"
+	BPMTag = "BPM"
+	TitleSortTag = "TITLESORT"
+	ArtistSortTag = "ARTISTSORT"
+	AlbumSortTag = "ALBUMSORT"
+	AlbumArtistSortTag = "ALBUMARTISTSORT"
+	ComposerSortTag = "COMPOSERSORT"
+)
+
+// setTag replaces the field key with value, or removes it when value is empty.
+func (block *FLACMetadataBlockVorbisComment) setTag(key string, value string) {
+	if value == "" {
+		block.RemoveTag(key)
+
+		return
+	}
+
+	block.SetTag(key, value)
+}
+
+// Title returns the first TITLE value.
+func (block *FLACMetadataBlockVorbisComment) Title() string {
+	return block.firstComment(TitleTag)
+}
+
+// SetTitle replaces the TITLE values, removing them if title is empty.
+func (block *FLACMetadataBlockVorbisComment) SetTitle(title string) {
+	block.setTag(TitleTag, title)
+}
+
+// Artist returns the first ARTIST value.
+func (block *FLACMetadataBlockVorbisComment) Artist() string {
+	return block.firstComment(ArtistTag)
+}
+
+// SetArtist replaces the ARTIST values, removing them if artist is empty.
+func (block *FLACMetadataBlockVorbisComment) SetArtist(artist string) {
+	block.setTag(ArtistTag, artist)
+}
+
+// Album returns the first ALBUM value.
+func (block *FLACMetadataBlockVorbisComment) Album() string {
+	return block.firstComment(AlbumTag)
+}
+
+// SetAlbum replaces the ALBUM values, removing them if album is empty.
+func (block *FLACMetadataBlockVorbisComment) SetAlbum(album string) {
+	block.setTag(AlbumTag, album)
+}
+
+// AlbumArtist returns the first ALBUMARTIST value.
+func (block *FLACMetadataBlockVorbisComment) AlbumArtist() string {
+	return block.firstComment(AlbumArtistTag)
+}
+
+// SetAlbumArtist replaces the ALBUMARTIST values, removing them if albumArtist is empty.
+func (block *FLACMetadataBlockVorbisComment) SetAlbumArtist(albumArtist string) {
+	block.setTag(AlbumArtistTag, albumArtist)
+}
+
+// Composer returns the first COMPOSER value.
+func (block *FLACMetadataBlockVorbisComment) Composer() string {
+	return block.firstComment(ComposerTag)
+}
+
+// SetComposer replaces the COMPOSER values, removing them if composer is empty.
+func (block *FLACMetadataBlockVorbisComment) SetComposer(composer string) {
+	block.setTag(ComposerTag, composer)
+}
+
+// Genre returns the first GENRE value.
+func (block *FLACMetadataBlockVorbisComment) Genre() string {
+	return block.firstComment(GenreTag)
+}
+
+// SetGenre replaces the GENRE values, removing them if genre is empty.
+func (block *FLACMetadataBlockVorbisComment) SetGenre(genre string) {
+	block.setTag(GenreTag, genre)
+}
+
+// Copyright returns the first COPYRIGHT value.
+func (block *FLACMetadataBlockVorbisComment) Copyright() string {
+	return block.firstComment(CopyrightTag)
+}
+
+// SetCopyright replaces the COPYRIGHT values, removing them if copyright is empty.
+func (block *FLACMetadataBlockVorbisComment) SetCopyright(copyright string) {
+	block.setTag(CopyrightTag, copyright)
+}
+
+// ISRC returns the first ISRC value.
+func (block *FLACMetadataBlockVorbisComment) ISRC() string {
+	return block.firstComment(ISRCTag)
+}
+
+// SetISRC replaces the ISRC values, removing them if isrc is empty.
+func (block *FLACMetadataBlockVorbisComment) SetISRC(isrc string) {
+	block.setTag(ISRCTag, isrc)
+}
+
+// Lyrics returns the first LYRICS value.
+func (block *FLACMetadataBlockVorbisComment) Lyrics() string {
+	return block.firstComment(LyricsTag)
+}
+
+// SetLyrics replaces the LYRICS values, removing them if lyrics is empty.
+func (block *FLACMetadataBlockVorbisComment) SetLyrics(lyrics string) {
+	block.setTag(LyricsTag, lyrics)
+}
+
+// MultiValueFormat is the form Values and SetValues store fields with more than one value in. With an empty
+// Delimiter each value is a separate comment, as the Xiph recommendations suggest; otherwise values are joined
+// into a single comment with Delimiter on write and split on it on read.
+type MultiValueFormat struct {
+	Delimiter string
+}
+
+// DefaultMultiValueFormat stores each value as a separate comment.
+var DefaultMultiValueFormat = MultiValueFormat{}
+
+// Values returns the values of the field key, splitting each comment on the delimiter of format. Separate
+// comments are always read as separate values, so fields written in either form are read the same way.
+func (block *FLACMetadataBlockVorbisComment) Values(key string, format MultiValueFormat) (values []string) {
+	for _, value := range block.GetTag(key) {
+		if format.Delimiter == "" {
+			values = append(values, value)
+		} else {
+			values = append(values, strings.Split(value, format.Delimiter)...)
+		}
+	}
+
+	return
+}
+
+// SetValues replaces the field key with values stored in format, removing it if there are none.
+func (block *FLACMetadataBlockVorbisComment) SetValues(key string, values []string, format MultiValueFormat) (err error) {
+	if format.Delimiter != "" && len(values) > 0 {
+		values = []string{strings.Join(values, format.Delimiter)}
+	}
+
+	return block.SetTag(key, values...)
+}
+
+// Artists returns the ARTIST values read in format.
+func (block *FLACMetadataBlockVorbisComment) Artists(format MultiValueFormat) []string {
+	return block.Values(ArtistTag, format)
+}
+
+// SetArtists replaces the ARTIST values with artists stored in format.
+func (block *FLACMetadataBlockVorbisComment) SetArtists(artists []string, format MultiValueFormat) {
+	block.SetValues(ArtistTag, artists, format)
+}
+
+// AlbumArtists returns the ALBUMARTIST values read in format.
+func (block *FLACMetadataBlockVorbisComment) AlbumArtists(format MultiValueFormat) []string {
+	return block.Values(AlbumArtistTag, format)
+}
+
+// SetAlbumArtists replaces the ALBUMARTIST values with albumArtists stored in format.
+func (block *FLACMetadataBlockVorbisComment) SetAlbumArtists(albumArtists []string, format MultiValueFormat) {
+	block.SetValues(AlbumArtistTag, albumArtists, format)
+}
+
+// Composers returns the COMPOSER values read in format.
+func (block *FLACMetadataBlockVorbisComment) Composers(format MultiValueFormat) []string {
+	return block.Values(ComposerTag, format)
+}
+
+// SetComposers replaces the COMPOSER values with composers stored in format.
+func (block *FLACMetadataBlockVorbisComment) SetComposers(composers []string, format MultiValueFormat) {
+	block.SetValues(ComposerTag, composers, format)
+}
+
+// Genres returns the GENRE values read in format.
+func (block *FLACMetadataBlockVorbisComment) Genres(format MultiValueFormat) []string {
+	return block.Values(GenreTag, format)
+}
+
+// SetGenres replaces the GENRE values with genres stored in format.
+func (block *FLACMetadataBlockVorbisComment) SetGenres(genres []string, format MultiValueFormat) {
+	block.SetValues(GenreTag, genres, format)
+}
+
+// NumberFormat is the form SetTrackNumber and SetDiscNumber write numbers in. Combined writes the total in the
+// number field as in "3/12" rather than in a separate total field, and Digits zero pads numbers to that width.
+type NumberFormat struct {
+	Combined bool
+	Digits int
+}
+
+// DefaultNumberFormat writes totals in separate fields without padding, as the Xiph recommendations suggest.
+var DefaultNumberFormat = NumberFormat{}
+
+func (format NumberFormat) format(number int) string {
+	return fmt.Sprintf("%0*d", format.Digits, number)
+}
+
+// numberPair reads a number and its total from numberKey, which may hold "3" or "3/12", and totalKeys,
+// which take precedence for the total.
+func (block *FLACMetadataBlockVorbisComment) numberPair(numberKey string, totalKeys ...string) (number int, total int) {
+	number, total = parseNumberPair(block.firstComment(numberKey))
+
+	if separate, _ := parseNumberPair(block.firstComment(totalKeys...)); separate != 0 {
+		total = separate
+	}
+
+	return
+}
+
+// setNumberPair writes number and total in format, removing the fields of any that are zero.
+func (block *FLACMetadataBlockVorbisComment) setNumberPair(format NumberFormat, number int, total int, numberKey string, totalKeys ...string) {
+	for _, key := range totalKeys {
+		block.RemoveTag(key)
+	}
+
+	value := ""
+
+	if number > 0 {
+		value = format.format(number)
+
+		if format.Combined && total > 0 {
+			value += "/" + format.format(total)
+		}
+	}
+
+	block.setTag(numberKey, value)
+
+	if !format.Combined && total > 0 {
+		block.setTag(totalKeys[0], format.format(total))
+	}
+}
+
+// TrackNumber returns the track number and total number of tracks, reading "3/12" style TRACKNUMBER values
+// as well as TRACKTOTAL and TOTALTRACKS. Missing or unparsable values are 0.
+func (block *FLACMetadataBlockVorbisComment) TrackNumber() (number int, total int) {
+	return block.numberPair(TrackNumberTag, TrackTotalTag, TotalTracksTag)
+}
+
+// SetTrackNumber writes the track number and total in format, replacing TRACKNUMBER, TRACKTOTAL and TOTALTRACKS.
+// A zero number or total leaves that field out.
+func (block *FLACMetadataBlockVorbisComment) SetTrackNumber(number int, total int, format NumberFormat) {
+	block.setNumberPair(format, number, total, TrackNumberTag, TrackTotalTag, TotalTracksTag)
+}
+
+// DiscNumber returns the disc number and total number of discs, reading "1/2" style DISCNUMBER values
+// as well as DISCTOTAL and TOTALDISCS. Missing or unparsable values are 0.
+func (block *FLACMetadataBlockVorbisComment) DiscNumber() (number int, total int) {
+	return block.numberPair(DiscNumberTag, DiscTotalTag, TotalDiscsTag)
+}
+
+// SetDiscNumber writes the disc number and total in format, replacing DISCNUMBER, DISCTOTAL and TOTALDISCS.
+// A zero number or total leaves that field out.
+func (block *FLACMetadataBlockVorbisComment) SetDiscNumber(number int, total int, format NumberFormat) {
+	block.setNumberPair(format, number, total, DiscNumberTag, DiscTotalTag, TotalDiscsTag)
+}
@@ -0,0 +1,87 @@
+package flac
+
+func (suite *FLACTestSuite) TestTypedTags() {
+	comments := suite.flac.VorbisComment()
+
+	suite.assert.Equal("", comments.Title())
+
+	comments.SetTitle("Song")
+	comments.SetArtist("Someone")
+	comments.SetAlbum("Record")
+	comments.AddTag("artist", "Someone Else")
+
+	suite.assert.Equal("Song", comments.Title())
+	suite.assert.Equal("Someone", comments.Artist())
+	suite.assert.Equal("Record", comments.Album())
+	suite.assert.Equal([]string{"Someone", "Someone Else"}, comments.GetTag(ArtistTag))
+
+	comments.SetArtist("Another")
+
+	suite.assert.Equal([]string{"Another"}, comments.GetTag(ArtistTag))
+
+	comments.SetTitle("")
+
+	suite.assert.Empty(comments.GetTag(TitleTag))
+	suite.assert.Equal("Record", suite.flac.TrackInfo().Album)
+}
+
+func (suite *FLACTestSuite) TestTrackNumber() {
+	comments := NewVorbisComment("go-flac")
+
+	comments.AddTag(TrackNumberTag, "3/12")
+
+	number, total := comments.TrackNumber()
+
+	suite.assert.Equal(3, number)
+	suite.assert.Equal(12, total)
+
+	comments.AddTag("totaltracks", "13")
+
+	number, total = comments.TrackNumber()
+
+	suite.assert.Equal(13, total)
+
+	comments.SetTrackNumber(4, 12, DefaultNumberFormat)
+
+	suite.assert.Equal([]Comment{{TrackNumberTag, "4"}, {TrackTotalTag, "12"}}, comments.Comments)
+
+	comments.SetTrackNumber(5, 12, NumberFormat{Combined: true, Digits: 2})
+
+	suite.assert.Equal([]Comment{{TrackNumberTag, "05/12"}}, comments.Comments)
+
+	comments.SetDiscNumber(1, 0, DefaultNumberFormat)
+	comments.SetTrackNumber(0, 0, DefaultNumberFormat)
+
+	suite.assert.Equal([]Comment{{DiscNumberTag, "1"}}, comments.Comments)
+
+	number, total = comments.DiscNumber()
+
+	suite.assert.Equal(1, number)
+	suite.assert.Equal(0, total)
+}
+
+func (suite *FLACTestSuite) TestMultiValueFormat() {
+	comments := NewVorbisComment("go-flac")
+	joined := MultiValueFormat{Delimiter: "; "}
+
+	comments.SetArtists([]string{"A", "B"}, DefaultMultiValueFormat)
+
+	suite.assert.Equal([]Comment{{ArtistTag, "A"}, {ArtistTag, "B"}}, comments.Comments)
+	suite.assert.Equal([]string{"A", "B"}, comments.Artists(joined))
+
+	comments.SetArtists([]string{"A", "B", "C"}, joined)
+
+	suite.assert.Equal([]Comment{{ArtistTag, "A; B; C"}}, comments.Comments)
+	suite.assert.Equal([]string{"A; B; C"}, comments.Artists(DefaultMultiValueFormat))
+	suite.assert.Equal([]string{"A", "B", "C"}, comments.Artists(joined))
+
+	comments.AddTag(ArtistTag, "D")
+
+	suite.assert.Equal([]string{"A", "B", "C", "D"}, comments.Artists(joined))
+
+	comments.SetGenres(nil, joined)
+	comments.SetArtists(nil, joined)
+
+	suite.assert.Empty(comments.Comments)
+	suite.assert.Error(comments.SetValues("BAD=KEY", []string{"x"}, joined))
+}
@@ -0,0 +1,144 @@
+package flac
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"path/filepath"
+	"text/template"
+)
+
+// tagTemplateFuncs are the functions available to tag templates besides the text/template builtins.
+// match returns the first group of a regular expression matched against text, or the whole match if it
+// has no groups, or "" if it does not match.
+var tagTemplateFuncs = template.FuncMap{
+	"match": func(pattern string, text string) (string, error) {
+		expression, err := regexp.Compile(pattern)
+
+		if err != nil {
+			return "", err
+		}
+
+		groups := expression.FindStringSubmatch(text)
+
+		switch {
+			case groups == nil:
+				return "", nil
+
+			case len(groups) > 1:
+				return groups[1], nil
+		}
+
+		return groups[0], nil
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim": strings.TrimSpace,
+}
+
+// TagTemplate writes a vorbis comment field from a text/template evaluated against TagTemplateData, such as
+// `{{.Tag "ARTIST"}}` for ALBUMARTIST or `{{match "^([0-9]+)" .Name}}` for TRACKNUMBER. With IfMissing the field
+// is only written when it has no value. A template that produces only white space leaves the field untouched.
+type TagTemplate struct {
+	Key string
+	IfMissing bool
+	template *template.Template
+}
+
+// NewTagTemplate parses text as the template for the field key.
+func NewTagTemplate(key string, text string, ifMissing bool) (tagTemplate *TagTemplate, err error) {
+	if !validTagKey(key) {
+		err = &InvalidTagKeyError{key}
+
+		return
+	}
+
+	parsed, err := template.New(key).Funcs(tagTemplateFuncs).Option("missingkey=error").Parse(text)
+
+	if err != nil {
+		return
+	}
+
+	tagTemplate = &TagTemplate{
+		Key: key,
+		IfMissing: ifMissing,
+		template: parsed,
+	}
+
+	return
+}
+
+// TagTemplateData is what tag templates are evaluated against. Path is the path the file was parsed from,
+// Dir the name of the directory holding it and Name its file name without the extension.
+type TagTemplateData struct {
+	Path string
+	Dir string
+	Name string
+	comments *FLACMetadataBlockVorbisComment
+}
+
+// Tag returns the first value of the field key, or "" if there is none.
+func (data TagTemplateData) Tag(key string) string {
+	return data.comments.firstComment(key)
+}
+
+// Tags returns every value of the field key.
+func (data TagTemplateData) Tags(key string) []string {
+	return data.comments.GetTag(key)
+}
+
+// ApplyTagTemplates evaluates the templates in order, each seeing the fields written by those before it,
+// and returns the number of fields written. A vorbis comment block is added if there is none.
+func (flac *FLAC) ApplyTagTemplates(templates ...*TagTemplate) (written int, err error) {
+	if flac.path == "" {
+		err = errors.New("FLAC was not parsed from a file")
+
+		return
+	}
+
+	comments := flac.VorbisComment()
+
+	if comments == nil {
+		comments = NewVorbisComment("")
+		err = flac.AppendBlock(comments)
+
+		if err != nil {
+			return
+		}
+	}
+
+	data := TagTemplateData{
+		Path: flac.path,
+		Dir: filepath.Base(filepath.Dir(flac.path)),
+		Name: strings.TrimSuffix(filepath.Base(flac.path), filepath.Ext(flac.path)),
+		comments: comments,
+	}
+
+	for _, tagTemplate := range templates {
+		if tagTemplate.IfMissing && len(comments.GetTag(tagTemplate.Key)) > 0 {
+			continue
+		}
+
+		var value strings.Builder
+
+		err = tagTemplate.template.Execute(&value, data)
+
+		if err != nil {
+			return
+		}
+
+		if strings.TrimSpace(value.String()) == "" {
+			continue
+		}
+
+		err = comments.SetTag(tagTemplate.Key, value.String())
+
+		if err != nil {
+			return
+		}
+
+		written++
+	}
+
+	return
+}
@@ -0,0 +1,69 @@
+package flac
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func (suite *FLACTestSuite) TestApplyTagTemplates() {
+	source, _ := suite.copySample()
+	path := filepath.Join(filepath.Dir(source), "07 - Song.flac")
+
+	suite.assert.NoError(os.Rename(source, path))
+
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	comments := flac.VorbisComment()
+
+	comments.SetArtist("Someone")
+	comments.SetAlbumArtist("Various")
+
+	var templates []*TagTemplate
+
+	for _, definition := range [][]string{
+		{AlbumArtistTag, `{{.Tag "ARTIST"}}`},
+		{TrackNumberTag, `{{match "^0*([0-9]+)" .Name}}`},
+		{TitleTag, `{{match "^[0-9]+ - (.*)" .Name | upper}}`},
+		{"SUBTITLE", `{{match "^none" .Name}}`},
+		{CommentTag, `{{.Tag "TITLE"}} by {{.Tag "ARTIST"}}`},
+	} {
+		tagTemplate, err := NewTagTemplate(definition[0], definition[1], true)
+
+		suite.assert.NoError(err)
+
+		templates = append(templates, tagTemplate)
+	}
+
+	written, err := flac.ApplyTagTemplates(templates...)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(3, written)
+	suite.assert.Equal("Various", comments.AlbumArtist())
+
+	number, _ := comments.TrackNumber()
+
+	suite.assert.Equal(7, number)
+	suite.assert.Equal("SONG", comments.Title())
+	suite.assert.Equal([]string{"SONG by Someone"}, comments.GetTag(CommentTag))
+	suite.assert.Empty(comments.GetTag("SUBTITLE"))
+
+	_, err = NewTagTemplate("A=B", "value", false)
+
+	suite.assert.Error(err)
+
+	_, err = NewTagTemplate(TitleTag, "{{.Tag", false)
+
+	suite.assert.Error(err)
+
+	broken, err := NewTagTemplate(TitleTag, `{{match "(" .Name}}`, false)
+
+	suite.assert.NoError(err)
+
+	_, err = flac.ApplyTagTemplates(broken)
+
+	suite.assert.Error(err)
+}
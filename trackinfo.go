@@ -0,0 +1,125 @@
+package flac
+
+import (
+	"time"
+	"strings"
+	"strconv"
+	"encoding/hex"
+)
+
+// ArtworkRef describes an embedded picture without carrying its payload.
+type ArtworkRef struct {
+	Index int
+	Type PictureType
+	MIMEType string
+	Description string
+	Width uint32
+	Height uint32
+	Size int
+	MD5 string
+}
+
+// TrackInfo is a flattened snapshot of the metadata media servers care about.
+// Its fields are stable and do not expose the underlying block structures.
+type TrackInfo struct {
+	Title string
+	Artist string
+	Album string
+	AlbumArtist string
+	Composer string
+	Genre string
+	Date string
+	Comment string
+	TrackNumber int
+	TrackTotal int
+	DiscNumber int
+	DiscTotal int
+	SampleRate uint32
+	Channels uint8
+	BitsPerSample uint8
+	NumSamples uint64
+	Duration time.Duration
+	Artwork []ArtworkRef
+}
+
+// Duration returns the playing time of the stream, or zero when the number of samples is unknown.
+func (block *FLACMetadataBlockStreamInfo) Duration() time.Duration {
+	if block.SampleRate == 0 {
+		return 0
+	}
+
+	rate := uint64(block.SampleRate)
+	seconds := block.NumSamples / rate
+	remainder := block.NumSamples % rate
+
+	return time.Duration(seconds) * time.Second + time.Duration(remainder * uint64(time.Second) / rate)
+}
+
+func (block *FLACMetadataBlockVorbisComment) firstComment(keys ...string) string {
+	for _, key := range keys {
+		if values := block.GetTag(key); len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
+// parseNumberPair splits values such as "3" or "3/12" into their components.
+func parseNumberPair(value string) (number int, total int) {
+	fields := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	number, _ = strconv.Atoi(strings.TrimSpace(fields[0]))
+
+	if len(fields) == 2 {
+		total, _ = strconv.Atoi(strings.TrimSpace(fields[1]))
+	}
+
+	return
+}
+
+// TrackInfo builds a TrackInfo snapshot from the parsed metadata.
+func (flac *FLAC) TrackInfo() *TrackInfo {
+	info := &TrackInfo{}
+
+	if flac.StreamInfo != nil {
+		info.SampleRate = flac.StreamInfo.SampleRate
+		info.Channels = flac.StreamInfo.Channels
+		info.BitsPerSample = flac.StreamInfo.BitsPerSample
+		info.NumSamples = flac.StreamInfo.NumSamples
+		info.Duration = flac.StreamInfo.Duration()
+	}
+
+	if comments := flac.VorbisComment(); comments != nil {
+		info.Title = comments.firstComment(TitleTag)
+		info.Artist = comments.firstComment(ArtistTag)
+		info.Album = comments.firstComment(AlbumTag)
+		info.AlbumArtist = comments.firstComment(AlbumArtistTag, "ALBUM ARTIST")
+		info.Composer = comments.firstComment(ComposerTag)
+		info.Genre = comments.firstComment(GenreTag)
+		info.Date = comments.firstComment(DateTag, YearTag)
+		info.Comment = comments.firstComment(CommentTag, DescriptionTag)
+		info.TrackNumber, info.TrackTotal = comments.TrackNumber()
+		info.DiscNumber, info.DiscTotal = comments.DiscNumber()
+	}
+
+	for index, iBlock := range flac.MetadataBlocks {
+		block, ok := iBlock.(*FLACMetadataBlockPicture)
+
+		if !ok {
+			continue
+		}
+
+		info.Artwork = append(info.Artwork, ArtworkRef{
+			Index: index,
+			Type: block.Type,
+			MIMEType: block.MIMEType,
+			Description: block.Description,
+			Width: block.Width,
+			Height: block.Height,
+			Size: int(block.PictureLength),
+			MD5: hex.EncodeToString(block.PictureMD5),
+		})
+	}
+
+	return info
+}
@@ -0,0 +1,33 @@
+package flac
+
+import (
+	"time"
+)
+
+func (suite *FLACTestSuite) TestTrackInfo() {
+	info := suite.flac.TrackInfo()
+
+	suite.assert.Equal(88200, info.SampleRate)
+	suite.assert.Equal(2, info.Channels)
+	suite.assert.Equal(24, info.BitsPerSample)
+	suite.assert.Equal(793287, info.NumSamples)
+	suite.assert.Equal(8994183673 * time.Nanosecond, info.Duration)
+	suite.assert.Equal("", info.Title)
+	suite.assert.Equal(1, len(info.Artwork))
+	suite.assert.Equal(FrontCover, info.Artwork[0].Type)
+	suite.assert.Equal("image/jpeg", info.Artwork[0].MIMEType)
+	suite.assert.Equal(2448, info.Artwork[0].Width)
+	suite.assert.Equal("c6f3cec420be726d74ca3ccfb7461f65", info.Artwork[0].MD5)
+}
+
+func (suite *FLACTestSuite) TestParseNumberPair() {
+	number, total := parseNumberPair("3/12")
+
+	suite.assert.Equal(3, number)
+	suite.assert.Equal(12, total)
+
+	number, total = parseNumberPair(" 7 ")
+
+	suite.assert.Equal(7, number)
+	suite.assert.Equal(0, total)
+}
@@ -0,0 +1,149 @@
+package flac
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"image/jpeg"
+	"image/color"
+)
+
+// ImageTransformer rewrites images before they are embedded, for instance to enforce a cover art size policy.
+// It returns the data and MIME type to embed, which may be those it was given. Implementations must be safe
+// for concurrent use.
+type ImageTransformer interface {
+	Transform(data []byte, mimeType string) (transformed []byte, transformedType string, err error)
+}
+
+// SetImageTransformer installs the ImageTransformer applied to pictures added to this FLAC by AddPicture,
+// AddPictureFromFile, SetFrontCover and ImportPicture. Passing nil embeds images unchanged. Clones share the
+// transformer.
+func (flac *FLAC) SetImageTransformer(t ImageTransformer) {
+	flac.imageTransformer = t
+}
+
+// embedPicture creates a picture block for data after passing it through the installed ImageTransformer.
+func (flac *FLAC) embedPicture(pictureType PictureType, mimeType string, description string, data []byte) (block *FLACMetadataBlockPicture, err error) {
+	if flac.imageTransformer != nil && mimeType != URLMIMEType {
+		data, mimeType, err = flac.imageTransformer.Transform(data, mimeType)
+
+		if err != nil {
+			return
+		}
+	}
+
+	return NewPicture(pictureType, mimeType, description, data)
+}
+
+// DownscaleTransformer is an ImageTransformer shrinking images larger than MaxWidth by MaxHeight pixels to fit,
+// keeping their aspect ratio. JPEG images are re-encoded as JPEG with Quality, or jpeg.DefaultQuality if it is
+// zero, and other images as PNG. Images that fit, and those in formats without a registered decoder, are
+// left unchanged. Both bounds must be positive.
+type DownscaleTransformer struct {
+	MaxWidth int
+	MaxHeight int
+	Quality int
+}
+
+func (transformer DownscaleTransformer) Transform(data []byte, mimeType string) (transformed []byte, transformedType string, err error) {
+	if transformer.MaxWidth <= 0 || transformer.MaxHeight <= 0 {
+		err = errors.New("downscale bounds must be positive")
+
+		return
+	}
+
+	transformed, transformedType = data, mimeType
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+
+	if errors.Is(err, image.ErrFormat) {
+		err = nil
+
+		return
+	}
+
+	if err != nil || config.Width <= transformer.MaxWidth && config.Height <= transformer.MaxHeight {
+		return
+	}
+
+	if config.Width * config.Height > MaxImagePixels {
+		err = errors.New("picture dimensions exceed MaxImagePixels")
+
+		return
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return
+	}
+
+	width, height := config.Width, config.Height
+
+	if width * transformer.MaxHeight > height * transformer.MaxWidth {
+		width, height = transformer.MaxWidth, max(1, height * transformer.MaxWidth / width)
+	} else {
+		width, height = max(1, width * transformer.MaxHeight / height), transformer.MaxHeight
+	}
+
+	scaled := downscale(img, width, height)
+
+	var buffer bytes.Buffer
+
+	if format == "jpeg" {
+		quality := transformer.Quality
+
+		if quality == 0 {
+			quality = jpeg.DefaultQuality
+		}
+
+		err = jpeg.Encode(&buffer, scaled, &jpeg.Options{Quality: quality})
+		transformedType = "image/jpeg"
+	} else {
+		err = png.Encode(&buffer, scaled)
+		transformedType = "image/png"
+	}
+
+	if err != nil {
+		transformed, transformedType = nil, ""
+
+		return
+	}
+
+	transformed = buffer.Bytes()
+
+	return
+}
+
+// downscale shrinks img to width by height pixels, averaging the source pixels each destination pixel covers.
+func downscale(img image.Image, width int, height int) *image.RGBA {
+	bounds := img.Bounds()
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		top := bounds.Min.Y + y * bounds.Dy() / height
+		bottom := max(top + 1, bounds.Min.Y + (y + 1) * bounds.Dy() / height)
+
+		for x := 0; x < width; x++ {
+			left := bounds.Min.X + x * bounds.Dx() / width
+			right := max(left + 1, bounds.Min.X + (x + 1) * bounds.Dx() / width)
+
+			var r, g, b, a uint64
+
+			for sourceY := top; sourceY < bottom; sourceY++ {
+				for sourceX := left; sourceX < right; sourceX++ {
+					pr, pg, pb, pa := img.At(sourceX, sourceY).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+				}
+			}
+
+			count := uint64((bottom - top) * (right - left))
+			scaled.SetRGBA(x, y, color.RGBA{uint8(r / count >> 8), uint8(g / count >> 8), uint8(b / count >> 8), uint8(a / count >> 8)})
+		}
+	}
+
+	return scaled
+}
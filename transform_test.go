@@ -0,0 +1,81 @@
+package flac
+
+import (
+	"os"
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"path/filepath"
+)
+
+type failingTransformer struct{}
+
+func (failingTransformer) Transform(data []byte, mimeType string) ([]byte, string, error) {
+	return nil, "", errors.New("rejected")
+}
+
+func (suite *FLACTestSuite) TestImageTransformer() {
+	flac := suite.flac.Clone()
+
+	flac.SetImageTransformer(DownscaleTransformer{MaxWidth: 100, MaxHeight: 100})
+
+	cover := suite.flac.FrontCover()
+	block, err := flac.AddPicture(BackCover, "", "scan", cover.Picture)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("image/jpeg", block.MIMEType)
+	suite.assert.Equal([]uint32{75, 100, 24}, []uint32{block.Width, block.Height, block.ColourDepth})
+	suite.assert.Equal(block, flac.MetadataBlocks[len(flac.MetadataBlocks) - 1])
+
+	var wide, small bytes.Buffer
+
+	suite.assert.NoError(png.Encode(&wide, image.NewGray(image.Rect(0, 0, 300, 200))))
+	suite.assert.NoError(png.Encode(&small, image.NewGray(image.Rect(0, 0, 30, 20))))
+	suite.assert.NoError(flac.SetFrontCover(wide.Bytes(), ""))
+	suite.assert.Equal("image/png", flac.FrontCover().MIMEType)
+	suite.assert.Equal([]uint32{100, 66}, []uint32{flac.FrontCover().Width, flac.FrontCover().Height})
+
+	block, err = flac.AddPicture(Other, "image/png", "", small.Bytes())
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(small.Bytes(), block.Picture)
+
+	path := filepath.Join(suite.T().TempDir(), "wide.png")
+
+	suite.assert.NoError(os.WriteFile(path, wide.Bytes(), 0644))
+
+	block, err = flac.ImportPicture("4||back|300x200x8|" + path)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal([]uint32{100, 66}, []uint32{block.Width, block.Height})
+
+	unchanged := suite.flac.Clone()
+	block, err = unchanged.AddPicture(Other, "image/png", "", wide.Bytes())
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(uint32(300), block.Width)
+
+	block, err = flac.AddPicture(Artist, URLMIMEType, "", []byte("http://example.com/a.jpg"))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("http://example.com/a.jpg", string(block.Picture))
+
+	_, _, err = DownscaleTransformer{}.Transform(small.Bytes(), "image/png")
+
+	suite.assert.Error(err)
+
+	flac.SetImageTransformer(failingTransformer{})
+
+	count := len(flac.MetadataBlocks)
+	_, err = flac.AddPicture(Other, "image/png", "", small.Bytes())
+
+	suite.assert.Error(err)
+
+	_, err = flac.ImportPicture(path)
+
+	suite.assert.Error(err)
+	suite.assert.Error(flac.SetFrontCover(small.Bytes(), "image/png"))
+	suite.assert.Equal(count, len(flac.MetadataBlocks))
+	suite.assert.Equal(100, flac.FrontCover().Width)
+}
@@ -0,0 +1,165 @@
+package flac
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"encoding/binary"
+	"github.com/garfunkel/go-bitbuffer"
+)
+
+// unmarshalBinary decodes a block encoded with its 4-byte header, as produced by MarshalBinary, into block.
+// Blocks not attached to a FLAC are decoded with a detached parser state.
+func unmarshalBinary(block IFLACMetadataBlock, expected BlockType, data []byte) (err error) {
+	if len(data) < 4 {
+		err = fmt.Errorf("%w: block header needs 4 bytes, got %d", ErrTruncated, len(data))
+
+		return
+	}
+
+	header := block.header()
+	header.Last = data[0] >> 7 != 0
+	header.Type = BlockType(data[0] & 0x7f)
+	header.DataLength = uint32(data[1]) << 16 | uint32(data[2]) << 8 | uint32(data[3])
+
+	switch {
+		case header.Type == Invalid:
+			err = fmt.Errorf("%w: %d", ErrInvalidBlockType, header.Type)
+
+		case expected == Reserved && header.Type < Reserved:
+			err = fmt.Errorf("%w: %d is not a reserved block type", ErrInvalidBlockType, header.Type)
+
+		case expected != Reserved && header.Type != expected:
+			err = fmt.Errorf("%w: got %d, expected %d", ErrInvalidBlockType, header.Type, expected)
+
+		case uint64(len(data) - 4) < uint64(header.DataLength):
+			err = fmt.Errorf("%w: block data needs %d bytes, got %d", ErrTruncated, header.DataLength, len(data) - 4)
+
+		case uint64(len(data) - 4) > uint64(header.DataLength):
+			err = fmt.Errorf("%d unexpected bytes after block data", uint64(len(data) - 4) - uint64(header.DataLength))
+	}
+
+	if err != nil {
+		return
+	}
+
+	if header.FLAC == nil {
+		header.FLAC = &FLAC{
+			buffer: bitbuffer.NewBitBuffer(binary.BigEndian),
+		}
+
+		defer func() {
+			header.FLAC = nil
+		}()
+	}
+
+	err = block.parse(bytes.NewReader(data[4:]))
+
+	return
+}
+
+// UnmarshalBinary decodes a stream info block encoded with its header, such as one produced by MarshalBinary.
+func (block *FLACMetadataBlockStreamInfo) UnmarshalBinary(data []byte) error {
+	*block = FLACMetadataBlockStreamInfo{}
+
+	return unmarshalBinary(block, StreamInfo, data)
+}
+
+// UnmarshalBinary decodes a padding block encoded with its header.
+func (block *FLACMetadataBlockPadding) UnmarshalBinary(data []byte) error {
+	*block = FLACMetadataBlockPadding{}
+
+	return unmarshalBinary(block, Padding, data)
+}
+
+// UnmarshalBinary decodes an application block encoded with its header, decoding its value if a codec is registered.
+func (block *FLACMetadataBlockApplication) UnmarshalBinary(data []byte) error {
+	*block = FLACMetadataBlockApplication{}
+
+	return unmarshalBinary(block, Application, data)
+}
+
+// UnmarshalBinary decodes a seek table block encoded with its header.
+func (block *FLACMetadataBlockSeekTable) UnmarshalBinary(data []byte) error {
+	*block = FLACMetadataBlockSeekTable{}
+
+	return unmarshalBinary(block, SeekTable, data)
+}
+
+// UnmarshalBinary decodes a vorbis comment block encoded with its header.
+func (block *FLACMetadataBlockVorbisComment) UnmarshalBinary(data []byte) error {
+	*block = FLACMetadataBlockVorbisComment{}
+
+	return unmarshalBinary(block, VorbisComment, data)
+}
+
+// UnmarshalBinary decodes a cue sheet block encoded with its header.
+func (block *FLACMetadataBlockCueSheet) UnmarshalBinary(data []byte) error {
+	*block = FLACMetadataBlockCueSheet{}
+
+	return unmarshalBinary(block, CueSheet, data)
+}
+
+// UnmarshalBinary decodes a picture block encoded with its header. The picture data is always loaded.
+func (block *FLACMetadataBlockPicture) UnmarshalBinary(data []byte) error {
+	*block = FLACMetadataBlockPicture{}
+
+	return unmarshalBinary(block, Picture, data)
+}
+
+// UnmarshalBinary decodes a block of a reserved type encoded with its header.
+func (block *FLACMetadataBlockReserved) UnmarshalBinary(data []byte) error {
+	*block = FLACMetadataBlockReserved{}
+
+	return unmarshalBinary(block, Reserved, data)
+}
+
+// UnmarshalBlock decodes a block encoded with its header into the block type named by the header.
+func UnmarshalBlock(data []byte) (block IFLACMetadataBlock, err error) {
+	if len(data) == 0 {
+		err = errors.New("no block data")
+
+		return
+	}
+
+	var unmarshaler interface {
+		IFLACMetadataBlock
+		UnmarshalBinary([]byte) error
+	}
+
+	switch BlockType(data[0] & 0x7f) {
+		case StreamInfo:
+			unmarshaler = &FLACMetadataBlockStreamInfo{}
+
+		case Padding:
+			unmarshaler = &FLACMetadataBlockPadding{}
+
+		case Application:
+			unmarshaler = &FLACMetadataBlockApplication{}
+
+		case SeekTable:
+			unmarshaler = &FLACMetadataBlockSeekTable{}
+
+		case VorbisComment:
+			unmarshaler = &FLACMetadataBlockVorbisComment{}
+
+		case CueSheet:
+			unmarshaler = &FLACMetadataBlockCueSheet{}
+
+		case Picture:
+			unmarshaler = &FLACMetadataBlockPicture{}
+
+		default:
+			unmarshaler = &FLACMetadataBlockReserved{}
+	}
+
+	err = unmarshaler.UnmarshalBinary(data)
+
+	if err != nil {
+		return
+	}
+
+	block = unmarshaler
+
+	return
+}
@@ -0,0 +1,45 @@
+package flac
+
+import (
+	"os"
+)
+
+func (suite *FLACTestSuite) TestUnmarshalBinary() {
+	original, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	for block := range suite.flac.Blocks() {
+		header := block.header()
+		data := original[header.HeaderOffset:header.Offset + int64(header.DataLength)]
+		decoded, err := UnmarshalBlock(data)
+
+		suite.assert.NoError(err)
+		suite.assert.Nil(decoded.header().FLAC)
+		suite.assert.Equal(header.Type, decoded.header().Type)
+		suite.assert.Equal(header.Last, decoded.header().Last)
+		suite.assert.Equal(0, len(Diff(&FLAC{MetadataBlocks: []IFLACMetadataBlock{block}}, &FLAC{MetadataBlocks: []IFLACMetadataBlock{decoded}})))
+
+		encoded, err := decoded.marshal()
+
+		suite.assert.NoError(err)
+		suite.assert.Equal(int(header.DataLength), len(encoded))
+	}
+
+	cueSheet := &FLACMetadataBlockCueSheet{}
+
+	suite.assert.ErrorIs(cueSheet.UnmarshalBinary([]byte{byte(Padding), 0, 0, 0}), ErrInvalidBlockType)
+	suite.assert.ErrorIs(cueSheet.UnmarshalBinary([]byte{byte(CueSheet), 0, 1}), ErrTruncated)
+	suite.assert.ErrorIs(cueSheet.UnmarshalBinary([]byte{byte(CueSheet), 0, 2, 0, 0}), ErrTruncated)
+
+	reserved, err := UnmarshalBlock([]byte{0x80 | 9, 0, 0, 2, 'h', 'i'})
+
+	suite.assert.NoError(err)
+	suite.assert.IsType(&FLACMetadataBlockReserved{}, reserved)
+	suite.assert.Equal([]byte("hi"), reserved.(*FLACMetadataBlockReserved).Data)
+
+	encoded, err := reserved.(*FLACMetadataBlockReserved).MarshalBinary()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal([]byte{0x80 | 9, 0, 0, 2, 'h', 'i'}, encoded)
+}
@@ -0,0 +1,143 @@
+package flac
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Vendor is the encoder identified by a vorbis comment vendor string.
+type Vendor struct {
+	Name string
+	Version string
+}
+
+func (vendor Vendor) String() string {
+	if vendor.Version == "" {
+		return vendor.Name
+	}
+
+	return vendor.Name + " " + vendor.Version
+}
+
+// ParseVendor splits a vendor string such as "reference libFLAC 1.3.2 20170101" or "Lavf58.76.100"
+// into the encoder name and version.
+func ParseVendor(vendorString string) (vendor Vendor) {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(vendorString), "reference "))
+
+	for index, field := range fields {
+		if unicode.IsDigit(rune(field[0])) {
+			vendor.Name = strings.Join(fields[:index], " ")
+			vendor.Version = field
+
+			return
+		}
+	}
+
+	// Tools such as FFmpeg append the version directly to the name ("Lavf58.76.100").
+	if len(fields) == 1 {
+		if split := strings.IndexFunc(fields[0], unicode.IsDigit); split > 0 {
+			vendor.Name = fields[0][:split]
+			vendor.Version = fields[0][split:]
+
+			return
+		}
+	}
+
+	vendor.Name = strings.Join(fields, " ")
+
+	return
+}
+
+// DefaultVendorString, when not empty, replaces the vendor string of vorbis comment blocks as they are written,
+// identifying this library as the last to write the file. Vendor strings given to SetVendorString or
+// NewVorbisComment take precedence. When empty, the default, original vendor strings are preserved.
+var DefaultVendorString = ""
+
+// writtenVendor returns the vendor string to write for the block.
+func (block *FLACMetadataBlockVorbisComment) writtenVendor() string {
+	if block.vendorSet || DefaultVendorString == "" {
+		return block.VendorString
+	}
+
+	return DefaultVendorString
+}
+
+// SetVendorString sets the vendor string written for the vorbis comment block, overriding DefaultVendorString.
+// A vorbis comment block is added if there is none.
+func (flac *FLAC) SetVendorString(vendor string) {
+	comments := flac.VorbisComment()
+
+	if comments == nil {
+		comments = NewVorbisComment(vendor)
+		flac.AppendBlock(comments)
+	}
+
+	comments.VendorString = vendor
+	comments.vendorSet = true
+}
+
+// Vendor returns the encoder recorded in the vorbis comment block, or the zero Vendor if there is none.
+func (flac *FLAC) Vendor() Vendor {
+	comments := flac.VorbisComment()
+
+	if comments == nil {
+		return Vendor{}
+	}
+
+	return ParseVendor(comments.VendorString)
+}
+
+// VendorStats groups file paths by the encoder that produced them.
+type VendorStats map[Vendor][]string
+
+// CollectVendorStats parses each path and groups it by vendor, reporting per-file outcomes in result.
+func CollectVendorStats(paths []string) (stats VendorStats, result *BatchResult) {
+	stats = make(VendorStats)
+	result = &BatchResult{}
+
+	for _, path := range paths {
+		flac, err := Parse(path)
+
+		result.add(path, err)
+
+		if err != nil {
+			continue
+		}
+
+		vendor := flac.Vendor()
+		stats[vendor] = append(stats[vendor], path)
+
+		flac.Close()
+	}
+
+	return
+}
+
+// Vendors returns the vendors in the statistics sorted by name and version.
+func (stats VendorStats) Vendors() (vendors []Vendor) {
+	for vendor := range stats {
+		vendors = append(vendors, vendor)
+	}
+
+	sort.Slice(vendors, func(i, j int) bool {
+		if vendors[i].Name != vendors[j].Name {
+			return vendors[i].Name < vendors[j].Name
+		}
+
+		return vendors[i].Version < vendors[j].Version
+	})
+
+	return
+}
+
+// Matching returns the paths encoded by the named encoder with a version starting with versionPrefix.
+func (stats VendorStats) Matching(name string, versionPrefix string) (paths []string) {
+	for _, vendor := range stats.Vendors() {
+		if strings.EqualFold(vendor.Name, name) && strings.HasPrefix(vendor.Version, versionPrefix) {
+			paths = append(paths, stats[vendor]...)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,70 @@
+package flac
+
+func (suite *FLACTestSuite) TestParseVendor() {
+	suite.assert.Equal(Vendor{"libFLAC", "1.1.4"}, suite.flac.Vendor())
+	suite.assert.Equal(Vendor{"libFLAC", "1.3.2"}, ParseVendor("reference libFLAC 1.3.2 20170101"))
+	suite.assert.Equal(Vendor{"Lavf", "58.76.100"}, ParseVendor("Lavf58.76.100"))
+	suite.assert.Equal(Vendor{"Xiph.Org libVorbis I", "20020717"}, ParseVendor("Xiph.Org libVorbis I 20020717"))
+	suite.assert.Equal(Vendor{"Custom encoder", ""}, ParseVendor("Custom encoder"))
+	suite.assert.Equal("libFLAC 1.1.4", suite.flac.Vendor().String())
+}
+
+func (suite *FLACTestSuite) TestCollectVendorStats() {
+	stats, result := CollectVendorStats([]string{"sample.flac", "missing.flac", "sample.flac"})
+
+	suite.assert.Equal([]string{"missing.flac"}, result.Failed())
+	suite.assert.Equal([]Vendor{{"libFLAC", "1.1.4"}}, stats.Vendors())
+	suite.assert.Equal([]string{"sample.flac", "sample.flac"}, stats.Matching("libflac", "1.1"))
+	suite.assert.Equal(0, len(stats.Matching("libFLAC", "1.0")))
+}
+
+func (suite *FLACTestSuite) TestVendorString() {
+	defer func() {
+		DefaultVendorString = ""
+	}()
+
+	flac := suite.flac.Clone()
+	comments := flac.VorbisComment()
+
+	DefaultVendorString = "go-flac"
+
+	data, err := comments.MarshalBinary()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("go-flac", string(data[8:15]))
+	suite.assert.Equal("reference libFLAC 1.1.4 20070213", comments.VendorString)
+
+	flac.SetVendorString("my tagger 1.0")
+
+	data, err = comments.MarshalBinary()
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("my tagger 1.0", string(data[8:21]))
+
+	stripped := suite.flac.Clone()
+	stripped.StripMetadata()
+	stripped.SetVendorString("my tagger 1.0")
+
+	suite.assert.NotNil(stripped.VorbisComment())
+	suite.assert.Equal("my tagger 1.0", stripped.Vendor().String())
+
+	path, _ := suite.copySample()
+	saved, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer saved.Close()
+
+	suite.assert.NoError(saved.Save())
+	suite.assert.Equal("go-flac", saved.VorbisComment().VendorString)
+
+	DefaultVendorString = ""
+
+	reparsed, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer reparsed.Close()
+
+	suite.assert.Equal("go-flac", reparsed.VorbisComment().VendorString)
+}
@@ -0,0 +1,250 @@
+// Package watcher reports FLAC files being added, retagged and removed under a set of directories.
+package watcher
+
+import (
+	"os"
+	"maps"
+	"sync"
+	"time"
+	"io/fs"
+	"slices"
+	"strings"
+	"path/filepath"
+	"github.com/garfunkel/go-flac"
+)
+
+// DefaultInterval is how often directories are scanned when Options.Interval is not set.
+const DefaultInterval = 2 * time.Second
+
+// EventType identifies what happened to a file.
+type EventType uint
+
+// Enum indicating the kinds of event a Watcher sends.
+const (
+	FileAdded EventType = iota
+	TagsChanged
+	FileRemoved
+	FileFailed
+)
+
+func (eventType EventType) String() string {
+	switch eventType {
+		case FileAdded:
+			return "added"
+
+		case TagsChanged:
+			return "tags changed"
+
+		case FileRemoved:
+			return "removed"
+	}
+
+	return "failed"
+}
+
+// Event reports a change to the file at Path. Changes holds the metadata differences for TagsChanged, and Err
+// why the file could not be parsed for FileFailed.
+type Event struct {
+	Type EventType
+	Path string
+	Changes []flac.Change
+	Err error
+}
+
+// Options configures a Watcher. Interval is the time between scans, DefaultInterval if zero, and ParseOptions
+// are used for every file parsed.
+type Options struct {
+	Interval time.Duration
+	ParseOptions []flac.ParseOption
+}
+
+// Watcher polls directories for FLAC files, comparing the size and modification time of each file with the
+// previous scan and re-parsing the metadata of those that changed.
+type Watcher struct {
+	roots []string
+	options Options
+	files map[string]*watchedFile
+	events chan Event
+	done chan struct{}
+	stopped chan struct{}
+	closeOnce sync.Once
+}
+
+// watchedFile is what a Watcher knows of a file: its state when last scanned and its metadata, which is nil if
+// it could not be parsed.
+type watchedFile struct {
+	size int64
+	modTime time.Time
+	flac *flac.FLAC
+}
+
+// New scans the files with a .flac extension under roots and starts watching them. Files present now are not
+// reported; later scans send an event on Events for each file added, retagged or removed. A file that fails to
+// parse is reported with FileFailed and, once it parses, with FileAdded.
+func New(roots []string, options Options) (watcher *Watcher, err error) {
+	for _, root := range roots {
+		_, err = os.Stat(root)
+
+		if err != nil {
+			return
+		}
+	}
+
+	if options.Interval <= 0 {
+		options.Interval = DefaultInterval
+	}
+
+	watcher = &Watcher{
+		roots: roots,
+		options: options,
+		files: make(map[string]*watchedFile),
+		events: make(chan Event),
+		done: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	watcher.scan(false)
+
+	go watcher.run()
+
+	return
+}
+
+// Events returns the channel events are sent on, which is closed by Close.
+func (watcher *Watcher) Events() <-chan Event {
+	return watcher.events
+}
+
+// Close stops watching and closes the events channel. It is safe to call more than once.
+func (watcher *Watcher) Close() error {
+	watcher.closeOnce.Do(func() {
+		close(watcher.done)
+	})
+
+	<-watcher.stopped
+
+	return nil
+}
+
+// run scans every interval until the watcher is closed.
+func (watcher *Watcher) run() {
+	defer close(watcher.stopped)
+	defer close(watcher.events)
+
+	ticker := time.NewTicker(watcher.options.Interval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+			case <-watcher.done:
+				return
+
+			case <-ticker.C:
+				if !watcher.scan(true) {
+					return
+				}
+		}
+	}
+}
+
+// scan walks the roots and updates what is known of each file, sending events if notify is set. It returns
+// false if the watcher was closed while an event was waiting to be sent.
+func (watcher *Watcher) scan(notify bool) bool {
+	seen := make(map[string]bool)
+
+	for _, root := range watcher.roots {
+		// Unreadable directories are skipped, so files in a root that has gone are reported as removed.
+		filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err == nil && entry.Type().IsRegular() && strings.EqualFold(filepath.Ext(path), ".flac") {
+				seen[path] = true
+			}
+
+			return nil
+		})
+	}
+
+	var events []Event
+
+	for _, path := range slices.Sorted(maps.Keys(seen)) {
+		if event, ok := watcher.update(path); ok {
+			events = append(events, event)
+		}
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(watcher.files)) {
+		if !seen[path] {
+			delete(watcher.files, path)
+
+			events = append(events, Event{
+				Type: FileRemoved,
+				Path: path,
+			})
+		}
+	}
+
+	if !notify {
+		return true
+	}
+
+	for _, event := range events {
+		select {
+			case watcher.events <- event:
+
+			case <-watcher.done:
+				return false
+		}
+	}
+
+	return true
+}
+
+// update compares the file at path with what is known of it, re-parsing it if it changed, and returns the
+// event to send, if any.
+func (watcher *Watcher) update(path string) (event Event, ok bool) {
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return
+	}
+
+	known := watcher.files[path]
+
+	if known != nil && known.size == info.Size() && known.modTime.Equal(info.ModTime()) {
+		return
+	}
+
+	event.Path = path
+	ok = true
+
+	if known == nil || known.flac == nil {
+		parsed, _, err := flac.ParseMetadata(path, watcher.options.ParseOptions...)
+
+		if err != nil {
+			parsed = nil
+			event.Type, event.Err = FileFailed, err
+		} else {
+			event.Type = FileAdded
+		}
+
+		watcher.files[path] = &watchedFile{info.Size(), info.ModTime(), parsed}
+
+		return
+	}
+
+	known.size, known.modTime = info.Size(), info.ModTime()
+	previous := known.flac.Clone()
+	_, err = known.flac.Refresh()
+
+	if err != nil {
+		known.flac = nil
+		event.Type, event.Err = FileFailed, err
+
+		return
+	}
+
+	event.Type, event.Changes = TagsChanged, flac.Diff(previous, known.flac)
+	ok = len(event.Changes) > 0
+
+	return
+}
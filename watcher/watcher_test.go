@@ -0,0 +1,84 @@
+package watcher
+
+import (
+	"os"
+	"time"
+	"testing"
+	"path/filepath"
+	"github.com/garfunkel/go-flac"
+	"github.com/garfunkel/go-flac/flactest"
+	"github.com/stretchr/testify/assert"
+)
+
+// next waits for the next event, failing the test if none arrives in time.
+func next(t *testing.T, watcher *Watcher) (event Event) {
+	t.Helper()
+
+	select {
+		case event = <-watcher.Events():
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event received")
+	}
+
+	return
+}
+
+func TestWatcher(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.flac")
+	added := filepath.Join(dir, "sub", "added.flac")
+
+	assert.NoError(flactest.WriteFile(existing, flactest.Spec{Samples: 4096, Blocks: []flac.IFLACMetadataBlock{flac.NewPadding(1024)}}))
+
+	watcher, err := New([]string{dir}, Options{Interval: 10 * time.Millisecond})
+
+	assert.NoError(err)
+
+	defer watcher.Close()
+
+	assert.NoError(os.Mkdir(filepath.Dir(added), 0755))
+	// Written under another name first so a scan never sees it half written.
+	assert.NoError(flactest.WriteFile(added + ".tmp", flactest.Spec{Samples: 100}))
+	assert.NoError(os.Rename(added + ".tmp", added))
+	assert.Equal(Event{Type: FileAdded, Path: added}, next(t, watcher))
+
+	parsed, err := flac.Parse(existing)
+
+	assert.NoError(err)
+	assert.NoError(parsed.AppendBlock(flac.NewVorbisComment("")))
+	assert.NoError(parsed.VorbisComment().SetTag(flac.TitleTag, "Watched"))
+	assert.NoError(parsed.Save())
+	assert.NoError(parsed.Close())
+
+	event := next(t, watcher)
+
+	assert.Equal(TagsChanged, event.Type)
+	assert.Equal(existing, event.Path)
+	assert.Contains(event.Changes, flac.Change{Kind: flac.Added, Type: flac.VorbisComment})
+
+	assert.NoError(os.Remove(added))
+	assert.Equal(Event{Type: FileRemoved, Path: added}, next(t, watcher))
+
+	broken := filepath.Join(dir, "broken.flac")
+
+	assert.NoError(os.WriteFile(broken, []byte("not a flac file"), 0644))
+
+	event = next(t, watcher)
+
+	assert.Equal(FileFailed, event.Type)
+	assert.Equal(broken, event.Path)
+	assert.Error(event.Err)
+
+	assert.NoError(watcher.Close())
+
+	_, open := <-watcher.Events()
+
+	assert.False(open)
+}
+
+func TestNewMissingRoot(t *testing.T) {
+	_, err := New([]string{filepath.Join(t.TempDir(), "missing")}, Options{})
+
+	assert.New(t).Error(err)
+}
@@ -0,0 +1,1010 @@
+package flac
+
+import (
+	"io"
+	"os"
+	"bytes"
+	"time"
+	"io/fs"
+	"slices"
+	"fmt"
+	"errors"
+	"path/filepath"
+	"hash"
+	"crypto/md5"
+	"encoding/binary"
+)
+
+// maxBlockLength is the largest data length that fits in a metadata block header.
+const maxBlockLength = 1 << 24 - 1
+
+// ErrInsufficientPadding is returned by Save with WithInPlaceOnly when the edited metadata does not fit in the
+// space taken by the original metadata and its padding.
+var ErrInsufficientPadding = errors.New("metadata does not fit in the available padding")
+
+// ErrBlockTooLarge is returned, wrapped, when a block's data does not fit in the 24-bit length of its header.
+var ErrBlockTooLarge = errors.New("metadata block exceeds the maximum block length")
+
+func appendUint24(data []byte, value uint32) []byte {
+	return append(data, byte(value >> 16), byte(value >> 8), byte(value))
+}
+
+// appendPadded appends value truncated or NUL padded to length bytes.
+func appendPadded(data []byte, value string, length int) []byte {
+	field := make([]byte, length)
+
+	copy(field, value)
+
+	return append(data, field...)
+}
+
+// sourceData returns the original data of the block, from RawData when it was retained or else read back
+// from the file the metadata was parsed from.
+func (block *FLACMetadataBlock) sourceData() (data []byte, err error) {
+	if block.RawData != nil {
+		return block.RawData, nil
+	}
+
+	if block.FLAC == nil || block.FLAC.path == "" {
+		err = errors.New("block data was not retained and its source is unknown")
+
+		return
+	}
+
+	file := block.FLAC.file
+
+	if file == nil {
+		file, err = block.FLAC.open()
+
+		if err != nil {
+			return
+		}
+
+		defer file.Close()
+	}
+
+	readerAt, ok := file.(io.ReaderAt)
+
+	if !ok {
+		err = errors.New("block source does not support random access")
+
+		return
+	}
+
+	data = make([]byte, block.DataLength)
+	_, err = readerAt.ReadAt(data, block.Offset)
+
+	return
+}
+
+func (block *FLACMetadataBlockStreamInfo) marshal() (data []byte, err error) {
+	switch {
+		case len(block.UnencodedMD5) != 16:
+			err = errors.New("stream info MD5 signature must be 16 bytes")
+
+		case block.SampleRate >= 1 << 20:
+			err = fmt.Errorf("invalid sample rate %d", block.SampleRate)
+
+		case block.Channels == 0 || block.Channels > 8:
+			err = fmt.Errorf("invalid channel count %d, expected 1 to 8", block.Channels)
+
+		case block.BitsPerSample < 4 || block.BitsPerSample > 32:
+			err = fmt.Errorf("invalid bits per sample %d, expected 4 to 32", block.BitsPerSample)
+
+		case block.NumSamples >= 1 << 36:
+			err = errors.New("total samples does not fit in 36 bits")
+	}
+
+	if err != nil {
+		return
+	}
+
+	data = binary.BigEndian.AppendUint16(data, block.MinBlockSize)
+	data = binary.BigEndian.AppendUint16(data, block.MaxBlockSize)
+	data = appendUint24(data, block.MinFrameSize)
+	data = appendUint24(data, block.MaxFrameSize)
+	data = binary.BigEndian.AppendUint64(data, uint64(block.SampleRate) << 44 | uint64(block.Channels - 1) << 41 |
+		uint64(block.BitsPerSample - 1) << 36 | block.NumSamples & (1 << 36 - 1))
+	data = append(data, block.UnencodedMD5...)
+
+	return
+}
+
+func (block *FLACMetadataBlockPadding) marshal() (data []byte, err error) {
+	return make([]byte, block.NumBytes), nil
+}
+
+func (block *FLACMetadataBlockApplication) marshal() (data []byte, err error) {
+	if len(block.AppID) != 4 {
+		err = errors.New("application ID must be 4 bytes")
+
+		return
+	}
+
+	appData, err := block.appData()
+
+	if err != nil {
+		return
+	}
+
+	data = append([]byte(block.AppID), appData...)
+
+	return
+}
+
+func (block *FLACMetadataBlockSeekTable) marshal() (data []byte, err error) {
+	for _, point := range block.SeekPoints {
+		data = binary.BigEndian.AppendUint64(data, point.Sample)
+		data = binary.BigEndian.AppendUint64(data, point.ByteOffset)
+		data = binary.BigEndian.AppendUint16(data, point.NumSamples)
+	}
+
+	return
+}
+
+func (block *FLACMetadataBlockVorbisComment) marshal() (data []byte, err error) {
+	if violations := block.ValidateTags(); len(violations) > 0 {
+		err = &InvalidTagsError{violations}
+
+		return
+	}
+
+	vendor := block.writtenVendor()
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(vendor)))
+	data = append(data, vendor...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(block.Comments)))
+
+	for _, comment := range block.Comments {
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(comment.Key) + 1 + len(comment.Value)))
+		data = append(data, comment.Key...)
+		data = append(data, '=')
+		data = append(data, comment.Value...)
+	}
+
+	return
+}
+
+func (block *FLACMetadataBlockCueSheet) marshal() (data []byte, err error) {
+	if len(block.CueSheetTracks) > 255 {
+		err = errors.New("cue sheet has more than 255 tracks")
+
+		return
+	}
+
+	var flags byte
+
+	if block.IsCD {
+		flags = 0x80
+	}
+
+	data = appendPadded(data, block.MediaCatalogNumber, 128)
+	data = binary.BigEndian.AppendUint64(data, block.NumLeadInSamples)
+	data = append(data, flags)
+	data = append(data, make([]byte, 258)...)
+	data = append(data, byte(len(block.CueSheetTracks)))
+
+	for _, track := range block.CueSheetTracks {
+		if len(track.CueSheetTrackIndices) > 255 {
+			err = errors.New("cue sheet track has more than 255 index points")
+
+			return
+		}
+
+		flags = 0
+
+		if !track.IsAudio {
+			flags |= 0x80
+		}
+
+		if track.PreEmphasis {
+			flags |= 0x40
+		}
+
+		data = binary.BigEndian.AppendUint64(data, track.Offset)
+		data = append(data, track.Track)
+		data = appendPadded(data, track.ISRC, 12)
+		data = append(data, flags)
+		data = append(data, make([]byte, 13)...)
+		data = append(data, byte(len(track.CueSheetTrackIndices)))
+
+		for _, index := range track.CueSheetTrackIndices {
+			data = binary.BigEndian.AppendUint64(data, index.Offset)
+			data = append(data, index.IndexNumber, 0, 0, 0)
+		}
+	}
+
+	return
+}
+
+func (block *FLACMetadataBlockPicture) marshal() (data []byte, err error) {
+	picture := block.Picture
+
+	if !block.Loaded() {
+		reader := block.Open()
+		picture, err = io.ReadAll(reader)
+		closeReader(reader)
+
+		if err != nil {
+			return
+		}
+	}
+
+	data = binary.BigEndian.AppendUint32(data, uint32(block.Type))
+	data = binary.BigEndian.AppendUint32(data, uint32(len(block.MIMEType)))
+	data = append(data, block.MIMEType...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(block.Description)))
+	data = append(data, block.Description...)
+	data = binary.BigEndian.AppendUint32(data, block.Width)
+	data = binary.BigEndian.AppendUint32(data, block.Height)
+	data = binary.BigEndian.AppendUint32(data, block.ColourDepth)
+	data = binary.BigEndian.AppendUint32(data, block.NumColours)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(picture)))
+	data = append(data, picture...)
+
+	return
+}
+
+func (block *FLACMetadataBlockReserved) marshal() (data []byte, err error) {
+	return block.Data, nil
+}
+
+func (block *FLACMetadataBlockSkipped) marshal() (data []byte, err error) {
+	return block.FLACMetadataBlock.sourceData()
+}
+
+// appendBlock appends the header and data of block, returning the data length.
+func appendBlock(data []byte, block IFLACMetadataBlock, last bool) (encoded []byte, length uint32, err error) {
+	payload, err := block.marshal()
+
+	if err != nil {
+		return
+	}
+
+	if len(payload) > maxBlockLength {
+		err = fmt.Errorf("block of type %d holding %d bytes: %w", block.header().Type, len(payload), ErrBlockTooLarge)
+
+		return
+	}
+
+	typeByte := byte(block.header().Type)
+
+	if last {
+		typeByte |= 0x80
+	}
+
+	length = uint32(len(payload))
+	encoded = append(data, typeByte)
+	encoded = appendUint24(encoded, length)
+	encoded = append(encoded, payload...)
+
+	return
+}
+
+// marshalBinary encodes a single block with its header, keeping its last-block flag.
+func marshalBinary(block IFLACMetadataBlock) (data []byte, err error) {
+	data, _, err = appendBlock(nil, block, block.isLast())
+
+	return
+}
+
+// MarshalBinary encodes the block as stored in a FLAC file, including its 4-byte header.
+func (block *FLACMetadataBlockStreamInfo) MarshalBinary() ([]byte, error) {
+	return marshalBinary(block)
+}
+
+// MarshalBinary encodes the block as stored in a FLAC file, including its 4-byte header.
+func (block *FLACMetadataBlockPadding) MarshalBinary() ([]byte, error) {
+	return marshalBinary(block)
+}
+
+// MarshalBinary encodes the block as stored in a FLAC file, including its 4-byte header.
+func (block *FLACMetadataBlockApplication) MarshalBinary() ([]byte, error) {
+	return marshalBinary(block)
+}
+
+// MarshalBinary encodes the block as stored in a FLAC file, including its 4-byte header.
+func (block *FLACMetadataBlockSeekTable) MarshalBinary() ([]byte, error) {
+	return marshalBinary(block)
+}
+
+// MarshalBinary encodes the block as stored in a FLAC file, including its 4-byte header.
+func (block *FLACMetadataBlockVorbisComment) MarshalBinary() ([]byte, error) {
+	return marshalBinary(block)
+}
+
+// MarshalBinary encodes the block as stored in a FLAC file, including its 4-byte header.
+func (block *FLACMetadataBlockCueSheet) MarshalBinary() ([]byte, error) {
+	return marshalBinary(block)
+}
+
+// MarshalBinary encodes the block as stored in a FLAC file, including its 4-byte header.
+func (block *FLACMetadataBlockPicture) MarshalBinary() ([]byte, error) {
+	return marshalBinary(block)
+}
+
+// MarshalBinary encodes the block as stored in a FLAC file, including its 4-byte header.
+func (block *FLACMetadataBlockReserved) MarshalBinary() ([]byte, error) {
+	return marshalBinary(block)
+}
+
+// MarshalBinary encodes the block as stored in a FLAC file, including its 4-byte header.
+func (block *FLACMetadataBlockSkipped) MarshalBinary() ([]byte, error) {
+	return marshalBinary(block)
+}
+
+// encodeMetadata serializes blocks, headers included, in order, marking the final block as the last.
+// Blocks that break the structural rules checked by layoutViolations are refused with a ValidationError.
+func encodeMetadata(blocks []IFLACMetadataBlock) (data []byte, lengths []uint32, err error) {
+	for index, block := range blocks {
+		switch {
+			case block == nil:
+				err = fmt.Errorf("metadata block %d is nil", index)
+
+			case block.header().Type == Invalid:
+				err = fmt.Errorf("metadata block %d: %w", index, ErrInvalidBlockType)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	if violations := layoutViolations(blocks); len(violations) > 0 {
+		err = &ValidationError{
+			Violations: violations,
+		}
+
+		return
+	}
+
+	for index, block := range blocks {
+		var length uint32
+
+		data, length, err = appendBlock(data, block, index == len(blocks) - 1)
+
+		if err != nil {
+			return
+		}
+
+		lengths = append(lengths, length)
+	}
+
+	return
+}
+
+// updateHeaders records the layout blocks were written with, starting at offset.
+func updateHeaders(blocks []IFLACMetadataBlock, lengths []uint32, offset int64) {
+	for index, block := range blocks {
+		header := block.header()
+
+		if picture, ok := block.(*FLACMetadataBlockPicture); ok && !picture.Loaded() {
+			picture.PictureOffset = offset + 4 + 32 + int64(len(picture.MIMEType) + len(picture.Description))
+		}
+
+		if comments, ok := block.(*FLACMetadataBlockVorbisComment); ok {
+			comments.VendorString = comments.writtenVendor()
+		}
+
+		header.Last = index == len(blocks) - 1
+		header.DataLength = lengths[index]
+		header.HeaderOffset = offset
+		header.Offset = offset + 4
+		offset += 4 + int64(lengths[index])
+	}
+}
+
+// defaultPadding is the padding left by Save when it has to rewrite a file and no target padding is set.
+const defaultPadding = 8192
+
+// withPadding returns the stream info and non-padding blocks followed by a single new padding block of length
+// bytes. A negative length leaves out padding altogether.
+func (flac *FLAC) withPadding(length int64) (blocks []IFLACMetadataBlock) {
+	blocks = []IFLACMetadataBlock{flac.StreamInfo}
+
+	for _, block := range flac.MetadataBlocks {
+		if block.header().Type != Padding {
+			blocks = append(blocks, block)
+		}
+	}
+
+	if length >= 0 {
+		padding := NewPadding(uint32(length))
+		padding.FLAC = flac
+		blocks = append(blocks, padding)
+	}
+
+	return
+}
+
+// prepareTags sanitizes and normalizes the vorbis comments when saving with WithSanitizeTags or WithNormalizeTags.
+func (flac *FLAC) prepareTags(options *saveOptions) {
+	if !options.sanitizeTags && options.normalization == nil {
+		return
+	}
+
+	for block := range flac.BlocksOfType(VorbisComment) {
+		comments, ok := block.(*FLACMetadataBlockVorbisComment)
+
+		if !ok {
+			continue
+		}
+
+		if options.sanitizeTags {
+			comments.SanitizeTags()
+		}
+
+		if options.normalization != nil {
+			comments.NormalizeTags(*options.normalization)
+		}
+	}
+}
+
+// rewriteLayout returns the blocks to write when the whole file is written.
+func (flac *FLAC) rewriteLayout(options *saveOptions) []IFLACMetadataBlock {
+	if options.targetPadding >= 0 && !options.dontUsePadding {
+		return flac.withPadding(options.targetPadding)
+	}
+
+	return slices.Collect(flac.Blocks())
+}
+
+// inPlaceLayout returns the blocks and their encoding for writing over the original metadata, or nil data
+// when they do not fit in the space it takes.
+func (flac *FLAC) inPlaceLayout(options *saveOptions) (blocks []IFLACMetadataBlock, data []byte, lengths []uint32, err error) {
+	space := flac.AudioOffset - int64(len(FLACMarker))
+
+	if options.dontUsePadding {
+		blocks = slices.Collect(flac.Blocks())
+		data, lengths, err = encodeMetadata(blocks)
+
+		if int64(len(data)) != space {
+			data = nil
+		}
+
+		return
+	}
+
+	data, _, err = encodeMetadata(flac.withPadding(-1))
+
+	if err != nil {
+		return
+	}
+
+	available := space - int64(len(data))
+
+	if available < 0 || (available > 0 && available < 4) || available - 4 > maxBlockLength {
+		data = nil
+
+		return
+	}
+
+	blocks = flac.withPadding(available - 4)
+
+	if available == 0 {
+		blocks = flac.withPadding(-1)
+	}
+
+	data, lengths, err = encodeMetadata(blocks)
+
+	return
+}
+
+// Save writes the metadata back into the file it was parsed from. Unless WithoutPaddingReuse is given, padding
+// blocks are merged into a single padding block at the end, sized to absorb the change in metadata size, so the
+// audio data is not touched. When the metadata does not fit, the whole file is rewritten as by SaveAs with
+// the padding set by WithTargetPadding, 8192 bytes by default, or with WithInPlaceOnly ErrInsufficientPadding
+// is returned and the file left untouched.
+func (flac *FLAC) Save(options ...SaveOption) error {
+	return flac.save(newSaveOptions(options))
+}
+
+func (flac *FLAC) save(saveOptions *saveOptions) (err error) {
+	if flac.fsys != nil || flac.path == "" {
+		err = errors.New("FLAC was not parsed from a file that can be written")
+
+		return
+	}
+
+	if flac.blocksLeftOut > 0 {
+		err = ErrIncompleteMetadata
+
+		return
+	}
+
+	changed, err := flac.Changed()
+
+	if err != nil {
+		return
+	}
+
+	if changed {
+		err = errors.New("file has changed since it was parsed")
+
+		return
+	}
+
+	// A dry run reports on a copy so that sanitizing and normalizing tags leaves the caller's metadata alone.
+	if saveOptions.report != nil {
+		flac = flac.Clone()
+	}
+
+	if saveOptions.withoutFrameGap {
+		_, err = flac.DetectFrameGap()
+
+		if err != nil {
+			return
+		}
+	}
+
+	flac.prepareTags(saveOptions)
+	blocks, data, lengths, err := flac.inPlaceLayout(saveOptions)
+
+	if err != nil {
+		return
+	}
+
+	rewrite := data == nil || saveOptions.trailing > 0 || saveOptions.withoutFrameGap && flac.Gap != nil
+
+	if rewrite {
+		if saveOptions.targetPadding < 0 && !saveOptions.dontUsePadding {
+			saveOptions.targetPadding = defaultPadding
+		}
+
+		blocks = flac.rewriteLayout(saveOptions)
+		data, lengths, err = encodeMetadata(blocks)
+
+		if err != nil {
+			return
+		}
+	}
+
+	if saveOptions.report != nil {
+		err = flac.fillSaveReport(saveOptions, blocks, data, rewrite)
+
+		if err != nil {
+			return
+		}
+	}
+
+	if rewrite && saveOptions.inPlaceOnly {
+		err = ErrInsufficientPadding
+
+		return
+	}
+
+	if saveOptions.report != nil {
+		return
+	}
+
+	if rewrite {
+		err = flac.writeFile(flac.path, blocks, data, lengths, saveOptions)
+
+		return
+	}
+
+	if saveOptions.backupSuffix != "" {
+		err = backup(flac.path, saveOptions.backupSuffix)
+
+		if err != nil {
+			return
+		}
+	}
+
+	file, err := os.OpenFile(flac.path, os.O_RDWR, 0)
+
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	_, err = file.WriteAt(data, int64(len(FLACMarker)))
+
+	if err != nil {
+		return
+	}
+
+	err = file.Sync()
+
+	if err != nil {
+		return
+	}
+
+	updateHeaders(blocks, lengths, int64(len(FLACMarker)))
+	flac.StreamInfo = blocks[0].(*FLACMetadataBlockStreamInfo)
+	flac.MetadataBlocks = blocks[1:]
+	flac.size, flac.modTime, flac.quickHash, err = fileState(file, flac.quickHash != nil)
+
+	return
+}
+
+// audioSource returns the file the metadata was parsed from and the offset of its audio frames, including any
+// frame gap, along with the file to close when done, which is nil for the held file.
+func (flac *FLAC) audioSource() (source io.ReaderAt, offset int64, closer io.Closer, err error) {
+	if flac.path == "" {
+		err = errors.New("FLAC was not parsed from a file and has no audio to copy")
+
+		return
+	}
+
+	file := flac.file
+
+	if file == nil {
+		file, err = flac.open()
+
+		if err != nil {
+			return
+		}
+
+		closer = file
+	}
+
+	source, ok := file.(io.ReaderAt)
+
+	if !ok {
+		err = errors.New("audio source does not support random access")
+
+		if closer != nil {
+			closer.Close()
+		}
+
+		return
+	}
+
+	offset = flac.AudioOffset
+
+	return
+}
+
+// writeStream writes the marker, the encoded metadata and the audio frames of the source file to w,
+// feeding the audio frames to hash when it is not nil.
+func (flac *FLAC) writeStream(w io.Writer, metadata []byte, hash hash.Hash, options *saveOptions) (n int64, err error) {
+	source, offset, closer, err := flac.audioSource()
+
+	if err != nil {
+		return
+	}
+
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	if options.trailing > 0 {
+		source = io.NewSectionReader(source, 0, flac.size - options.trailing)
+	}
+
+	if options.withoutFrameGap && flac.Gap != nil {
+		offset += flac.Gap.Size
+	}
+
+	written, err := w.Write(append([]byte(FLACMarker), metadata...))
+	n = int64(written)
+
+	if err != nil {
+		return
+	}
+
+	copied, err := copyAudio(w, source, offset, hash)
+	n += copied
+
+	return
+}
+
+// WriteTo writes a complete FLAC stream to w: the marker, the metadata blocks as they stand, and the audio
+// frames copied from the file the metadata was parsed from without loading them into memory.
+// Any frame gap in the original is copied through.
+func (flac *FLAC) WriteTo(w io.Writer) (n int64, err error) {
+	data, _, err := encodeMetadata(slices.Collect(flac.Blocks()))
+
+	if err != nil {
+		return
+	}
+
+	n, err = flac.writeStream(w, data, nil, newSaveOptions(nil))
+
+	return
+}
+
+// SaveAs writes a complete FLAC file with the current metadata to path. The file is written to a temporary
+// file in the same directory, synced and renamed over path, so path never holds a partially written file.
+// Padding blocks are written as they stand unless WithTargetPadding is given. Saving over the file the metadata
+// was parsed from rewrites it and the FLAC then refers to the new file; otherwise it continues to refer to its original.
+func (flac *FLAC) SaveAs(path string, options ...SaveOption) error {
+	return flac.saveAs(path, newSaveOptions(options))
+}
+
+func (flac *FLAC) saveAs(path string, options *saveOptions) (err error) {
+	if flac.blocksLeftOut > 0 {
+		err = ErrIncompleteMetadata
+
+		return
+	}
+
+	if options.withoutFrameGap {
+		_, err = flac.DetectFrameGap()
+
+		if err != nil {
+			return
+		}
+	}
+
+	flac.prepareTags(options)
+	blocks := flac.rewriteLayout(options)
+	data, lengths, err := encodeMetadata(blocks)
+
+	if err != nil {
+		return
+	}
+
+	return flac.writeFile(path, blocks, data, lengths, options)
+}
+
+// writeFile writes the marker, the encoded metadata and the audio frames to path through a temporary file.
+func (flac *FLAC) writeFile(path string, blocks []IFLACMetadataBlock, data []byte, lengths []uint32, options *saveOptions) (err error) {
+	var source, target fs.FileInfo
+
+	if flac.path != "" && flac.fsys == nil {
+		source, _ = os.Stat(flac.path)
+	}
+
+	target, _ = os.Stat(path)
+	exists := target != nil
+	rewrite := source != nil && exists && os.SameFile(source, target)
+
+	if target == nil {
+		target = source
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "." + filepath.Base(path) + ".*.tmp")
+
+	if err != nil {
+		return
+	}
+
+	var written, copied hash.Hash
+
+	if options.verifyAudio {
+		written, copied = md5.New(), md5.New()
+	}
+
+	_, err = flac.writeStream(temp, data, written, options)
+
+	if err == nil {
+		err = temp.Sync()
+	}
+
+	if err == nil && options.verifyAudio {
+		_, err = copyAudio(io.Discard, temp, int64(len(FLACMarker) + len(data)), copied)
+
+		if err == nil && !bytes.Equal(written.Sum(nil), copied.Sum(nil)) {
+			err = errors.New("audio data written does not match the original")
+		}
+	}
+
+	closeErr := temp.Close()
+
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = applyAttributes(temp.Name(), target, options)
+	}
+
+	if err == nil && exists && options.backupSuffix != "" {
+		err = backup(path, options.backupSuffix)
+	}
+
+	// Windows cannot replace a file this process holds open, so the handle is given up first and restored
+	// if the replace fails.
+	held := rewrite && flac.file != nil
+
+	if err == nil && held {
+		flac.Close()
+	}
+
+	if err == nil {
+		err = replaceFile(temp.Name(), path)
+	}
+
+	if err != nil {
+		os.Remove(temp.Name())
+
+		if held && flac.file == nil {
+			if file, openErr := os.Open(path); openErr == nil {
+				flac.file = file
+			}
+		}
+
+		return
+	}
+
+	syncDir(filepath.Dir(path))
+
+	if !rewrite {
+		return
+	}
+
+	updateHeaders(blocks, lengths, int64(len(FLACMarker)))
+
+	flac.StreamInfo = blocks[0].(*FLACMetadataBlockStreamInfo)
+	flac.MetadataBlocks = blocks[1:]
+
+	flac.AudioOffset = int64(len(FLACMarker) + len(data))
+
+	if options.withoutFrameGap {
+		flac.Gap = nil
+	} else if flac.Gap != nil {
+		flac.Gap.Offset = flac.AudioOffset
+	}
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return
+	}
+
+	flac.size, flac.modTime, flac.quickHash, err = fileState(file, flac.quickHash != nil)
+
+	if held && err == nil {
+		flac.file = file
+	} else {
+		file.Close()
+	}
+
+	return
+}
+
+// SaveReport describes what Save would do, as filled in by a save with WithDryRun. Rewrite reports whether the
+// metadata does not fit in place so the whole file would be rewritten. Blocks lists the types of the blocks in the
+// order they would be written and Changed indexes those whose encoding differs from the bytes at their position
+// in the file. Padding is the total padding data length, MetadataLength the length of the encoded blocks and
+// Size the resulting file size.
+type SaveReport struct {
+	Rewrite bool
+	Blocks []BlockType
+	Changed []int
+	Padding int64
+	MetadataLength int64
+	Size int64
+}
+
+// fillSaveReport describes writing the encoded blocks to the file the metadata was parsed from.
+func (flac *FLAC) fillSaveReport(options *saveOptions, blocks []IFLACMetadataBlock, data []byte, rewrite bool) (err error) {
+	report := options.report
+	file, err := os.Open(flac.path)
+
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	original := make([]byte, flac.AudioOffset - int64(len(FLACMarker)))
+	_, err = file.ReadAt(original, int64(len(FLACMarker)))
+
+	if err != nil {
+		return
+	}
+
+	*report = SaveReport{
+		Rewrite: rewrite,
+		MetadataLength: int64(len(data)),
+		Size: flac.size,
+	}
+
+	if rewrite {
+		audio := flac.size - flac.AudioOffset - options.trailing
+
+		if options.withoutFrameGap && flac.Gap != nil {
+			audio -= flac.Gap.Size
+		}
+
+		report.Size = int64(len(FLACMarker) + len(data)) + audio
+	}
+
+	offset := 0
+
+	for index, block := range blocks {
+		length := 4 + int(binary.BigEndian.Uint32(data[offset:]) & 0xffffff)
+		end := offset + length
+
+		report.Blocks = append(report.Blocks, block.header().Type)
+
+		if block.header().Type == Padding {
+			report.Padding += int64(length - 4)
+		}
+
+		if end > len(original) || !bytes.Equal(data[offset:end], original[offset:end]) {
+			report.Changed = append(report.Changed, index)
+		}
+
+		offset = end
+	}
+
+	return
+}
+
+// applyAttributes gives the file at path a default mode, or with WithPreserveAttributes the mode, owner
+// and modification time of info where it is known.
+func applyAttributes(path string, info fs.FileInfo, options *saveOptions) (err error) {
+	if !options.preserveAttributes || info == nil {
+		return os.Chmod(path, 0644)
+	}
+
+	err = os.Chmod(path, info.Mode().Perm())
+
+	if err != nil {
+		return
+	}
+
+	err = preserveOwner(path, info)
+
+	if err != nil {
+		return
+	}
+
+	return os.Chtimes(path, time.Time{}, info.ModTime())
+}
+
+// backup copies the file at path to path with suffix appended, keeping its mode and modification time.
+// The copy is written to a temporary file first so an existing backup is only replaced by a complete one.
+func backup(path string, suffix string) (err error) {
+	source, err := os.Open(path)
+
+	if err != nil {
+		return
+	}
+
+	defer source.Close()
+
+	info, err := source.Stat()
+
+	if err != nil {
+		return
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "." + filepath.Base(path) + suffix + ".*.tmp")
+
+	if err != nil {
+		return
+	}
+
+	_, err = io.Copy(temp, source)
+
+	if err == nil {
+		err = temp.Sync()
+	}
+
+	closeErr := temp.Close()
+
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = applyAttributes(temp.Name(), info, &saveOptions{preserveAttributes: true})
+	}
+
+	if err == nil {
+		err = replaceFile(temp.Name(), path + suffix)
+	}
+
+	if err != nil {
+		os.Remove(temp.Name())
+	}
+
+	return
+}
+
+// syncDir makes a rename in dir durable where the platform allows directories to be synced.
+func syncDir(dir string) {
+	handle, err := os.Open(dir)
+
+	if err != nil {
+		return
+	}
+
+	handle.Sync()
+	handle.Close()
+}
@@ -0,0 +1,443 @@
+package flac
+
+import (
+	"io"
+	"os"
+	"io/fs"
+	"bytes"
+	"time"
+	"slices"
+	"encoding"
+	"path/filepath"
+)
+
+func (suite *FLACTestSuite) copySample() (path string, data []byte) {
+	data, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	path = filepath.Join(suite.T().TempDir(), "sample.flac")
+
+	suite.assert.NoError(os.WriteFile(path, data, 0644))
+
+	return
+}
+
+func (suite *FLACTestSuite) TestEncodeMetadata() {
+	original, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	data, lengths, err := encodeMetadata(slices.Collect(suite.flac.Blocks()))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(1 + len(suite.flac.MetadataBlocks), len(lengths))
+	suite.assert.True(bytes.Equal(original[4:suite.flac.AudioOffset], data))
+}
+
+func (suite *FLACTestSuite) TestEncodeMetadataHeaders() {
+	flac := suite.flac.Clone()
+	comments := flac.VorbisComment()
+	comments.SetTag("TITLE", "Song")
+
+	// Stale lengths and last flags are recomputed rather than trusted.
+	comments.Last = true
+	flac.MetadataBlocks[len(flac.MetadataBlocks) - 1].header().Last = false
+
+	data, lengths, err := encodeMetadata(slices.Collect(flac.Blocks()))
+
+	suite.assert.NoError(err)
+
+	parsed, err := ParseReader(bytes.NewReader(append([]byte(FLACMarker), data...)))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(56 + len("TITLE=Song") + 4, lengths[3])
+	suite.assert.Equal(lengths[3], parsed.VorbisComment().DataLength)
+	suite.assert.False(parsed.VorbisComment().Last)
+	suite.assert.True(parsed.MetadataBlocks[len(parsed.MetadataBlocks) - 1].isLast())
+
+	_, _, err = encodeMetadata([]IFLACMetadataBlock{flac.StreamInfo, NewPadding(maxBlockLength + 1)})
+
+	suite.assert.ErrorIs(err, ErrBlockTooLarge)
+
+	_, _, err = encodeMetadata([]IFLACMetadataBlock{NewPadding(0)})
+
+	suite.assert.Error(err)
+
+	_, _, err = encodeMetadata([]IFLACMetadataBlock{flac.StreamInfo, flac.StreamInfo})
+
+	suite.assert.Error(err)
+}
+
+func (suite *FLACTestSuite) TestSave() {
+	path, original := suite.copySample()
+	flac, err := Parse(path, WithLazyPictures())
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	flac.VorbisComment().SetTag("TITLE", "Song")
+
+	suite.assert.NoError(flac.Save())
+
+	data, err := os.ReadFile(path)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(len(original), len(data))
+	suite.assert.True(bytes.Equal(original[flac.AudioOffset:], data[flac.AudioOffset:]))
+
+	changed, err := flac.Changed()
+
+	suite.assert.NoError(err)
+	suite.assert.False(changed)
+
+	saved, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer saved.Close()
+
+	suite.assert.Equal([]string{"Song"}, saved.VorbisComment().GetTag("TITLE"))
+	suite.assert.Equal(suite.flac.AudioOffset, saved.AudioOffset)
+	suite.assert.Equal(suite.flac.FrontCover().PictureMD5, saved.FrontCover().PictureMD5)
+	suite.assert.Equal(7596 - len("TITLE=Song") - 4, slices.Collect(saved.BlocksOfType(Padding))[0].header().DataLength)
+
+	picture, err := io.ReadAll(flac.FrontCover().Open())
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(suite.flac.FrontCover().PictureMD5, pictureMD5(picture))
+
+	flac.VorbisComment().SetTag("LYRICS", string(make([]byte, 8000)))
+
+	suite.assert.ErrorIs(flac.Save(WithInPlaceOnly()), ErrInsufficientPadding)
+
+	unchanged, err := os.ReadFile(path)
+
+	suite.assert.NoError(err)
+	suite.assert.True(bytes.Equal(data, unchanged))
+}
+
+func (suite *FLACTestSuite) TestSaveAs() {
+	path, original := suite.copySample()
+	flac, err := Parse(path, WithLazyPictures())
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	flac.VorbisComment().SetTag("LYRICS", string(bytes.Repeat([]byte("la "), 4000)))
+
+	suite.assert.ErrorIs(flac.Save(WithInPlaceOnly()), ErrInsufficientPadding)
+
+	target := filepath.Join(filepath.Dir(path), "saved.flac")
+
+	suite.assert.NoError(flac.SaveAs(target))
+
+	saved, err := Parse(target)
+
+	suite.assert.NoError(err)
+
+	defer saved.Close()
+
+	data, err := os.ReadFile(target)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(12000, len(saved.VorbisComment().GetTag("LYRICS")[0]))
+	suite.assert.Equal(suite.flac.FrontCover().PictureMD5, saved.FrontCover().PictureMD5)
+	suite.assert.True(bytes.Equal(original[flac.AudioOffset:], data[saved.AudioOffset:]))
+
+	// Saving over the source rewrites it and leaves the FLAC referring to the new file.
+	suite.assert.NoError(flac.SaveAs(path))
+
+	rewritten, err := os.ReadFile(path)
+
+	suite.assert.NoError(err)
+	suite.assert.True(bytes.Equal(data, rewritten))
+	suite.assert.Equal(saved.AudioOffset, flac.AudioOffset)
+
+	changed, err := flac.Changed()
+
+	suite.assert.NoError(err)
+	suite.assert.False(changed)
+
+	picture, err := io.ReadAll(flac.FrontCover().Open())
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(suite.flac.FrontCover().PictureMD5, pictureMD5(picture))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(2, len(entries))
+
+	var buffer bytes.Buffer
+
+	n, err := suite.flac.WriteTo(&buffer)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(len(original), n)
+	suite.assert.True(bytes.Equal(original, buffer.Bytes()))
+
+	reader, err := ParseReader(bytes.NewReader(original))
+
+	suite.assert.NoError(err)
+
+	_, err = reader.WriteTo(&buffer)
+
+	suite.assert.Error(err)
+}
+
+func (suite *FLACTestSuite) TestSavePadding() {
+	path, original := suite.copySample()
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	// Without padding reuse an unchanged size is still written in place.
+	suite.assert.NoError(flac.Save(WithoutPaddingReuse()))
+	suite.assert.Equal(suite.flac.AudioOffset, flac.AudioOffset)
+
+	flac.AppendBlock(NewPadding(100))
+	flac.VorbisComment().SetTag("LYRICS", string(bytes.Repeat([]byte("la "), 4000)))
+
+	suite.assert.NoError(flac.Save(WithTargetPadding(1000), WithVerifyAudio()))
+
+	saved, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer saved.Close()
+
+	padding := slices.Collect(saved.BlocksOfType(Padding))
+
+	suite.assert.Equal(1, len(padding))
+	suite.assert.Equal(1000, padding[0].header().DataLength)
+	suite.assert.True(padding[0].isLast())
+	suite.assert.Equal(flac.AudioOffset, saved.AudioOffset)
+
+	data, err := os.ReadFile(path)
+
+	suite.assert.NoError(err)
+	suite.assert.True(bytes.Equal(original[suite.flac.AudioOffset:], data[saved.AudioOffset:]))
+
+	// Shrinking the metadata without padding reuse rewrites the file rather than growing the padding.
+	flac.VorbisComment().RemoveTag("LYRICS")
+
+	suite.assert.NoError(flac.Save(WithoutPaddingReuse()))
+	suite.assert.Equal(saved.AudioOffset - int64(12000 + len("LYRICS=") + 4), flac.AudioOffset)
+}
+
+func (suite *FLACTestSuite) TestWriteReserved() {
+	flac := suite.flac.Clone()
+	reserved, err := UnmarshalBlock([]byte{9, 0, 0, 3, 1, 2, 3})
+
+	suite.assert.NoError(err)
+	suite.assert.NoError(flac.InsertBlock(0, reserved))
+
+	var buffer bytes.Buffer
+
+	_, err = flac.WriteTo(&buffer)
+
+	suite.assert.NoError(err)
+
+	written, err := ParseReader(bytes.NewReader(buffer.Bytes()))
+
+	suite.assert.NoError(err)
+	suite.assert.IsType(&FLACMetadataBlockReserved{}, written.MetadataBlocks[0])
+	suite.assert.Equal(9, written.MetadataBlocks[0].header().Type)
+	suite.assert.Equal([]byte{1, 2, 3}, written.MetadataBlocks[0].(*FLACMetadataBlockReserved).Data)
+	suite.assert.Equal(len(suite.flac.MetadataBlocks) + 1, len(written.MetadataBlocks))
+}
+
+func (suite *FLACTestSuite) TestSaveDryRun() {
+	path, original := suite.copySample()
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	var report SaveReport
+
+	flac.VorbisComment().SetTag("TITLE", "Song")
+
+	suite.assert.NoError(flac.Save(WithDryRun(&report)))
+	suite.assert.False(report.Rewrite)
+	suite.assert.Equal([]BlockType{StreamInfo, SeekTable, Application, VorbisComment, Picture, CueSheet, Padding}, report.Blocks)
+	suite.assert.Equal([]int{3, 4, 5, 6}, report.Changed)
+	suite.assert.Equal(7596 - len("TITLE=Song") - 4, report.Padding)
+	suite.assert.Equal(suite.flac.AudioOffset - 4, report.MetadataLength)
+	suite.assert.Equal(len(original), report.Size)
+
+	flac.VorbisComment().SetTag("LYRICS", string(bytes.Repeat([]byte("la "), 4000)))
+
+	suite.assert.NoError(flac.Save(WithDryRun(&report)))
+	suite.assert.True(report.Rewrite)
+	suite.assert.Equal(defaultPadding, report.Padding)
+	suite.assert.Equal(report.MetadataLength + 4 + int64(len(original)) - suite.flac.AudioOffset, report.Size)
+	suite.assert.ErrorIs(flac.Save(WithDryRun(&report), WithInPlaceOnly()), ErrInsufficientPadding)
+
+	flac.VorbisComment().AddTag("BAD KEY", "value")
+
+	suite.assert.NoError(flac.Save(WithDryRun(&report), WithSanitizeTags()))
+	suite.assert.Equal([]string{"value"}, flac.VorbisComment().GetTag("BAD KEY"))
+
+	data, err := os.ReadFile(path)
+
+	suite.assert.NoError(err)
+	suite.assert.True(bytes.Equal(original, data))
+	suite.assert.Equal(7596, slices.Collect(flac.BlocksOfType(Padding))[0].header().DataLength)
+}
+
+func (suite *FLACTestSuite) TestSaveBackup() {
+	path, original := suite.copySample()
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	flac.VorbisComment().SetTag("TITLE", "Song")
+
+	suite.assert.NoError(flac.Save(WithBackup(".bak")))
+
+	backup, err := os.ReadFile(path + ".bak")
+
+	suite.assert.NoError(err)
+	suite.assert.True(bytes.Equal(original, backup))
+
+	saved, err := os.ReadFile(path)
+
+	suite.assert.NoError(err)
+
+	flac.VorbisComment().SetTag("LYRICS", string(bytes.Repeat([]byte("la "), 4000)))
+
+	suite.assert.NoError(flac.Save(WithBackup(".bak")))
+
+	backup, err = os.ReadFile(path + ".bak")
+
+	suite.assert.NoError(err)
+	suite.assert.True(bytes.Equal(saved, backup))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(2, len(entries))
+}
+
+func (suite *FLACTestSuite) TestSaveAsPreserveAttributes() {
+	path, _ := suite.copySample()
+	modTime := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+
+	suite.assert.NoError(os.Chmod(path, 0640))
+	suite.assert.NoError(os.Chtimes(path, modTime, modTime))
+
+	flac, err := Parse(path)
+
+	suite.assert.NoError(err)
+
+	defer flac.Close()
+
+	suite.assert.NoError(flac.SaveAs(path, WithPreserveAttributes()))
+
+	info, err := os.Stat(path)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(os.FileMode(0640), info.Mode().Perm())
+	suite.assert.True(info.ModTime().Equal(modTime))
+
+	suite.assert.NoError(flac.SaveAs(path))
+
+	info, err = os.Stat(path)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal(os.FileMode(0644), info.Mode().Perm())
+}
+
+func (suite *FLACTestSuite) TestMarshalBinary() {
+	original, err := os.ReadFile("sample.flac")
+
+	suite.assert.NoError(err)
+
+	for block := range suite.flac.Blocks() {
+		header := block.header()
+		data, err := block.(encoding.BinaryMarshaler).MarshalBinary()
+
+		suite.assert.NoError(err)
+		suite.assert.True(bytes.Equal(original[header.HeaderOffset:header.Offset + int64(header.DataLength)], data))
+	}
+}
+
+func (suite *FLACTestSuite) TestMarshalStreamInfoRanges() {
+	edits := []func(*FLACMetadataBlockStreamInfo){
+		func(block *FLACMetadataBlockStreamInfo) { block.SampleRate = 1 << 20 },
+		func(block *FLACMetadataBlockStreamInfo) { block.Channels = 0 },
+		func(block *FLACMetadataBlockStreamInfo) { block.Channels = 9 },
+		func(block *FLACMetadataBlockStreamInfo) { block.BitsPerSample = 0 },
+		func(block *FLACMetadataBlockStreamInfo) { block.BitsPerSample = 3 },
+		func(block *FLACMetadataBlockStreamInfo) { block.BitsPerSample = 33 },
+		func(block *FLACMetadataBlockStreamInfo) { block.NumSamples = 1 << 36 },
+	}
+
+	for _, edit := range edits {
+		block := suite.flac.StreamInfo.clone(nil).(*FLACMetadataBlockStreamInfo)
+
+		edit(block)
+
+		_, err := block.MarshalBinary()
+
+		suite.assert.Error(err)
+	}
+
+	block := suite.flac.StreamInfo.clone(nil).(*FLACMetadataBlockStreamInfo)
+	block.Channels = 8
+	block.BitsPerSample = 32
+	block.SampleRate = 1 << 20 - 1
+	data, err := block.MarshalBinary()
+
+	suite.assert.NoError(err)
+
+	decoded := &FLACMetadataBlockStreamInfo{}
+
+	suite.assert.NoError(decoded.UnmarshalBinary(data))
+	suite.assert.Equal([]uint64{8, 32, 1 << 20 - 1}, []uint64{uint64(decoded.Channels), uint64(decoded.BitsPerSample), uint64(decoded.SampleRate)})
+}
+
+func (suite *FLACTestSuite) TestReplaceFile() {
+	dir := suite.T().TempDir()
+	source := filepath.Join(dir, "source")
+	target := filepath.Join(dir, "target")
+
+	suite.assert.NoError(os.WriteFile(source, []byte("new"), 0644))
+	suite.assert.NoError(os.WriteFile(target, []byte("old"), 0644))
+
+	held, err := os.Open(target)
+
+	suite.assert.NoError(err)
+
+	defer held.Close()
+
+	suite.assert.NoError(replaceFile(source, target))
+
+	data, err := os.ReadFile(target)
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("new", string(data))
+
+	_, err = os.Stat(source)
+
+	suite.assert.ErrorIs(err, fs.ErrNotExist)
+
+	// A missing target is created.
+	suite.assert.NoError(os.WriteFile(source, []byte("created"), 0644))
+	suite.assert.NoError(replaceFile(source, filepath.Join(dir, "missing")))
+
+	data, err = os.ReadFile(filepath.Join(dir, "missing"))
+
+	suite.assert.NoError(err)
+	suite.assert.Equal("created", string(data))
+	suite.assert.Error(replaceFile(source, target))
+}